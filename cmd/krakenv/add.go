@@ -12,18 +12,30 @@ import (
 )
 
 var (
-	addType     string
-	addPrompt   string
-	addDefault  string
-	addMin      string
-	addMax      string
-	addMinlen   string
-	addMaxlen   string
-	addPattern  string
-	addOptions  string
-	addFormat   string
-	addOptional bool
-	addSecret   bool
+	addType      string
+	addPrompt    string
+	addDefault   string
+	addMin       string
+	addMax       string
+	addMinlen    string
+	addMaxlen    string
+	addPattern   string
+	addOptions   string
+	addFormat    string
+	addSchemes   string
+	addIPVersion string
+	addExists    bool
+	addKind      string
+	addAbs       bool
+	addAllowIP   bool
+	addEntropy   string
+	addLayout    string
+	addAfter     string
+	addBefore    string
+	addOptional  bool
+	addSecret    bool
+	addDryRun    bool
+	addSection   string
 )
 
 var addCmd = &cobra.Command{
@@ -39,7 +51,19 @@ Examples:
   krakenv add MAX_CONNECTIONS --type int --min 1 --max 100 --default 10
   krakenv add LOG_LEVEL --type enum --options "debug,info,warn,error" --default info
   krakenv add DB_PASSWORD --type string --prompt "Database password?" --secret
-  krakenv add ENABLE_METRICS --type boolean --optional --default false`,
+  krakenv add API_KEY --type string --secret --minlen 32 --entropy 128
+  krakenv add ENABLE_METRICS --type boolean --optional --default false
+  krakenv add API_BASE_URL --type url --schemes https
+  krakenv add ADMIN_EMAIL --type email
+  krakenv add BIND_ADDRESS --type ip --ipversion 4
+  krakenv add ALLOWED_CIDR --type cidr
+  krakenv add NODE_VERSION --type semver --min 18.0.0 --default 18.17.0
+  krakenv add TLS_CERT_PATH --type path --exists --kind file --abs
+  krakenv add SMTP_HOST --type hostname --allowip
+  krakenv add RELEASE_DATE --type date --after 2024-01-01
+  krakenv add CUTOFF --type date --layout 02/01/2006
+  krakenv add API_URL --type url --dry-run
+  krakenv add DB_POOL_SIZE --type int --section Database`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAdd,
 }
@@ -48,29 +72,53 @@ var variableNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
 
 func init() {
 	addCmd.Flags().StringVarP(&addType, "type", "t", "string",
-		"Variable type (string, int, numeric, boolean, enum, object)")
+		"Variable type (string, int, numeric, boolean, enum, object, url, email, duration, ip, cidr, semver, path, hostname, date)")
 	addCmd.Flags().StringVarP(&addPrompt, "prompt", "p", "",
 		"Prompt message for the wizard")
 	addCmd.Flags().StringVarP(&addDefault, "default", "D", "",
 		"Default value")
 	addCmd.Flags().StringVar(&addMin, "min", "",
-		"Minimum value (int/numeric)")
+		"Minimum value (int/numeric/duration/semver)")
 	addCmd.Flags().StringVar(&addMax, "max", "",
-		"Maximum value (int/numeric)")
+		"Maximum value (int/numeric/duration/semver)")
 	addCmd.Flags().StringVar(&addMinlen, "minlen", "",
 		"Minimum length (string)")
 	addCmd.Flags().StringVar(&addMaxlen, "maxlen", "",
 		"Maximum length (string)")
 	addCmd.Flags().StringVar(&addPattern, "pattern", "",
 		"Regex pattern (string)")
+	addCmd.Flags().StringVar(&addEntropy, "entropy", "",
+		"Minimum estimated strength in bits (string)")
 	addCmd.Flags().StringVarP(&addOptions, "options", "o", "",
 		"Comma-separated options (enum)")
 	addCmd.Flags().StringVar(&addFormat, "format", "",
 		"Object format: json or yaml")
+	addCmd.Flags().StringVar(&addSchemes, "schemes", "",
+		"Comma-separated allowed URL schemes (url)")
+	addCmd.Flags().StringVar(&addIPVersion, "ipversion", "",
+		"Allowed IP versions: 4, 6 or 4,6 (ip/cidr)")
+	addCmd.Flags().BoolVar(&addExists, "exists", false,
+		"Require the path to exist when validating (path)")
+	addCmd.Flags().StringVar(&addKind, "kind", "",
+		"Required kind of an existing path: file or dir (path)")
+	addCmd.Flags().BoolVar(&addAbs, "abs", false,
+		"Require an absolute path (path)")
+	addCmd.Flags().BoolVar(&addAllowIP, "allowip", false,
+		"Also accept IP addresses (hostname)")
+	addCmd.Flags().StringVar(&addLayout, "layout", "",
+		"Go time layout, default 2006-01-02 (date)")
+	addCmd.Flags().StringVar(&addAfter, "after", "",
+		"Earliest date, exclusive, in the layout (date)")
+	addCmd.Flags().StringVar(&addBefore, "before", "",
+		"Latest date, exclusive, in the layout (date)")
 	addCmd.Flags().BoolVar(&addOptional, "optional", false,
 		"Mark as optional")
 	addCmd.Flags().BoolVar(&addSecret, "secret", false,
 		"Mark as secret (hides input)")
+	addCmd.Flags().BoolVar(&addDryRun, "dry-run", false,
+		"Print the line that would be added without changing the distributable")
+	addCmd.Flags().StringVar(&addSection, "section", "",
+		"Insert after the variables under this '# <section>' comment instead of appending")
 
 	rootCmd.AddCommand(addCmd)
 }
@@ -89,7 +137,7 @@ func runAdd(_ *cobra.Command, args []string) error {
 	}
 
 	// Parse existing distributable
-	distFile, err := parser.ParseEnvFile(distPath)
+	distFile, err := loadDistFile(distPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse distributable: %w", err)
 	}
@@ -101,11 +149,30 @@ func runAdd(_ *cobra.Command, args []string) error {
 	}
 
 	// Build annotation
-	annotation := buildAnnotation()
+	annotation := buildAnnotation(addFlagsSpec())
 
 	// Build line
 	line := buildVariableLine(varName, annotation)
 
+	if addDryRun {
+		fmt.Println(line)
+		return nil
+	}
+
+	if addSection != "" {
+		inserted, err := insertInSection(line)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			if !quiet {
+				fmt.Printf("✓ Added to section %s: %s\n", addSection, line)
+			}
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: Section %q not found in %s; appending instead\n", addSection, distPath)
+	}
+
 	// Check if file ends with newline
 	needsNewline := false
 	if stat, err := os.Stat(distPath); err == nil && stat.Size() > 0 {
@@ -144,60 +211,169 @@ func runAdd(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func buildAnnotation() string {
+// insertInSection inserts line into the distributable's addSection block.
+// Reports false, leaving the file untouched, if the section does not exist.
+func insertInSection(line string) (bool, error) {
+	lines, err := readFileLines(distPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	idx, err := sectionInsertIndex(lines, addSection)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse file: %w", err)
+	}
+	if idx < 0 {
+		return false, nil
+	}
+
+	lines = append(lines[:idx], append([]string{line}, lines[idx:]...)...)
+	if err := writeFileLines(distPath, lines); err != nil {
+		return false, fmt.Errorf("failed to write: %w", err)
+	}
+	return true, nil
+}
+
+// annotationSpec holds the parts of an annotation, as given to add's flags
+// or the init wizard. Constraints that do not apply to the type are ignored.
+type annotationSpec struct {
+	varType   string
+	prompt    string
+	min       string
+	max       string
+	minlen    string
+	maxlen    string
+	pattern   string
+	entropy   string
+	options   string
+	format    string
+	schemes   string
+	ipVersion string
+	exists    bool
+	kind      string
+	abs       bool
+	allowIP   bool
+	layout    string
+	after     string
+	before    string
+	optional  bool
+	secret    bool
+}
+
+// addFlagsSpec collects the annotation given by add's flags.
+func addFlagsSpec() annotationSpec {
+	return annotationSpec{
+		varType:   addType,
+		prompt:    addPrompt,
+		min:       addMin,
+		max:       addMax,
+		minlen:    addMinlen,
+		maxlen:    addMaxlen,
+		pattern:   addPattern,
+		entropy:   addEntropy,
+		options:   addOptions,
+		format:    addFormat,
+		schemes:   addSchemes,
+		ipVersion: addIPVersion,
+		exists:    addExists,
+		kind:      addKind,
+		abs:       addAbs,
+		allowIP:   addAllowIP,
+		layout:    addLayout,
+		after:     addAfter,
+		before:    addBefore,
+		optional:  addOptional,
+		secret:    addSecret,
+	}
+}
+
+func buildAnnotation(spec annotationSpec) string {
 	// Default prompt if not provided
-	prompt := addPrompt
+	prompt := spec.prompt
 	if prompt == "" {
-		prompt = "Enter " + strings.ToLower(addType) + " value"
+		prompt = "Enter " + strings.ToLower(spec.varType) + " value"
 	}
 
 	// Build parts
-	parts := []string{addType}
+	parts := []string{spec.varType}
 
 	// Add constraints based on type
-	switch addType {
-	case "int", "numeric":
-		if addMin != "" {
-			parts = append(parts, "min:"+addMin)
+	switch spec.varType {
+	case "int", "numeric", "duration", "semver":
+		if spec.min != "" {
+			parts = append(parts, "min:"+spec.min)
 		}
-		if addMax != "" {
-			parts = append(parts, "max:"+addMax)
+		if spec.max != "" {
+			parts = append(parts, "max:"+spec.max)
 		}
 	case "string":
-		if addMinlen != "" {
-			parts = append(parts, "minlen:"+addMinlen)
+		if spec.minlen != "" {
+			parts = append(parts, "minlen:"+spec.minlen)
+		}
+		if spec.maxlen != "" {
+			parts = append(parts, "maxlen:"+spec.maxlen)
 		}
-		if addMaxlen != "" {
-			parts = append(parts, "maxlen:"+addMaxlen)
+		if spec.pattern != "" {
+			parts = append(parts, "pattern:"+spec.pattern)
 		}
-		if addPattern != "" {
-			parts = append(parts, "pattern:"+addPattern)
+		if spec.entropy != "" {
+			parts = append(parts, "entropy:"+spec.entropy)
 		}
 	case "enum":
-		if addOptions != "" {
-			parts = append(parts, "options:"+addOptions)
+		if spec.options != "" {
+			parts = append(parts, "options:"+spec.options)
 		}
 	case "object":
-		if addFormat != "" {
-			parts = append(parts, "format:"+addFormat)
+		if spec.format != "" {
+			parts = append(parts, "format:"+spec.format)
+		}
+	case "url":
+		if spec.schemes != "" {
+			parts = append(parts, "schemes:"+spec.schemes)
+		}
+	case "ip", "cidr":
+		if spec.ipVersion != "" {
+			parts = append(parts, "version:"+spec.ipVersion)
+		}
+	case "path":
+		if spec.exists {
+			parts = append(parts, "exists:true")
+		}
+		if spec.kind != "" {
+			parts = append(parts, "kind:"+spec.kind)
+		}
+		if spec.abs {
+			parts = append(parts, "abs:true")
+		}
+	case "hostname":
+		if spec.allowIP {
+			parts = append(parts, "allowip:true")
+		}
+	case "date":
+		if spec.layout != "" {
+			parts = append(parts, "layout:"+spec.layout)
+		}
+		if spec.after != "" {
+			parts = append(parts, "after:"+spec.after)
+		}
+		if spec.before != "" {
+			parts = append(parts, "before:"+spec.before)
 		}
 	}
 
 	// Add modifiers
-	if addOptional {
+	if spec.optional {
 		parts = append(parts, "optional")
 	}
-	if addSecret {
+	if spec.secret {
 		parts = append(parts, "secret")
 	}
 
 	return "#prompt:" + prompt + "|" + strings.Join(parts, ";")
 }
 
+// buildVariableLine formats the new variable the way fmt writes it, so an
+// empty default is always written as "NAME= #prompt:...".
 func buildVariableLine(name, annotation string) string {
-	line := name + "=" + addDefault
-	if annotation != "" {
-		line += " " + annotation
-	}
-	return line
+	return parser.FormatLine(name, addDefault, annotation)
 }
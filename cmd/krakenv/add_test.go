@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAdd_DryRun(t *testing.T) {
+	content := "# Server\nPORT=8080 #prompt:Port?|int\n"
+	path := writeTestFile(t, t.TempDir(), ".env.dist", content)
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &addType, "int")
+	setGlobal(t, &addPrompt, "Pool size?")
+	setGlobal(t, &addMin, "1")
+	setGlobal(t, &addDefault, "10")
+	setGlobal(t, &addDryRun, true)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runAdd(nil, []string{"DB_POOL_SIZE"}))
+	})
+	assert.Equal(t, "DB_POOL_SIZE=10 #prompt:Pool size?|int;min:1\n", out)
+	assert.Equal(t, content, readTestFile(t, path))
+}
+
+func TestRunAdd_DryRunInvalidName(t *testing.T) {
+	content := "PORT=8080\n"
+	path := writeTestFile(t, t.TempDir(), ".env.dist", content)
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &addDryRun, true)
+
+	assert.ErrorContains(t, runAdd(nil, []string{"db_pool"}), "invalid variable name")
+	assert.Equal(t, content, readTestFile(t, path))
+}
+
+func TestRunAdd_Section(t *testing.T) {
+	content := "# Server\nPORT=8080\n\n# === Database ===\nDB_HOST=db\nDB_PORT=5432 # primary\n\n# Cache\nCACHE_URL=\n"
+
+	tests := []struct {
+		name    string
+		section string
+		want    string
+	}{
+		{
+			name:    "after the section's last variable",
+			section: "database",
+			want:    "# Server\nPORT=8080\n\n# === Database ===\nDB_HOST=db\nDB_PORT=5432 # primary\nDB_POOL_SIZE= #prompt:Pool size?|int\n\n# Cache\nCACHE_URL=\n",
+		},
+		{
+			name:    "last section",
+			section: "Cache",
+			want:    content + "DB_POOL_SIZE= #prompt:Pool size?|int\n",
+		},
+		{
+			name:    "missing section appends",
+			section: "Queue",
+			want:    content + "DB_POOL_SIZE= #prompt:Pool size?|int\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, t.TempDir(), ".env.dist", content)
+			setGlobal(t, &distPath, path)
+			setGlobal(t, &addType, "int")
+			setGlobal(t, &addPrompt, "Pool size?")
+			setGlobal(t, &addSection, tt.section)
+			setGlobal(t, &quiet, true)
+
+			require.NoError(t, runAdd(nil, []string{"DB_POOL_SIZE"}))
+			assert.Equal(t, tt.want, readTestFile(t, path))
+		})
+	}
+}
+
+func TestRunAdd_SectionWithoutVariables(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist", "# Database\n\n# Server\nPORT=8080\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &addType, "string")
+	setGlobal(t, &addPrompt, "Host?")
+	setGlobal(t, &addSection, "Database")
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runAdd(nil, []string{"DB_HOST"}))
+	assert.Equal(t, "# Database\nDB_HOST= #prompt:Host?|string\n\n# Server\nPORT=8080\n", readTestFile(t, path))
+}
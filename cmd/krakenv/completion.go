@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Besides commands and
+flags, it completes environment files for generate, inspect and validate,
+and variable names from the distributable for add, remove, set and get.
+
+Setup:
+  bash:        source <(krakenv completion bash)
+  zsh:         krakenv completion zsh > "${fpath[1]}/_krakenv"
+  fish:        krakenv completion fish > ~/.config/fish/completions/krakenv.fish
+  powershell:  krakenv completion powershell | Out-String | Invoke-Expression
+
+Examples:
+  krakenv completion bash > /etc/bash_completion.d/krakenv
+  krakenv completion zsh > "${fpath[1]}/_krakenv"`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	for _, cmd := range []*cobra.Command{generateCmd, inspectCmd} {
+		cmd.ValidArgsFunction = completeEnvFiles(1)
+	}
+	validateCmd.ValidArgsFunction = completeEnvFiles(-1)
+
+	for _, cmd := range []*cobra.Command{addCmd, removeCmd, setCmd, getCmd} {
+		cmd.ValidArgsFunction = completeVariableNames
+	}
+}
+
+func runCompletion(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q (use: bash, zsh, fish, powershell)", args[0])
+	}
+}
+
+// completeEnvFiles suggests .env files in the current directory, other than
+// the distributables and files already given. maxArgs limits how many
+// arguments are completed; -1 means any number.
+func completeEnvFiles(maxArgs int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if err := resolveGlobalFlags(cmd, args); err != nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		dists := make([]string, len(distPaths))
+		for i, path := range distPaths {
+			dists[i] = filepath.Clean(path)
+		}
+
+		matches, _ := filepath.Glob(".env*")
+		var files []string
+		for _, m := range matches {
+			if slices.Contains(dists, m) || slices.Contains(args, m) {
+				continue
+			}
+			if info, err := os.Stat(m); err == nil && !info.IsDir() && strings.HasPrefix(m, toComplete) {
+				files = append(files, m)
+			}
+		}
+
+		// Fall back to the shell's file completion for other paths
+		return files, cobra.ShellCompDirectiveDefault
+	}
+}
+
+// completeVariableNames suggests the names of variables defined in the
+// distributable for a command's first argument.
+func completeVariableNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if err := resolveGlobalFlags(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	distFile, err := loadDistributable()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, v := range distFile.Variables {
+		if strings.HasPrefix(v.Name, strings.ToUpper(toComplete)) {
+			names = append(names, v.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var (
+	diffJSON    bool
+	diffSecrets bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <fileA> <fileB>",
+	Short: "Compare two environment files directly",
+	Long: `Compare two environment files by variable name and value, without
+involving the distributable.
+
+Reports variables only present in one of the files and variables whose
+values differ. Values of variables marked secret in the distributable, if
+there is one, are masked unless --show-secrets is given.
+
+Exit codes:
+  0 - Files are equivalent
+  1 - Differences found
+  2 - File not found or unreadable
+
+Examples:
+  krakenv diff .env.staging .env.production
+  krakenv diff .env.local .env.testing --json | jq '.changed'`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "j", false,
+		"Output as JSON (for scripting)")
+	diffCmd.Flags().BoolVar(&diffSecrets, "show-secrets", false,
+		"Print values of secret variables instead of masking them")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(_ *cobra.Command, args []string) error {
+	files := make([]*parser.EnvFile, 0, 2)
+	for _, path := range args {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", path)
+			os.Exit(2)
+		}
+
+		envFile, err := parser.ParseEnvFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", path, err)
+			os.Exit(2)
+		}
+		files = append(files, envFile)
+	}
+
+	result := inspector.Diff(files[0], files[1])
+	result.ShowSecrets = diffSecrets
+
+	// The distributable is optional here; it only tells which values are secret
+	if distFile, err := loadDistributable(); err == nil {
+		result.Secrets = inspector.SecretNames(distFile)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "WARNING: Failed to parse distributable %s, secret values are not masked: %v\n", distLabel(), err)
+	}
+
+	if diffJSON {
+		jsonOutput, err := result.FormatJSON()
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(jsonOutput)
+	} else if !quiet {
+		fmt.Print(result.FormatReport())
+	}
+
+	if result.HasDifferences() {
+		os.Exit(1)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/lint"
+)
+
+// defaultEditor is used when neither $VISUAL nor $EDITOR is set.
+const defaultEditor = "vi"
+
+var editCmd = &cobra.Command{
+	Use:   "edit [path]",
+	Short: "Open the distributable in $EDITOR and lint it after saving",
+	Long: `Open the distributable in your editor ($VISUAL, then $EDITOR, then vi).
+When the editor exits the file is parsed and linted, and any annotation
+problems are reported with an offer to reopen the editor and fix them.
+
+With --non-interactive the editor is not opened; the file is only linted.
+
+Exit codes:
+  0 - File saved without problems
+  1 - Problems remain in the file
+  2 - File not found or unreadable
+
+Examples:
+  krakenv edit
+  EDITOR="code --wait" krakenv edit config/.env.template
+  krakenv edit --non-interactive`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEdit(_ *cobra.Command, args []string) error {
+	path := distPath
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", path)
+		os.Exit(2)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		if !nonInteractive {
+			if err := openEditor(path); err != nil {
+				return err
+			}
+		}
+
+		envFile, err := loadDistFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", path, err)
+			os.Exit(2)
+		}
+
+		issues := lint.Lint(envFile)
+		if len(issues) == 0 {
+			if !quiet {
+				fmt.Printf("✓ LINT PASSED: %s\n", path)
+			}
+			return nil
+		}
+
+		if nonInteractive {
+			if !quiet {
+				printLintIssues(path, issues)
+			}
+			os.Exit(1)
+		}
+		printLintIssues(path, issues)
+
+		fmt.Print("\nReopen the editor to fix them? [Y/n]: ")
+		answer, err := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if err != nil || (answer != "" && answer != "y" && answer != "yes") {
+			os.Exit(1)
+		}
+	}
+}
+
+// openEditor runs the user's editor on path and waits for it to exit. The
+// editor setting may include arguments, such as "code --wait".
+func openEditor(path string) error {
+	fields := editorCommand()
+	editor := strings.Join(fields, " ")
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// editorCommand returns the editor from $VISUAL, then $EDITOR, split into the
+// program and its arguments. Unset or blank settings fall back to the
+// default editor.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{defaultEditor}
+}
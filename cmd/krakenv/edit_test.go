@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		visual string
+		editor string
+		want   []string
+	}{
+		{"default", "", "", []string{defaultEditor}},
+		{"editor", "", "nano", []string{"nano"}},
+		{"visual first", "code --wait", "nano", []string{"code", "--wait"}},
+		{"blank visual", "  ", "nano", []string{"nano"}},
+		{"blank editor", "", " \t", []string{defaultEditor}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			assert.Equal(t, tt.want, editorCommand())
+		})
+	}
+}
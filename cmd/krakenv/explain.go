@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+var explainJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <name>",
+	Short: "Describe how the distributable defines a variable",
+	Long: `Print everything the distributable says about a variable: its default,
+type, constraints, modifiers and prompt, along with the example value and
+suggestion shown when a value is invalid.
+
+Exit codes:
+  0 - Variable found
+  1 - Variable not defined in the distributable
+  2 - Distributable not found or unreadable
+
+Examples:
+  krakenv explain DB_PORT
+  krakenv explain API_KEY --json | jq '.constraints'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().BoolVarP(&explainJSON, "json", "j", false,
+		"Output as JSON (for scripting)")
+	explainCmd.ValidArgsFunction = completeVariableNames
+
+	rootCmd.AddCommand(explainCmd)
+}
+
+// explainConstraint is one constraint in the `explain --json` output.
+type explainConstraint struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// explainResult is the `explain --json` output. Annotation fields are left
+// out for variables without an annotation.
+type explainResult struct {
+	Name        string              `json:"name"`
+	Default     string              `json:"default"`
+	Line        int                 `json:"line"`
+	Annotated   bool                `json:"annotated"`
+	Annotation  string              `json:"annotation,omitempty"`
+	Type        string              `json:"type,omitempty"`
+	Like        string              `json:"like,omitempty"`
+	Prompt      string              `json:"prompt,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Constraints []explainConstraint `json:"constraints,omitempty"`
+	EnvDefaults map[string]string   `json:"envDefaults,omitempty"`
+	Optional    bool                `json:"optional"`
+	Secret      bool                `json:"secret"`
+	NoCase      bool                `json:"nocase,omitempty"`
+	Example     string              `json:"example,omitempty"`
+	Suggestion  string              `json:"suggestion,omitempty"`
+}
+
+func runExplain(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	distFile, err := loadDistributable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distLabel(), err)
+		os.Exit(2)
+	}
+
+	v := distFile.GetVariable(name)
+	if v == nil {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Variable %s not found in %s\n", name, distLabel())
+		}
+		os.Exit(1)
+	}
+
+	result := explainVariable(*v)
+	if explainJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(formatExplanation(result))
+	return nil
+}
+
+// explainVariable collects what the distributable defines for a variable.
+func explainVariable(v parser.Variable) explainResult {
+	result := explainResult{
+		Name:    v.Name,
+		Default: v.Value,
+		Line:    v.LineNumber,
+	}
+
+	ann := v.Annotation
+	if ann == nil {
+		return result
+	}
+
+	result.Annotated = true
+	result.Annotation = parser.FormatAnnotation(ann)
+	result.Type = ann.Type.String()
+	result.Like = ann.Like
+	result.Prompt = ann.PromptText
+	result.Description = ann.Description
+	for _, c := range ann.Constraints {
+		result.Constraints = append(result.Constraints, explainConstraint{Name: c.Name, Value: c.Value})
+	}
+	result.EnvDefaults = ann.EnvDefaults
+	result.Optional = ann.IsOptional
+	result.Secret = ann.IsSecret
+	result.NoCase = ann.NoCase
+	result.Example = validator.GetExample(ann)
+	result.Suggestion = validator.GetSuggestion(ann)
+
+	return result
+}
+
+// formatExplanation renders an explanation as aligned "Label: value" lines.
+func formatExplanation(r explainResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (line %d of %s)\n", r.Name, r.Line, distLabel())
+
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %-12s %s\n", label+":", value)
+		}
+	}
+
+	defaultValue := r.Default
+	if defaultValue == "" {
+		defaultValue = "(none)"
+	}
+	field("Default", defaultValue)
+
+	if !r.Annotated {
+		b.WriteString("  Not annotated: never prompted for and not validated\n")
+		return b.String()
+	}
+
+	field("Type", r.Type)
+	field("Like", r.Like)
+	field("Prompt", r.Prompt)
+	field("Description", r.Description)
+	constraints := make([]string, 0, len(r.Constraints))
+	for _, c := range r.Constraints {
+		constraints = append(constraints, c.Name+":"+c.Value)
+	}
+	field("Constraints", strings.Join(constraints, ", "))
+	envs := make([]string, 0, len(r.EnvDefaults))
+	for env, value := range r.EnvDefaults {
+		envs = append(envs, env+"="+value)
+	}
+	sort.Strings(envs)
+	field("Env default", strings.Join(envs, ", "))
+	field("Optional", yesNo(r.Optional))
+	field("Secret", yesNo(r.Secret))
+	if r.NoCase {
+		field("Case", "options match case-insensitively")
+	}
+	field("Example", r.Example)
+	field("Suggestion", r.Suggestion)
+	field("Annotation", r.Annotation)
+
+	return b.String()
+}
+
+// yesNo spells a flag for the explain report.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
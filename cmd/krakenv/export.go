@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/export"
+	"github.com/theburrowhub/krakenv/pkg/envfile"
+)
+
+var (
+	exportFormat        string
+	exportService       string
+	exportInlineSecrets bool
+	exportName          string
+	exportNamespace     string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <target>",
+	Short: "Print environment variables for deployment tools",
+	Long: `Print the variables of an environment file in a format other tools can
+consume. Variables the target does not set take their distributable default,
+if there is a distributable.
+
+Formats:
+  compose - A Docker Compose environment: mapping. Secret variables are
+            written as ${VAR} references unless --inline-secrets is given.
+            Use --service to print a minimal services: stub instead.
+  k8s-configmap - A Kubernetes ConfigMap with the variables not marked secret.
+  k8s-secret    - A Kubernetes Secret with the secret variables under stringData.
+  k8s           - Both, as one stream for kubectl apply -f -.
+  shell - export NAME='value' lines for eval or source. Values are
+          single-quoted, so spaces, $ and quotes are kept literally.
+
+Kubernetes resources are named after the target (.env.production becomes
+env-production) unless --name is given. Secrets are identified by their
+annotation in the distributable.
+
+Examples:
+  krakenv export --format compose .env.local
+  krakenv export --format compose --service api .env.production > compose.override.yml
+  krakenv export --format compose --inline-secrets .env.local
+  krakenv export --format k8s --name api --namespace prod .env.production | kubectl apply -f -
+  krakenv export --format k8s-configmap .env.staging
+  eval "$(krakenv export --format shell .env.local)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", export.FormatCompose,
+		"Output format: compose, k8s, k8s-configmap, k8s-secret, shell")
+	exportCmd.Flags().StringVar(&exportService, "service", "",
+		"Wrap the compose environment in a service stub with this name")
+	exportCmd.Flags().BoolVar(&exportInlineSecrets, "inline-secrets", false,
+		"Write secret values instead of ${VAR} references")
+	exportCmd.Flags().StringVar(&exportName, "name", "",
+		"Kubernetes resource name (default: derived from the target file name)")
+	exportCmd.Flags().StringVar(&exportNamespace, "namespace", "",
+		"Kubernetes namespace for the resources")
+
+	exportCmd.ValidArgsFunction = completeEnvFiles(1)
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(_ *cobra.Command, args []string) error {
+	targetPath := args[0]
+
+	if !export.IsValidFormat(exportFormat) {
+		return fmt.Errorf("invalid format %q (use: compose, k8s, k8s-configmap, k8s-secret, shell)", exportFormat)
+	}
+
+	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", targetPath)
+		os.Exit(2)
+	}
+
+	targetFile, err := envfile.Parse(targetPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", targetPath, err)
+		os.Exit(2)
+	}
+
+	// The distributable is optional; it adds defaults and marks secrets
+	distFile, distErr := loadDistributable()
+	if distErr != nil {
+		if !errors.Is(distErr, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distLabel(), distErr)
+			os.Exit(2)
+		}
+		distFile = nil
+	}
+
+	variables := export.Merge(distFile, targetFile)
+
+	k8sOpts := export.KubernetesOptions{Name: exportName, Namespace: exportNamespace}
+	if k8sOpts.Name == "" {
+		k8sOpts.Name = export.ResourceName(targetPath)
+	}
+
+	var output []byte
+	switch exportFormat {
+	case export.FormatCompose:
+		output, err = export.Compose(variables, export.ComposeOptions{
+			Service:       exportService,
+			InlineSecrets: exportInlineSecrets,
+		})
+	case export.FormatK8s:
+		output, err = export.Kubernetes(variables, k8sOpts)
+	case export.FormatK8sConfigMap:
+		output, err = export.ConfigMap(variables, k8sOpts)
+	case export.FormatK8sSecret:
+		output, err = export.Secret(variables, k8sOpts)
+	case export.FormatShell:
+		output = export.Shell(variables)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", exportFormat, err)
+	}
+
+	fmt.Print(string(output))
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var (
+	fmtCheck bool
+	fmtDiff  bool
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [path]",
+	Short: "Reformat a distributable into canonical form",
+	Long: `Reformat a distributable file into canonical form.
+
+Config lines are moved to the top, variables are written as NAME=value with
+a single space before #prompt:, annotation spacing is normalized, trailing
+whitespace is removed and runs of blank lines are collapsed. Comments and
+variable order are preserved.
+
+Exit codes:
+  0 - File already formatted (or was reformatted)
+  1 - File needs formatting (with --check)
+  2 - File not found or unreadable
+
+Examples:
+  krakenv fmt
+  krakenv fmt config/.env.dist
+  krakenv fmt --check
+  krakenv fmt --diff`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFmt,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false,
+		"Exit 1 if the file is not formatted, without writing it")
+	fmtCmd.Flags().BoolVar(&fmtDiff, "diff", false,
+		"Print a unified diff of the changes instead of writing them")
+
+	rootCmd.AddCommand(fmtCmd)
+}
+
+func runFmt(_ *cobra.Command, args []string) error {
+	path := distPath
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Cannot read %s: %v\n", path, err)
+		os.Exit(2)
+	}
+
+	envFile, err := loadDistFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", path, err)
+		os.Exit(2)
+	}
+
+	original := string(data)
+	formatted := parser.FormatEnvFile(envFile)
+	changed := formatted != original
+
+	if fmtDiff && changed {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(original),
+			B:        difflib.SplitLines(formatted),
+			FromFile: path,
+			ToFile:   path + " (formatted)",
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compute diff: %w", err)
+		}
+		fmt.Print(diff)
+	}
+
+	if fmtCheck {
+		if changed {
+			if !quiet && !fmtDiff {
+				fmt.Printf("%s is not formatted\n", path)
+			}
+			os.Exit(1)
+		}
+		if !quiet {
+			fmt.Printf("✓ %s is formatted\n", path)
+		}
+		return nil
+	}
+
+	if fmtDiff || !changed {
+		if !changed && !quiet {
+			fmt.Printf("✓ %s is already formatted\n", path)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Formatted %s\n", path)
+	}
+
+	return nil
+}
@@ -1,21 +1,50 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
 	"github.com/theburrowhub/krakenv/internal/generator"
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/log"
 	"github.com/theburrowhub/krakenv/internal/parser"
 	"github.com/theburrowhub/krakenv/internal/tui/wizard"
+	"github.com/theburrowhub/krakenv/internal/validator"
 )
 
 var (
 	generateForce           bool
 	generateAll             bool
 	generateKeepAnnotations bool
+	generateNoPromptDefault bool
+	generateTrace           string
+	generateResolve         bool
+	generateFormat          string
+	generateBackup          string
+	generateWatch           bool
+	generateOnly            []string
+	generateExcept          []string
+	generateValues          string
+	generateSort            bool
+	generateEnv             string
+	generateStripComments   bool
+	generateNormalizeBools  bool
+	generateAnnotAbove      bool
+	generateJobs            int
+	generateNoDefault       bool
+	generateMerge           string
+	generateDryRun          bool
 )
 
 var generateCmd = &cobra.Command{
@@ -26,11 +55,36 @@ var generateCmd = &cobra.Command{
 The wizard will prompt for each variable that needs a value.
 Variables with existing valid values are skipped.
 
+When the target exists, --merge decides between its values and the
+distributable's defaults where both are set and differ:
+  keep-target  - the target's value is kept (default)
+  prefer-dist  - the distributable's default replaces it if the target's
+                 value is still the default it was generated from;
+                 values changed locally are kept
+  interactive  - you are asked which one to keep for each conflict
+Values entered in the wizard or given with --values always win.
+
+With --dry-run the wizard still runs, but the generated file is printed to
+stdout instead of written, and the changes to the existing target are
+listed on stderr: + added, - removed and ~ changed variables. No file is
+written, so --dry-run cannot be combined with --watch or --trace.
+
 Examples:
   krakenv generate .env.local
   krakenv generate .env.testing --dist config/env.template
   krakenv generate --all
-  krakenv generate .env.local --non-interactive`,
+  krakenv generate --all --non-interactive --jobs 4
+  krakenv generate --env production
+  krakenv generate .env.local --non-interactive
+  krakenv generate config.json --format json
+  krakenv generate .env.local --watch
+  krakenv generate .env.local --no-default
+  krakenv generate .env.local --merge interactive
+  krakenv generate .env.local --dry-run
+  krakenv generate .env.local --only DB_PORT,DB_HOST
+  krakenv generate .env.local --except LEGACY_TOKEN
+  krakenv generate .env.local --values values.json
+  krakenv generate .env.production --strip-comments`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runGenerate,
 }
@@ -40,102 +94,422 @@ func init() {
 		"Overwrite existing file without confirmation")
 	generateCmd.Flags().BoolVarP(&generateAll, "all", "a", false,
 		"Generate all environments defined in config")
+	generateCmd.Flags().StringVarP(&generateEnv, "env", "e", "",
+		"Generate .env.<name> for an environment defined in config")
 	generateCmd.Flags().BoolVarP(&generateKeepAnnotations, "keep-annotations", "k", false,
 		"Preserve annotations in generated file")
+	generateCmd.Flags().BoolVar(&generateNoPromptDefault, "no-prompt-defaults", false,
+		"Do not pre-fill wizard inputs with defaults (Tab still applies them)")
+	generateCmd.Flags().BoolVar(&generateNoDefault, "no-default", false,
+		"Prompt for every annotated variable, even those with a default or existing value")
+	generateCmd.Flags().StringVar(&generateFormat, "format", generator.FormatDotenv,
+		"Output format: dotenv, json or yaml")
+	generateCmd.Flags().BoolVar(&generateResolve, "resolve", false,
+		"Write ${VAR} references as their resolved values")
+	generateCmd.Flags().StringVar(&generateTrace, "trace", "",
+		"Write a JSON log of how each value was decided to this file")
+	generateCmd.Flags().StringVar(&generateBackup, "backup", "",
+		"Back up an existing target before writing (simple: <path>.bak, timestamp: <path>.<time>.bak)")
+	generateCmd.Flags().Lookup("backup").NoOptDefVal = generator.BackupSimple
+	generateCmd.Flags().BoolVarP(&generateWatch, "watch", "w", false,
+		"Regenerate without prompting whenever the distributable changes")
+	generateCmd.Flags().StringSliceVar(&generateOnly, "only", nil,
+		"Only prompt for and write these variables (comma-separated)")
+	generateCmd.Flags().StringSliceVar(&generateExcept, "except", nil,
+		"Skip these variables, keeping their existing values (comma-separated)")
+	generateCmd.Flags().StringVar(&generateValues, "values", "",
+		"JSON file mapping variable names to values; skips the wizard")
+	generateCmd.Flags().BoolVar(&generateSort, "sort", false,
+		"Write variables sorted by name")
+	generateCmd.Flags().BoolVar(&generateStripComments, "strip-comments", false,
+		"Write only NAME=value lines, without config block, comments or annotations")
+	generateCmd.Flags().BoolVar(&generateAnnotAbove, "annotations-above", false,
+		"With --keep-annotations, write each annotation on its own line above the variable")
+	generateCmd.Flags().BoolVar(&generateNormalizeBools, "normalize-booleans", false,
+		"Write boolean values as true/false, or in the style of their output constraint")
+	generateCmd.Flags().StringVar(&generateMerge, "merge", generator.MergeKeepTarget,
+		"How existing target values and dist defaults are merged: keep-target, prefer-dist or interactive")
+	generateCmd.Flags().BoolVar(&generateDryRun, "dry-run", false,
+		"Print the generated file and what would change instead of writing it")
+	generateCmd.Flags().IntVarP(&generateJobs, "jobs", "j", runtime.NumCPU(),
+		"Targets generated at once by --all when not prompting")
 
 	rootCmd.AddCommand(generateCmd)
 }
 
 func runGenerate(_ *cobra.Command, args []string) error {
+	if !generator.IsValidFormat(generateFormat) {
+		return fmt.Errorf("invalid format %q (use: dotenv, json, yaml)", generateFormat)
+	}
+	if generateBackup != "" && !generator.IsValidBackupMode(generateBackup) {
+		return fmt.Errorf("invalid backup mode %q (use: simple, timestamp)", generateBackup)
+	}
+	if generateEnv != "" && (generateAll || len(args) > 0) {
+		return fmt.Errorf("--env cannot be combined with --all or a target file")
+	}
+	if !generator.IsValidMergeStrategy(generateMerge) {
+		return fmt.Errorf("invalid merge strategy %q (use: keep-target, prefer-dist, interactive)", generateMerge)
+	}
+	if generateMerge == generator.MergeInteractive && (nonInteractive || generateValues != "" || generateWatch) {
+		return fmt.Errorf("--merge interactive prompts for conflicts; it cannot be combined with --non-interactive, --values or --watch")
+	}
+	if generateDryRun && (generateWatch || generateTrace != "") {
+		return fmt.Errorf("--dry-run writes no files; it cannot be combined with --watch or --trace")
+	}
+	if generateJobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", generateJobs)
+	}
+
 	// Parse distributable
-	distFile, err := parser.ParseEnvFile(distPath)
+	distFile, err := loadDistributable()
 	if err != nil {
-		return fmt.Errorf("failed to parse distributable %s: %w", distPath, err)
+		return fmt.Errorf("failed to parse distributable %s: %w", distLabel(), err)
+	}
+
+	for _, name := range generator.NewFilter(generateOnly, generateExcept).Unmatched(distFile) {
+		fmt.Fprintf(os.Stderr, "WARNING: %s is not defined in %s\n", name, distLabel())
+	}
+
+	var values map[string]string
+	if generateValues != "" {
+		values = loadValuesFile(distFile, generateValues)
 	}
 
 	// Determine target(s)
 	var targets []string
 	if generateAll {
-		if distFile.Config != nil && len(distFile.Config.Environments) > 0 {
-			for _, env := range distFile.Config.Environments {
-				targets = append(targets, ".env."+env)
-			}
-		} else {
-			targets = []string{".env.local"}
+		for _, env := range configuredEnvironments(distFile) {
+			targets = append(targets, ".env."+env)
 		}
+	} else if generateEnv != "" {
+		target, err := environmentTarget(distFile, generateEnv)
+		if err != nil {
+			return err
+		}
+		targets = []string{target}
 	} else if len(args) > 0 {
 		targets = []string{args[0]}
 	} else {
-		return fmt.Errorf("target file required (e.g., .env.local) or use --all")
+		return fmt.Errorf("target file required (e.g., .env.local) or use --all or --env")
+	}
+
+	if generateWatch {
+		return watchDistributable(targets)
+	}
+
+	// Without prompts targets are independent and can be generated at once;
+	// wizards cannot overlap, so interactive runs stay sequential, and so do
+	// dry runs, whose output would otherwise interleave
+	if len(targets) > 1 && (nonInteractive || values != nil) && !generateDryRun {
+		return generateConcurrently(distFile, targets, values)
 	}
 
 	// Process each target
+	var traces []*generator.Trace
 	for _, target := range targets {
-		if err := generateTarget(distFile, target); err != nil {
+		trace, err := generateTarget(distFile, target, values)
+		if trace != nil {
+			traces = append(traces, trace)
+		}
+		if err != nil {
+			writeGenerateTrace(traces)
+			var unresolved *unresolvedError
+			if errors.As(err, &unresolved) {
+				printUnresolved(unresolved)
+				os.Exit(2)
+			}
 			return err
 		}
 	}
 
+	return writeGenerateTrace(traces)
+}
+
+// generateConcurrently generates targets without prompting using a pool of
+// generateJobs workers. Every target is attempted; failures are reported per
+// target once all of them have finished.
+func generateConcurrently(distFile *parser.EnvFile, targets []string, values map[string]string) error {
+	type result struct {
+		trace *generator.Trace
+		err   error
+	}
+	results := make([]result, len(targets))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(generateJobs, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				trace, err := generateTarget(distFile, targets[i], values)
+				results[i] = result{trace: trace, err: err}
+			}
+		}()
+	}
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var traces []*generator.Trace
+	var unresolved []*unresolvedError
+	failed := 0
+	for i, r := range results {
+		if r.trace != nil {
+			traces = append(traces, r.trace)
+		}
+		if r.err == nil {
+			continue
+		}
+		failed++
+		var u *unresolvedError
+		if errors.As(r.err, &u) {
+			unresolved = append(unresolved, u)
+		}
+		fmt.Fprintf(os.Stderr, "✗ %s: %v\n", targets[i], r.err)
+	}
+
+	if err := writeGenerateTrace(traces); err != nil {
+		return err
+	}
+	if len(unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "\nTo fix this, run the listed targets interactively (krakenv generate <target>)\n")
+		fmt.Fprintf(os.Stderr, "or add default values to your distributable\n")
+		os.Exit(2)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets failed", failed, len(targets))
+	}
 	return nil
 }
 
-func generateTarget(distFile *parser.EnvFile, targetPath string) error {
+// configuredEnvironments returns the environments declared in the
+// distributable's config, falling back to .krakenvrc and then "local".
+func configuredEnvironments(distFile *parser.EnvFile) []string {
+	if distFile.Config != nil && len(distFile.Config.Environments) > 0 {
+		return distFile.Config.Environments
+	}
+	if projectConfig != nil && len(projectConfig.Environments) > 0 {
+		return projectConfig.Environments
+	}
+	return parser.DefaultKrakenvConfig().Environments
+}
+
+// environmentTarget returns the target path for a named environment, which
+// must be one of the configured environments.
+func environmentTarget(distFile *parser.EnvFile, name string) (string, error) {
+	envs := configuredEnvironments(distFile)
+	for _, env := range envs {
+		if env == name {
+			return ".env." + env, nil
+		}
+	}
+	return "", fmt.Errorf("environment %q is not configured in %s (available: %s)",
+		name, distLabel(), strings.Join(envs, ", "))
+}
+
+// writeGenerateTrace writes the collected decision logs when --trace is set.
+func writeGenerateTrace(traces []*generator.Trace) error {
+	if generateTrace == "" {
+		return nil
+	}
+	if err := generator.WriteTraceFile(generateTrace, traces); err != nil {
+		return err
+	}
+	if verbose && !quiet {
+		fmt.Printf("Trace written to %s\n", generateTrace)
+	}
+	return nil
+}
+
+func generateTarget(distFile *parser.EnvFile, targetPath string, values map[string]string) (*generator.Trace, error) {
 	// Check if target exists
 	if _, err := os.Stat(targetPath); err == nil && !generateForce {
-		if nonInteractive {
+		if nonInteractive || generateDryRun {
 			// In non-interactive mode, just proceed with update
 		} else if !quiet {
 			fmt.Printf("Target file %s exists, will update...\n", targetPath)
 		}
 	}
 
-	// Create generator
-	gen := generator.NewGenerator(distFile, targetPath)
-	gen.KeepAnnotations = generateKeepAnnotations
+	// Only the wizard can re-enter values
+	gen := newTargetGenerator(distFile, targetPath, !nonInteractive && values == nil)
+	if generateTrace != "" {
+		gen.Trace = generator.NewTrace(targetPath)
+	}
 
 	// Load existing target
 	if err := gen.LoadTarget(); err != nil {
-		return fmt.Errorf("failed to load target: %w", err)
+		return nil, fmt.Errorf("failed to load target: %w", err)
 	}
 
 	// Get variables that need prompting
-	toPrompt := gen.GetVariablesToPrompt()
+	var toPrompt []parser.Variable
+	for _, v := range gen.GetVariablesToPrompt() {
+		if _, ok := values[v.Name]; ok {
+			log.Debug("variable skipped", "name", v.Name, "reason", "given with --values")
+			continue
+		}
+		toPrompt = append(toPrompt, v)
+	}
 
-	var userValues map[string]string
+	userValues := make(map[string]string, len(values))
+	for name, value := range values {
+		userValues[name] = value
+	}
 
 	if len(toPrompt) > 0 {
-		if nonInteractive {
+		if nonInteractive || values != nil {
 			// Non-interactive mode: fail if any variables need values
-			return handleNonInteractive(toPrompt, targetPath)
+			if err := handleNonInteractive(toPrompt, targetPath); err != nil {
+				return nil, err
+			}
+		} else {
+			// Run interactive wizard
+			values, err := runWizard(toPrompt)
+			if err != nil {
+				return nil, err
+			}
+			if values == nil {
+				// User aborted
+				return nil, fmt.Errorf("generation aborted by user")
+			}
+			userValues = values
 		}
+	}
 
-		// Run interactive wizard
-		values, err := runWizard(toPrompt)
-		if err != nil {
-			return err
-		}
-		if values == nil {
-			// User aborted
-			return fmt.Errorf("generation aborted by user")
-		}
-		userValues = values
-	} else {
-		userValues = make(map[string]string)
+	if gen.Merge == generator.MergeInteractive {
+		askMergeConflicts(gen.Conflicts(), userValues)
 	}
 
 	// Merge and write
 	variables := gen.MergeVariables(userValues)
+	if generateDryRun {
+		return gen.Trace, previewTarget(gen, variables)
+	}
 	if err := gen.WriteFile(variables); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return gen.Trace, fmt.Errorf("failed to write file: %w", err)
 	}
 
+	if gen.DroppedComments > 0 && !quiet {
+		fmt.Fprintf(os.Stderr, "WARNING: %d comment(s) not attached to a variable were left out of sorted output\n", gen.DroppedComments)
+	}
+	if gen.BackupPath != "" && verbose && !quiet {
+		fmt.Printf("Backed up previous %s to %s\n", targetPath, gen.BackupPath)
+	}
 	if !quiet {
 		fmt.Printf("✓ Generated %s with %d variables\n", targetPath, len(variables))
 	}
 
+	return gen.Trace, nil
+}
+
+// newTargetGenerator creates a generator for targetPath configured from the
+// generate flags. prompting tells whether the wizard can run, which --no-default
+// needs.
+func newTargetGenerator(distFile *parser.EnvFile, targetPath string, prompting bool) *generator.Generator {
+	gen := generator.NewGenerator(distFile, targetPath)
+	gen.KeepAnnotations = generateKeepAnnotations
+	gen.Resolve = generateResolve
+	gen.Format = generateFormat
+	gen.Backup = generateBackup
+	gen.Filter = generator.NewFilter(generateOnly, generateExcept)
+	gen.Sort = generateSort
+	gen.StripComments = generateStripComments
+	gen.NormalizeBooleans = generateNormalizeBools
+	gen.AnnotationsAbove = generateAnnotAbove
+	gen.Merge = generateMerge
+	gen.PromptAll = generateNoDefault && prompting
+	return gen
+}
+
+// previewTarget prints the file gen would write to stdout and, unless quiet,
+// lists the variables it would add, remove or change in the existing target
+// on stderr. Values are left out so secrets stay hidden.
+func previewTarget(gen *generator.Generator, variables []parser.Variable) error {
+	content, err := gen.Render(variables)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", gen.TargetPath, err)
+	}
+	os.Stdout.Write(content)
+
+	if gen.DroppedComments > 0 && !quiet {
+		fmt.Fprintf(os.Stderr, "WARNING: %d comment(s) not attached to a variable were left out of sorted output\n", gen.DroppedComments)
+	}
+	if quiet {
+		return nil
+	}
+
+	if gen.TargetFile == nil {
+		fmt.Fprintf(os.Stderr, "Would create %s with %d variables\n", gen.TargetPath, len(variables))
+		return nil
+	}
+
+	// Compare the values as written, after resolving and normalizing
+	output, err := gen.OutputVariables(variables)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", gen.TargetPath, err)
+	}
+	diff := inspector.Diff(gen.TargetFile, &parser.EnvFile{Path: gen.TargetPath, Variables: output})
+	if !diff.HasDifferences() {
+		fmt.Fprintf(os.Stderr, "No variable changes to %s\n", gen.TargetPath)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Would update %s: %d added, %d removed, %d changed\n",
+		gen.TargetPath, len(diff.OnlyInB), len(diff.OnlyInA), len(diff.Changed))
+	for _, v := range diff.OnlyInB {
+		fmt.Fprintf(os.Stderr, "  + %s\n", v.Name)
+	}
+	for _, v := range diff.OnlyInA {
+		fmt.Fprintf(os.Stderr, "  - %s\n", v.Name)
+	}
+	for _, c := range diff.Changed {
+		fmt.Fprintf(os.Stderr, "  ~ %s\n", c.Name)
+	}
 	return nil
 }
 
+// askMergeConflicts asks, for each conflict not already settled in the
+// wizard, whether to keep the target's value or take the dist default, and
+// records the answer in userValues.
+func askMergeConflicts(conflicts []generator.MergeConflict, userValues map[string]string) {
+	reader := bufio.NewReader(os.Stdin)
+	for _, c := range conflicts {
+		name := c.Variable.Name
+		if _, ok := userValues[name]; ok {
+			continue
+		}
+
+		target, dist := c.Target, c.Dist
+		if c.Variable.Annotation != nil && c.Variable.Annotation.IsSecret {
+			target, dist = "****", "****"
+		}
+		fmt.Printf("%s differs: target has %q, distributable default is %q\n", name, target, dist)
+		answer := strings.ToLower(askLine(reader, "  Keep [t]arget or use [d]istributable value? [T/d]: "))
+		if answer == "d" || answer == "dist" {
+			userValues[name] = c.Dist
+		} else {
+			userValues[name] = c.Target
+		}
+	}
+}
+
+// unresolvedError reports required variables that have no value when
+// generating without prompts.
+type unresolvedError struct {
+	target string
+	names  []string
+}
+
+func (e *unresolvedError) Error() string {
+	return fmt.Sprintf("variables require values: %s", strings.Join(e.names, ", "))
+}
+
+// handleNonInteractive checks that every variable that would be prompted for
+// can be left at its default or empty, returning an *unresolvedError if not.
 func handleNonInteractive(toPrompt []parser.Variable, targetPath string) error {
 	// Check if we can resolve with defaults
 	var unresolved []string
@@ -156,23 +530,24 @@ func handleNonInteractive(toPrompt []parser.Variable, targetPath string) error {
 		return nil
 	}
 
-	// Cannot resolve - fail with descriptive error
-	fmt.Fprintf(os.Stderr, "ERROR: Cannot generate %s in non-interactive mode\n\n", targetPath)
+	return &unresolvedError{target: targetPath, names: unresolved}
+}
+
+// printUnresolved explains a failed non-interactive generation of one target.
+func printUnresolved(e *unresolvedError) {
+	fmt.Fprintf(os.Stderr, "ERROR: Cannot generate %s in non-interactive mode\n\n", e.target)
 	fmt.Fprintf(os.Stderr, "The following variables require values:\n")
-	for _, name := range unresolved {
+	for _, name := range e.names {
 		fmt.Fprintf(os.Stderr, "  - %s\n", name)
 	}
 	fmt.Fprintf(os.Stderr, "\nTo fix this:\n")
-	fmt.Fprintf(os.Stderr, "  1. Run interactively: krakenv generate %s\n", targetPath)
+	fmt.Fprintf(os.Stderr, "  1. Run interactively: krakenv generate %s\n", e.target)
 	fmt.Fprintf(os.Stderr, "  2. Or set values in environment before running\n")
 	fmt.Fprintf(os.Stderr, "  3. Or add default values to your distributable\n")
-
-	os.Exit(2)
-	return nil // Won't reach here
 }
 
 func runWizard(variables []parser.Variable) (map[string]string, error) {
-	m := wizard.New(variables)
+	m := wizard.New(variables).WithPrefill(!generateNoPromptDefault)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	finalModel, err := p.Run()
@@ -188,3 +563,87 @@ func runWizard(variables []parser.Variable) (map[string]string, error) {
 
 	return wizardModel.GetValues(), nil
 }
+
+// loadValuesFile reads a JSON object of variable values for --values and
+// validates them against the distributable. Unknown names are warned about
+// and dropped; invalid values abort with exit code 2.
+func loadValuesFile(distFile *parser.EnvFile, path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Cannot read %s: %v\n", path, err)
+		os.Exit(2)
+	}
+
+	raw, err := decodeValues(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s is not a JSON object of name/value pairs: %v\n", path, err)
+		os.Exit(2)
+	}
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]string, len(raw))
+	var failures []string
+	for _, name := range names {
+		distVar := distFile.GetVariable(name)
+		if distVar == nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %s in %s is not defined in %s\n", name, path, distLabel())
+			continue
+		}
+
+		value, err := jsonValueString(raw[name])
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if err := validator.ValidateValue(value, distVar.Annotation); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		values[name] = value
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: Invalid values in %s:\n", path)
+		for _, f := range failures {
+			fmt.Fprintf(os.Stderr, "  - %s\n", f)
+		}
+		os.Exit(2)
+	}
+
+	return values
+}
+
+// decodeValues decodes a --values file. Numbers are kept as written, so large
+// integers and IDs do not lose precision through float64.
+func decodeValues(data []byte) (map[string]any, error) {
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// jsonValueString converts a JSON value to its env file form. Strings are used
+// as is, other scalars in their JSON spelling, and objects or arrays as
+// compact JSON.
+func jsonValueString(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("cannot convert value: %w", err)
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeValues_KeepsNumbers(t *testing.T) {
+	raw, err := decodeValues([]byte(`{"ID": 12345678901234567890, "RATIO": 1.50, "PORTS": [8080, 9007199254740993]}`))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"ID":    "12345678901234567890",
+		"RATIO": "1.50",
+		"PORTS": "[8080,9007199254740993]",
+	}
+	for name, want := range tests {
+		got, err := jsonValueString(raw[name])
+		require.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestRunGenerate_DryRun(t *testing.T) {
+	dist := "PORT=8080 #prompt:Port?|int\nDEBUG=false #prompt:Debug?|boolean\nHOST=localhost #prompt:Host?|string\n"
+
+	tests := []struct {
+		name   string
+		target string
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "added, removed and changed",
+			target: "PORT=3000\nDEBUG=yes\nLEGACY=1\n",
+			stdout: "#krakenv:defaults=HOST=localhost\n\nPORT=3000\nDEBUG=true\nHOST=localhost\n",
+			stderr: "Would update %s: 1 added, 1 removed, 1 changed\n  + HOST\n  - LEGACY\n  ~ DEBUG\n",
+		},
+		{
+			name:   "no changes",
+			target: "PORT=3000\nDEBUG=true\nHOST=db\n",
+			stdout: "PORT=3000\nDEBUG=true\nHOST=db\n",
+			stderr: "No variable changes to %s\n",
+		},
+		{
+			name:   "new target",
+			stdout: "#krakenv:defaults=DEBUG=false&HOST=localhost&PORT=8080\n\nPORT=8080\nDEBUG=false\nHOST=localhost\n",
+			stderr: "Would create %s with 3 variables\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The subprocess has its own directory; a relative target is
+			// reported the same in both
+			dir := t.TempDir()
+			t.Chdir(dir)
+			setGlobal(t, &distPaths, []string{writeTestFile(t, dir, ".env.dist", dist)})
+			target := ".env.local"
+			if tt.target != "" {
+				writeTestFile(t, dir, target, tt.target)
+			}
+			setGlobal(t, &generateDryRun, true)
+			setGlobal(t, &generateNormalizeBools, true)
+			setGlobal(t, &nonInteractive, true)
+
+			code, stdout, stderr := runExit(t, func() {
+				require.NoError(t, runGenerate(nil, []string{target}))
+				if tt.target == "" {
+					assert.NoFileExists(t, target)
+				} else {
+					assert.Equal(t, tt.target, readTestFile(t, target))
+				}
+			})
+			assert.Equal(t, 0, code)
+			assert.Equal(t, tt.stdout, stdout)
+			assert.Equal(t, fmt.Sprintf(tt.stderr, target), stderr)
+		})
+	}
+}
+
+func TestRunGenerate_DryRunFlags(t *testing.T) {
+	setGlobal(t, &generateDryRun, true)
+
+	t.Run("trace", func(t *testing.T) {
+		setGlobal(t, &generateTrace, filepath.Join(t.TempDir(), "trace.json"))
+		assert.ErrorContains(t, runGenerate(nil, []string{".env.local"}), "cannot be combined with --watch or --trace")
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		setGlobal(t, &generateWatch, true)
+		assert.ErrorContains(t, runGenerate(nil, []string{".env.local"}), "cannot be combined with --watch or --trace")
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var (
+	getTarget  string
+	getDefault string
+	getJSON    bool
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a single variable from an environment file",
+	Long: `Print the value of a single variable from an environment file.
+
+Exit codes:
+  0 - Variable found (or --default given)
+  1 - Variable not defined in the file
+  2 - File not found or unreadable
+
+Examples:
+  krakenv get DB_PORT --target .env.local
+  PORT=$(krakenv get PORT --target .env.local --default 8080)
+  krakenv get DB_HOST --target .env.local --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGet,
+}
+
+func init() {
+	getCmd.Flags().StringVar(&getTarget, "target", "",
+		"Environment file to read (required)")
+	getCmd.Flags().StringVar(&getDefault, "default", "",
+		"Value to print if the variable is not defined")
+	getCmd.Flags().BoolVarP(&getJSON, "json", "j", false,
+		"Output as JSON (for scripting)")
+	getCmd.MarkFlagRequired("target")
+	getCmd.RegisterFlagCompletionFunc("target", completeEnvFiles(-1))
+
+	rootCmd.AddCommand(getCmd)
+}
+
+// getResult is the `get --json` output.
+type getResult struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Found bool   `json:"found"`
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if _, err := os.Stat(getTarget); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", getTarget)
+		os.Exit(2)
+	}
+
+	envFile, err := parser.ParseEnvFile(getTarget)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", getTarget, err)
+		os.Exit(2)
+	}
+
+	hasDefault := cmd.Flags().Changed("default")
+	result := getResult{Name: name}
+	if v := envFile.GetVariable(name); v != nil {
+		result.Value = v.Value
+		result.Found = true
+	} else if hasDefault {
+		result.Value = getDefault
+	}
+
+	if getJSON {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if result.Found || hasDefault {
+		fmt.Println(result.Value)
+	} else if !quiet {
+		fmt.Fprintf(os.Stderr, "Variable %s not found in %s\n", name, getTarget)
+	}
+
+	if !result.Found && !hasDefault {
+		os.Exit(1)
+	}
+
+	return nil
+}
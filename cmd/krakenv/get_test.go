@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		varName string
+		flags   []string
+		code    int
+		stdout  string
+	}{
+		{name: "found", varName: "DB_HOST", stdout: "db.internal\n"},
+		{name: "found ignores default", varName: "DB_HOST", flags: []string{"--default", "localhost"}, stdout: "db.internal\n"},
+		{name: "missing", varName: "DB_PORT", code: 1},
+		{name: "missing with default", varName: "DB_PORT", flags: []string{"--default", "5432"}, stdout: "5432\n"},
+		{name: "missing with empty default", varName: "DB_PORT", flags: []string{"--default="}, stdout: "\n"},
+		{name: "json found", varName: "DB_HOST", flags: []string{"--json"}, stdout: `{"name":"DB_HOST","value":"db.internal","found":true}` + "\n"},
+		{name: "json missing", varName: "DB_PORT", flags: []string{"--json"}, code: 1, stdout: `{"name":"DB_PORT","value":"","found":false}` + "\n"},
+		{name: "json default", varName: "DB_PORT", flags: []string{"--default=5432", "--json"}, stdout: `{"name":"DB_PORT","value":"5432","found":false}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setGlobal(t, &getTarget, writeTestFile(t, t.TempDir(), ".env.local", "DB_HOST=db.internal\n"))
+			setGlobal(t, &getJSON, false)
+			setGlobal(t, &quiet, true)
+
+			code, stdout, _ := runExit(t, func() {
+				require.NoError(t, getCmd.ParseFlags(tt.flags))
+				runGet(getCmd, []string{tt.varName})
+			})
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.stdout, stdout)
+		})
+	}
+}
+
+func TestRunGet_FileNotFound(t *testing.T) {
+	setGlobal(t, &getTarget, "/nonexistent/.env.local")
+
+	code, _, stderr := runExit(t, func() {
+		runGet(getCmd, []string{"DB_HOST"})
+	})
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "File not found")
+}
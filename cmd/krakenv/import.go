@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+var (
+	importOutput        string
+	importSecretPattern string
+	importForce         bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <source>",
+	Short: "Create a distributable from an existing environment file",
+	Long: `Create an annotated distributable from a plain environment file.
+
+Each variable gets an annotation with a type inferred from its value, and
+the current value becomes its default. Variables whose names match the
+secret pattern are marked secret and their values are left out.
+Comments and blank lines are kept; existing annotations are kept as is.
+
+Examples:
+  krakenv import .env
+  krakenv import .env --output config/.env.dist
+  krakenv import .env --secret-pattern '.*(SECRET|TOKEN|PASSWORD).*'
+  krakenv import .env --secret-pattern ''`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importOutput, "output", "o", "",
+		"Path of the distributable to write (default: --dist)")
+	importCmd.Flags().StringVar(&importSecretPattern, "secret-pattern", validator.DefaultSecretPattern,
+		"Regex on variable names to mark as secret (empty to disable)")
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false,
+		"Overwrite an existing distributable")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(_ *cobra.Command, args []string) error {
+	sourcePath := args[0]
+	outputPath := importOutput
+	if outputPath == "" {
+		outputPath = distPath
+	}
+
+	var secretPattern *regexp.Regexp
+	if importSecretPattern != "" {
+		re, err := regexp.Compile(importSecretPattern)
+		if err != nil {
+			return fmt.Errorf("invalid secret pattern: %w", err)
+		}
+		secretPattern = re
+	}
+
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", sourcePath)
+		os.Exit(2)
+	}
+
+	if _, err := os.Stat(outputPath); err == nil && !importForce {
+		return fmt.Errorf("file %s already exists (use --force to overwrite)", outputPath)
+	}
+
+	source, err := parser.ParseEnvFile(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", sourcePath, err)
+	}
+
+	lines, secrets := importLines(source, secretPattern)
+
+	if err := os.WriteFile(outputPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Imported %d variables from %s into %s (%d secret)\n",
+			len(source.Variables), sourcePath, outputPath, secrets)
+		if verbose {
+			fmt.Println("\nReview the inferred types and prompts, then run:")
+			fmt.Println("  krakenv lint " + outputPath)
+		}
+	}
+
+	return nil
+}
+
+// importLines renders source as a distributable: variables gain inferred
+// annotations, secrets lose their values, and comments and blank lines stay
+// in place. Later definitions of a duplicated variable are dropped, keeping
+// the value that wins. Returns the lines and the number of secret variables.
+func importLines(source *parser.EnvFile, secretPattern *regexp.Regexp) ([]string, int) {
+	var lines []string
+	written := make(map[string]bool)
+	secrets := 0
+
+	for _, line := range source.Lines {
+		switch line.Kind {
+		case parser.LineBlank:
+			lines = append(lines, "")
+		case parser.LineComment, parser.LineConfig:
+			lines = append(lines, strings.TrimSpace(line.Text))
+		case parser.LineUnparsed:
+			fmt.Fprintf(os.Stderr, "WARNING: skipping unparsed line in %s: %s\n", source.Path, strings.TrimSpace(line.Text))
+		case parser.LineVariable:
+			if written[line.Name] {
+				continue
+			}
+			written[line.Name] = true
+
+			v := *source.GetVariable(line.Name)
+			if v.Annotation == nil {
+				v.Annotation = &parser.Annotation{
+					PromptText: "Enter " + v.Name,
+					Type:       validator.InferType(v.Value),
+				}
+				if secretPattern != nil && secretPattern.MatchString(v.Name) {
+					v.Annotation.IsSecret = true
+				}
+			}
+			if v.Annotation.IsSecret {
+				v.Value = ""
+				secrets++
+			}
+			if v.AnnotationAbove {
+				lines = append(lines, parser.FormatVariableAbove(v))
+			} else {
+				lines = append(lines, parser.FormatVariable(v, true))
+			}
+		}
+	}
+
+	// Drop trailing blank lines; the file ends with a single newline
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines, secrets
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+func TestRunImport(t *testing.T) {
+	dir := t.TempDir()
+	source := writeTestFile(t, dir, ".env",
+		"# Server\nPORT=8080\nDEBUG=true\nHOST=localhost\n\nAPI_TOKEN=s3cr3t\nPORT=9090\n")
+	output := filepath.Join(dir, ".env.dist")
+	setGlobal(t, &importOutput, output)
+	setGlobal(t, &importSecretPattern, validator.DefaultSecretPattern)
+	setGlobal(t, &importForce, false)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runImport(nil, []string{source}))
+	assert.Equal(t, "# Server\n"+
+		"PORT=9090 #prompt:Enter PORT|int\n"+
+		"DEBUG=true #prompt:Enter DEBUG|boolean\n"+
+		"HOST=localhost #prompt:Enter HOST|string\n"+
+		"\n"+
+		"API_TOKEN= #prompt:Enter API_TOKEN|string;secret\n",
+		readTestFile(t, output))
+
+	distFile, err := parser.ParseEnvFile(output)
+	require.NoError(t, err)
+	assert.True(t, distFile.GetVariable("API_TOKEN").Annotation.IsSecret)
+	assert.Equal(t, parser.TypeInt, distFile.GetVariable("PORT").Annotation.Type)
+}
+
+func TestRunImport_SecretPattern(t *testing.T) {
+	dir := t.TempDir()
+	source := writeTestFile(t, dir, ".env", "API_TOKEN=abc\nMY_KEY=xyz\n")
+	output := filepath.Join(dir, ".env.dist")
+	setGlobal(t, &importOutput, output)
+	setGlobal(t, &importForce, false)
+	setGlobal(t, &quiet, true)
+
+	t.Run("custom pattern", func(t *testing.T) {
+		setGlobal(t, &importSecretPattern, "_KEY$")
+		setGlobal(t, &importForce, true)
+
+		require.NoError(t, runImport(nil, []string{source}))
+		assert.Equal(t, "API_TOKEN=abc #prompt:Enter API_TOKEN|string\nMY_KEY= #prompt:Enter MY_KEY|string;secret\n",
+			readTestFile(t, output))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		setGlobal(t, &importSecretPattern, "")
+		setGlobal(t, &importForce, true)
+
+		require.NoError(t, runImport(nil, []string{source}))
+		assert.Equal(t, "API_TOKEN=abc #prompt:Enter API_TOKEN|string\nMY_KEY=xyz #prompt:Enter MY_KEY|string\n",
+			readTestFile(t, output))
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		setGlobal(t, &importSecretPattern, "(")
+
+		assert.ErrorContains(t, runImport(nil, []string{source}), "invalid secret pattern")
+	})
+}
+
+func TestRunImport_ExistingOutput(t *testing.T) {
+	dir := t.TempDir()
+	source := writeTestFile(t, dir, ".env", "PORT=8080\n")
+	output := writeTestFile(t, dir, ".env.dist", "KEEP=1\n")
+	setGlobal(t, &importOutput, output)
+	setGlobal(t, &importSecretPattern, "")
+	setGlobal(t, &quiet, true)
+
+	setGlobal(t, &importForce, false)
+	assert.ErrorContains(t, runImport(nil, []string{source}), "already exists")
+	assert.Equal(t, "KEEP=1\n", readTestFile(t, output))
+
+	setGlobal(t, &importForce, true)
+	require.NoError(t, runImport(nil, []string{source}))
+	assert.Equal(t, "PORT=8080 #prompt:Enter PORT|int\n", readTestFile(t, output))
+}
+
+func TestRunImport_KeepsAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	source := writeTestFile(t, dir, ".env", "#prompt:Level?|enum;options:debug,info\nLOG_LEVEL=info\nDB_PASSWORD=pw #prompt:Password?|string\n")
+	output := filepath.Join(dir, ".env.dist")
+	setGlobal(t, &importOutput, output)
+	setGlobal(t, &importSecretPattern, validator.DefaultSecretPattern)
+	setGlobal(t, &importForce, false)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runImport(nil, []string{source}))
+	assert.Equal(t, "#prompt:Level?|enum;options:debug,info\nLOG_LEVEL=info\nDB_PASSWORD=pw #prompt:Password?|string\n",
+		readTestFile(t, output))
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -15,23 +17,89 @@ var (
 	initPath         string
 	initEnvironments string
 	initForce        bool
-	initTemplate     bool
+	initTemplate     string
 )
 
+// Template names with special meaning; any other name is a preset.
+const (
+	templateGeneric = "generic" // Example comments only, the --template default
+	templateList    = "list"    // Print the available presets
+)
+
+// presetVariable is a variable a preset scaffolds, written as
+// NAME=default #prompt:...|type;constraints.
+type presetVariable struct {
+	name       string
+	value      string
+	annotation string
+}
+
+// initPreset is a named set of variables for a common stack.
+type initPreset struct {
+	description string
+	variables   []presetVariable
+}
+
+// initPresets are the presets available to init --template.
+var initPresets = map[string]initPreset{
+	"web": {
+		description: "HTTP service: port, host, log level and database URL",
+		variables: []presetVariable{
+			{"PORT", "8080", "#prompt:Server port?|int;min:1;max:65535"},
+			{"HOST", "0.0.0.0", "#prompt:Address to listen on?|ip"},
+			{"LOG_LEVEL", "info", "#prompt:Log level?|enum;options:debug,info,warn,error"},
+			{"DATABASE_URL", "", "#prompt:Database connection URL?|url;schemes:postgres,postgresql,mysql;secret"},
+		},
+	},
+	"postgres": {
+		description: "PostgreSQL connection settings",
+		variables: []presetVariable{
+			{"POSTGRES_HOST", "localhost", "#prompt:PostgreSQL host?|hostname;allowip:true"},
+			{"POSTGRES_PORT", "5432", "#prompt:PostgreSQL port?|int;min:1;max:65535"},
+			{"POSTGRES_DB", "app", "#prompt:Database name?|string;minlen:1;maxlen:63"},
+			{"POSTGRES_USER", "postgres", "#prompt:Database user?|string;minlen:1"},
+			{"POSTGRES_PASSWORD", "", "#prompt:Database password?|string;minlen:8;secret"},
+			{"POSTGRES_SSLMODE", "disable", "#prompt:SSL mode?|enum;options:disable,require,verify-ca,verify-full"},
+		},
+	},
+	"redis": {
+		description: "Redis cache connection",
+		variables: []presetVariable{
+			{"REDIS_URL", "redis://localhost:6379/0", "#prompt:Redis URL?|url;schemes:redis,rediss"},
+			{"REDIS_PASSWORD", "", "#prompt:Redis password?|string;optional;secret"},
+			{"CACHE_TTL", "5m", "#prompt:Default cache TTL?|duration;min:1s"},
+		},
+	},
+	"smtp": {
+		description: "Outgoing mail server",
+		variables: []presetVariable{
+			{"SMTP_HOST", "", "#prompt:SMTP server?|hostname"},
+			{"SMTP_PORT", "587", "#prompt:SMTP port?|enum;options:25,465,587,2525"},
+			{"SMTP_USER", "", "#prompt:SMTP user?|string;optional"},
+			{"SMTP_PASSWORD", "", "#prompt:SMTP password?|string;optional;secret"},
+			{"MAIL_FROM", "", "#prompt:Sender address?|email"},
+		},
+	},
+}
+
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize a new distributable file with optional interactive wizard",
 	Long: `Initialize a new distributable file for krakenv.
 
 By default, runs an interactive wizard to add variables.
-Use --template to create a file with example comments only.
+Use --template to create a file with example comments only, or
+--template <preset> to start from annotated variables for a common stack.
+--template list shows the available presets.
 
 Examples:
   krakenv init
   krakenv init --path config/.env.template
   krakenv init --template
+  krakenv init --template web
+  krakenv init --template list
   krakenv init --force`,
-	Args: cobra.NoArgs,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
 
@@ -42,13 +110,37 @@ func init() {
 		"Comma-separated environments")
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false,
 		"Overwrite existing file")
-	initCmd.Flags().BoolVarP(&initTemplate, "template", "t", false,
-		"Create with example comments only (skip wizard)")
+	initCmd.Flags().StringVarP(&initTemplate, "template", "t", "",
+		"Create with example comments only, or from a preset (skip wizard; 'list' shows presets)")
+	initCmd.Flags().Lookup("template").NoOptDefVal = templateGeneric
 
 	rootCmd.AddCommand(initCmd)
 }
 
-func runInit(_ *cobra.Command, _ []string) error {
+func runInit(_ *cobra.Command, args []string) error {
+	// The flag takes an optional value, so "--template web" leaves the name
+	// as an argument
+	if len(args) > 0 {
+		if initTemplate != templateGeneric {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		initTemplate = args[0]
+	}
+
+	if initTemplate == templateList {
+		printInitPresets()
+		return nil
+	}
+
+	var preset *initPreset
+	if initTemplate != "" && initTemplate != templateGeneric {
+		p, ok := initPresets[initTemplate]
+		if !ok {
+			return fmt.Errorf("unknown template %q (use: %s)", initTemplate, strings.Join(initPresetNames(), ", "))
+		}
+		preset = &p
+	}
+
 	// Check if file exists
 	if _, err := os.Stat(initPath); err == nil && !initForce {
 		return fmt.Errorf("file %s already exists (use --force to overwrite)", initPath)
@@ -80,7 +172,7 @@ func runInit(_ *cobra.Command, _ []string) error {
 	fmt.Fprintln(writer, "# Annotation syntax:")
 	fmt.Fprintln(writer, "#   VAR=default #prompt:Question?|type;constraint:value")
 	fmt.Fprintln(writer, "#")
-	fmt.Fprintln(writer, "# Types: string, int, numeric, boolean, enum, object")
+	fmt.Fprintln(writer, "# Types: "+strings.Join(initTypes, ", "))
 	fmt.Fprintln(writer, "# Modifiers: optional, secret")
 	fmt.Fprintln(writer, "#")
 	fmt.Fprintln(writer, "# Examples:")
@@ -91,16 +183,27 @@ func runInit(_ *cobra.Command, _ []string) error {
 	fmt.Fprintln(writer, "# Run: krakenv generate .env.local")
 	fmt.Fprintln(writer, "# ========================================")
 	fmt.Fprintln(writer)
-	fmt.Fprintln(writer, "# Add your variables below:")
-	fmt.Fprintln(writer)
+	if preset != nil {
+		fmt.Fprintf(writer, "# %s (preset: %s)\n", preset.description, initTemplate)
+		for _, v := range preset.variables {
+			fmt.Fprintln(writer, parser.FormatLine(v.name, v.value, v.annotation))
+		}
+	} else {
+		fmt.Fprintln(writer, "# Add your variables below:")
+		fmt.Fprintln(writer)
+	}
 
 	if err := writer.Flush(); err != nil {
 		return fmt.Errorf("failed to write: %w", err)
 	}
 
 	if !quiet {
-		fmt.Printf("✓ Created %s\n", initPath)
-		if !initTemplate {
+		if preset != nil {
+			fmt.Printf("✓ Created %s with %d variables from the %s preset\n", initPath, len(preset.variables), initTemplate)
+		} else {
+			fmt.Printf("✓ Created %s\n", initPath)
+		}
+		if initTemplate == "" {
 			fmt.Println("\nTo add variables interactively:")
 			fmt.Println("  krakenv add VAR_NAME --type string --prompt \"Question?\"")
 			fmt.Println("\nTo generate environment files:")
@@ -109,13 +212,37 @@ func runInit(_ *cobra.Command, _ []string) error {
 	}
 
 	// Run wizard if not template mode
-	if !initTemplate && !nonInteractive {
+	if initTemplate == "" && !nonInteractive {
 		return runInitWizard(initPath)
 	}
 
 	return nil
 }
 
+// initPresetNames returns the preset names in alphabetical order.
+func initPresetNames() []string {
+	names := make([]string, 0, len(initPresets))
+	for name := range initPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printInitPresets lists the presets and the variables each one scaffolds.
+func printInitPresets() {
+	fmt.Println("Available templates:")
+	for _, name := range initPresetNames() {
+		preset := initPresets[name]
+		vars := make([]string, len(preset.variables))
+		for i, v := range preset.variables {
+			vars[i] = v.name
+		}
+		fmt.Printf("  %-10s %s\n", name, preset.description)
+		fmt.Printf("  %-10s %s\n", "", strings.Join(vars, ", "))
+	}
+}
+
 func runInitWizard(path string) error {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -138,44 +265,28 @@ func runInitWizard(path string) error {
 		}
 
 		// Type
-		fmt.Print("Type? [string/int/numeric/boolean/enum/object]: ")
-		typeStr, _ := reader.ReadString('\n')
-		typeStr = strings.TrimSpace(typeStr)
-		if typeStr == "" {
-			typeStr = "string"
-		}
+		spec := annotationSpec{varType: askInitType(reader)}
 
-		// Constraints
-		fmt.Print("Constraints? (e.g., min:1;max:100): ")
-		constraints, _ := reader.ReadString('\n')
-		constraints = strings.TrimSpace(constraints)
+		// Constraints relevant to the type
+		if err := askTypeConstraints(reader, &spec); err != nil {
+			return err
+		}
 
 		// Default
-		fmt.Print("Default value? (optional): ")
-		defaultVal, _ := reader.ReadString('\n')
-		defaultVal = strings.TrimSpace(defaultVal)
+		defaultVal := askLine(reader, "Default value? (optional): ")
 
 		// Prompt
-		fmt.Print("Prompt message: ")
-		prompt, _ := reader.ReadString('\n')
-		prompt = strings.TrimSpace(prompt)
-		if prompt == "" {
-			prompt = "Enter " + name
+		spec.prompt = askLine(reader, "Prompt message: ")
+		if spec.prompt == "" {
+			spec.prompt = "Enter " + name
 		}
 
-		// Optional
-		fmt.Print("Is it optional? [y/n]: ")
-		optionalStr, _ := reader.ReadString('\n')
-		optional := strings.ToLower(strings.TrimSpace(optionalStr)) == "y"
-
-		// Secret
-		fmt.Print("Is it secret? [y/n]: ")
-		secretStr, _ := reader.ReadString('\n')
-		secret := strings.ToLower(strings.TrimSpace(secretStr)) == "y"
+		// Modifiers
+		spec.optional = askYesNo(reader, "Is it optional?")
+		spec.secret = askYesNo(reader, "Is it secret?")
 
 		// Build and append
-		annotation := buildWizardAnnotation(typeStr, prompt, constraints, optional, secret)
-		line := name + "=" + defaultVal + " " + annotation
+		line := parser.FormatLine(name, defaultVal, buildAnnotation(spec))
 
 		// Append to file
 		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
@@ -200,24 +311,78 @@ func runInitWizard(path string) error {
 	return nil
 }
 
-func buildWizardAnnotation(typeStr, prompt, constraints string, optional, secret bool) string {
-	parts := []string{typeStr}
+// initTypes lists the types the init wizard accepts.
+var initTypes = []string{"string", "int", "numeric", "boolean", "enum", "object", "url", "email",
+	"duration", "ip", "cidr", "semver", "path", "hostname", "date"}
 
-	if constraints != "" {
-		for _, c := range strings.Split(constraints, ";") {
-			c = strings.TrimSpace(c)
-			if c != "" {
-				parts = append(parts, c)
-			}
+// askLine prints a question and returns the trimmed answer.
+func askLine(reader *bufio.Reader, question string) string {
+	fmt.Print(question)
+	answer, _ := reader.ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// askYesNo asks a [y/n] question; only y or yes counts as yes.
+func askYesNo(reader *bufio.Reader, question string) bool {
+	answer := strings.ToLower(askLine(reader, question+" [y/n]: "))
+	return answer == "y" || answer == "yes"
+}
+
+// askInitType asks for a variable type until a known one is given. An empty
+// answer picks string.
+func askInitType(reader *bufio.Reader) string {
+	question := "Type? [" + strings.Join(initTypes, "/") + "]: "
+	for {
+		typeStr := strings.ToLower(askLine(reader, question))
+		if typeStr == "" {
+			return "string"
 		}
+		if slices.Contains(initTypes, typeStr) {
+			return typeStr
+		}
+		fmt.Printf("  Unknown type %q.\n", typeStr)
 	}
+}
 
-	if optional {
-		parts = append(parts, "optional")
-	}
-	if secret {
-		parts = append(parts, "secret")
+// askTypeConstraints asks for the constraints that apply to spec's type.
+// Empty answers leave a constraint out. Fails if input ends before a
+// required constraint is given.
+func askTypeConstraints(reader *bufio.Reader, spec *annotationSpec) error {
+	switch spec.varType {
+	case "int", "numeric", "duration", "semver":
+		spec.min = askLine(reader, "Minimum? (optional): ")
+		spec.max = askLine(reader, "Maximum? (optional): ")
+	case "string":
+		spec.minlen = askLine(reader, "Minimum length? (optional): ")
+		spec.maxlen = askLine(reader, "Maximum length? (optional): ")
+		spec.pattern = askLine(reader, "Pattern (regex)? (optional): ")
+	case "enum":
+		for spec.options == "" {
+			fmt.Print("Options? (comma-separated): ")
+			answer, err := reader.ReadString('\n')
+			spec.options = strings.TrimSpace(answer)
+			if err != nil && spec.options == "" {
+				return fmt.Errorf("enum options are required: %w", err)
+			}
+		}
+	case "object":
+		spec.format = askLine(reader, "Format? [json/yaml] (optional): ")
+	case "url":
+		spec.schemes = askLine(reader, "Allowed schemes? (comma-separated, optional): ")
+	case "ip", "cidr":
+		spec.ipVersion = askLine(reader, "IP version? [4/6/4,6] (optional): ")
+	case "path":
+		spec.abs = askYesNo(reader, "Must it be absolute?")
+		spec.exists = askYesNo(reader, "Must it exist when validating?")
+		if spec.exists {
+			spec.kind = askLine(reader, "Kind? [file/dir] (optional): ")
+		}
+	case "hostname":
+		spec.allowIP = askYesNo(reader, "Also accept IP addresses?")
+	case "date":
+		spec.layout = askLine(reader, "Layout? (optional, default 2006-01-02): ")
+		spec.after = askLine(reader, "After date? (optional): ")
+		spec.before = askLine(reader, "Before date? (optional): ")
 	}
-
-	return "#prompt:" + prompt + "|" + strings.Join(parts, ";")
+	return nil
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/lint"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestAskTypeConstraints_EnumOptions(t *testing.T) {
+	t.Run("asks again until options are given", func(t *testing.T) {
+		spec := annotationSpec{varType: "enum"}
+		reader := bufio.NewReader(strings.NewReader("\n\ndev,prod\n"))
+		require.NoError(t, askTypeConstraints(reader, &spec))
+		assert.Equal(t, "dev,prod", spec.options)
+	})
+
+	t.Run("last line without newline", func(t *testing.T) {
+		spec := annotationSpec{varType: "enum"}
+		reader := bufio.NewReader(strings.NewReader("dev,prod"))
+		require.NoError(t, askTypeConstraints(reader, &spec))
+		assert.Equal(t, "dev,prod", spec.options)
+	})
+
+	t.Run("end of input fails", func(t *testing.T) {
+		spec := annotationSpec{varType: "enum"}
+		reader := bufio.NewReader(strings.NewReader("\n"))
+		err := askTypeConstraints(reader, &spec)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestRunInit_TemplatesPassLint(t *testing.T) {
+	for _, template := range []string{templateGeneric, "web"} {
+		t.Run(template, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env.dist")
+			setGlobal(t, &initPath, path)
+			setGlobal(t, &initTemplate, template)
+			setGlobal(t, &initForce, false)
+			setGlobal(t, &quiet, true)
+
+			require.NoError(t, runInit(nil, nil))
+
+			envFile, err := parser.ParseEnvFile(path)
+			require.NoError(t, err)
+			assert.Empty(t, envFile.Warnings)
+			assert.Empty(t, lint.Lint(envFile))
+		})
+	}
+}
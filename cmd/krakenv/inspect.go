@@ -4,18 +4,30 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/theburrowhub/krakenv/internal/generator"
 	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/log"
 	"github.com/theburrowhub/krakenv/internal/parser"
 	"github.com/theburrowhub/krakenv/internal/tui/sync"
 )
 
 var (
-	inspectSync bool
-	inspectJSON bool
+	inspectSync    bool
+	inspectBackup  string
+	inspectJSON    bool
+	inspectNoColor bool
+	inspectSecrets bool
+	inspectCount   string
+	inspectFailOn  []string
+	inspectPrune   bool
+	inspectIgnore  []string
+	inspectReport  string
 )
 
 var inspectCmd = &cobra.Command{
@@ -26,15 +38,26 @@ var inspectCmd = &cobra.Command{
   - Variables in environment file not present in distributable
   - Variables with invalid values
 
+Use --report-file to also save the full report (JSON with --json) to a
+file; it is written even with --quiet or --count.
+
 Exit codes:
   0 - No discrepancies found
-  1 - Discrepancies found (report generated)
+  1 - Discrepancies found (report generated); with --fail-on, only
+      discrepancies of the listed kinds count
   2 - File not found or unreadable
 
 Examples:
   krakenv inspect .env.local
   krakenv inspect .env.local --sync
-  krakenv inspect .env.testing --json | jq '.missing | length'`,
+  krakenv inspect .env.ci --sync --non-interactive --prune
+  krakenv inspect .env.testing --json | jq '.missing | length'
+  krakenv inspect .env.ci --no-color > inspect.log
+  krakenv inspect .env.local --count
+  krakenv inspect .env.local --count=missing
+  krakenv inspect .env.local --fail-on missing,invalid
+  krakenv inspect .env --ignore 'KUBERNETES_*,CI_*'
+  krakenv inspect .env.ci --quiet --report-file reports/inspect.txt`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInspect,
 }
@@ -42,8 +65,26 @@ Examples:
 func init() {
 	inspectCmd.Flags().BoolVarP(&inspectSync, "sync", "s", false,
 		"Interactively sync discrepancies")
+	inspectCmd.Flags().StringVar(&inspectBackup, "backup", "",
+		"Back up the target before sync rewrites it (simple: <path>.bak, timestamp: <path>.<time>.bak)")
+	inspectCmd.Flags().Lookup("backup").NoOptDefVal = generator.BackupSimple
 	inspectCmd.Flags().BoolVarP(&inspectJSON, "json", "j", false,
 		"Output as JSON (for scripting)")
+	inspectCmd.Flags().BoolVar(&inspectNoColor, "no-color", false,
+		"Print the report without colors or styling")
+	inspectCmd.Flags().BoolVar(&inspectSecrets, "show-secrets", false,
+		"Print values of secret variables instead of masking them")
+	inspectCmd.Flags().StringVar(&inspectCount, "count", "",
+		"Print only discrepancy counts: missing=N extra=N invalid=N, or one count (missing, extra, invalid)")
+	inspectCmd.Flags().Lookup("count").NoOptDefVal = "all"
+	inspectCmd.Flags().StringSliceVar(&inspectFailOn, "fail-on", []string{"missing", "invalid", "extra"},
+		"Discrepancy kinds that make the exit code 1 (missing, invalid, extra)")
+	inspectCmd.Flags().StringSliceVar(&inspectIgnore, "ignore", nil,
+		"Glob patterns of extra variables to leave out, such as 'KUBERNETES_*' (comma-separated)")
+	inspectCmd.Flags().BoolVar(&inspectPrune, "prune", false,
+		"With --sync --non-interactive, remove variables not in the distributable")
+	inspectCmd.Flags().StringVar(&inspectReport, "report-file", "",
+		"Also write the full report (JSON with --json) to this file")
 
 	rootCmd.AddCommand(inspectCmd)
 }
@@ -51,6 +92,18 @@ func init() {
 func runInspect(_ *cobra.Command, args []string) error {
 	targetPath := args[0]
 
+	if inspectBackup != "" && !generator.IsValidBackupMode(inspectBackup) {
+		return fmt.Errorf("invalid backup mode %q (use: simple, timestamp)", inspectBackup)
+	}
+	for _, kind := range inspectFailOn {
+		if !inspector.IsValidKind(kind) {
+			return fmt.Errorf("invalid --fail-on kind %q (use: missing, invalid, extra)", kind)
+		}
+	}
+	if inspectPrune && (!inspectSync || !nonInteractive) {
+		return fmt.Errorf("--prune requires --sync and --non-interactive")
+	}
+
 	// Check target exists
 	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", targetPath)
@@ -58,9 +111,9 @@ func runInspect(_ *cobra.Command, args []string) error {
 	}
 
 	// Parse distributable
-	distFile, err := parser.ParseEnvFile(distPath)
+	distFile, err := loadDistributable()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distPath, err)
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distLabel(), err)
 		os.Exit(2)
 	}
 
@@ -73,6 +126,17 @@ func runInspect(_ *cobra.Command, args []string) error {
 
 	// Run inspection
 	result := inspector.Inspect(distFile, targetFile)
+	result.ShowSecrets = inspectSecrets
+	if err := result.IgnoreExtras(inspectIgnore); err != nil {
+		return err
+	}
+	logInspection(result)
+
+	if inspectReport != "" {
+		if err := writeInspectReport(result, inspectReport); err != nil {
+			return err
+		}
+	}
 
 	// Handle sync mode
 	if inspectSync && result.HasDiscrepancies() {
@@ -84,20 +148,65 @@ func runInspect(_ *cobra.Command, args []string) error {
 	}
 
 	// Output results
-	if inspectJSON {
+	if inspectCount != "" {
+		count, err := result.FormatCount(inspectCount)
+		if err != nil {
+			return err
+		}
+		fmt.Println(count)
+	} else if inspectJSON {
 		jsonOutput, err := result.FormatJSON()
 		if err != nil {
 			return fmt.Errorf("failed to format JSON: %w", err)
 		}
 		fmt.Println(jsonOutput)
+	} else if inspectNoColor && !quiet {
+		fmt.Print(result.FormatPlainReport())
 	} else if !quiet {
 		fmt.Print(result.FormatReport())
 	}
 
-	if result.HasDiscrepancies() {
-		os.Exit(1)
+	for _, kind := range inspectFailOn {
+		if n, _ := result.Count(kind); n > 0 {
+			os.Exit(1)
+		}
+	}
+
+	return nil
+}
+
+// logInspection logs what the inspection found for each variable. Secret
+// values quoted in messages are masked even with --show-secrets.
+func logInspection(result *inspector.InspectionResult) {
+	redact := inspector.Redactor{Secrets: result.Secrets}
+	for _, v := range result.MissingInEnv {
+		log.Debug("variable missing from target", "name", v.Name)
+	}
+	for _, v := range result.ExtraInEnv {
+		log.Debug("variable not in distributable", "name", v.Name)
+	}
+	for _, e := range result.InvalidValues {
+		log.Debug("variable invalid", "name", e.Variable,
+			"error", redact.Text(e.Variable, result.CurrentValues[e.Variable], e.Message))
 	}
+	log.Debug("target inspected", "target", result.TargetPath, "valid", result.ValidCount,
+		"missing", len(result.MissingInEnv), "extra", len(result.ExtraInEnv), "invalid", len(result.InvalidValues))
+}
 
+// writeInspectReport saves the report of an inspection to path, without
+// colors, or as JSON with --json.
+func writeInspectReport(result *inspector.InspectionResult, path string) error {
+	report := result.FormatPlainReport()
+	if inspectJSON {
+		jsonOutput, err := result.FormatJSON()
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		report = jsonOutput + "\n"
+	}
+	if err := writeReportFile(path, report); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
 	return nil
 }
 
@@ -163,35 +272,61 @@ func applyResolutions(resolutions []sync.Resolution, _, targetFile *parser.EnvFi
 }
 
 func updateTargetFile(targetFile *parser.EnvFile, targetPath string, updates map[string]string, removes map[string]bool) error {
-	file, err := os.Create(targetPath)
-	if err != nil {
-		return err
+	if inspectBackup != "" {
+		backupPath, err := generator.BackupFile(targetPath, inspectBackup)
+		if err != nil {
+			return err
+		}
+		if backupPath != "" && verbose && !quiet {
+			fmt.Printf("Backed up previous %s to %s\n", targetPath, backupPath)
+		}
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-
-	// Write existing variables (updated or kept)
-	for _, v := range targetFile.Variables {
-		if removes[v.Name] {
-			continue // Skip removed variables
+	// Walk the original lines so comments, blanks and formatting survive
+	written := make(map[string]bool)
+	var lines []string
+	for _, line := range targetFile.Lines {
+		if line.Kind != parser.LineVariable {
+			lines = append(lines, line.Text)
+			continue
 		}
-
-		value := v.Value
-		if newVal, ok := updates[v.Name]; ok {
-			value = newVal
-			delete(updates, v.Name) // Mark as written
+		if removes[line.Name] {
+			// Drop an annotation line directly above the variable too
+			if n := len(lines); n > 0 && parser.IsStandaloneAnnotation(lines[n-1]) {
+				lines = lines[:n-1]
+			}
+			continue
 		}
 
-		fmt.Fprintf(writer, "%s=%s\n", v.Name, value)
+		text := line.Text
+		if value, ok := updates[line.Name]; ok {
+			text = parser.ReplaceLineValue(text, value)
+			written[line.Name] = true
+		}
+		lines = append(lines, text)
 	}
 
-	// Write new variables (from updates that weren't in target)
-	for name, value := range updates {
-		fmt.Fprintf(writer, "%s=%s\n", name, value)
+	// Append new variables (from updates that weren't in target)
+	var added []string
+	for name := range updates {
+		if !written[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		lines = append(lines, parser.FormatVariable(parser.Variable{Name: name, Value: updates[name]}, false))
 	}
 
-	return writer.Flush()
+	var content string
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
+		return err
+	}
+	log.Info("file written", "path", targetPath, "updated", len(updates), "removed", len(removes))
+	return nil
 }
 
 func addToDistributable(distPath string, resolutions []sync.Resolution) error {
@@ -225,15 +360,11 @@ func addToDistributable(distPath string, resolutions []sync.Resolution) error {
 	}
 
 	for _, r := range resolutions {
-		v := r.Variable
+		var ann string
 		if r.Annotation != nil {
-			// Add with annotation
-			ann := parser.FormatAnnotation(r.Annotation)
-			fmt.Fprintf(writer, "%s=%s %s\n", v.Name, v.Value, ann)
-		} else {
-			// Add as simple variable without annotation
-			fmt.Fprintf(writer, "%s=%s\n", v.Name, v.Value)
+			ann = parser.FormatAnnotation(r.Annotation)
 		}
+		fmt.Fprintln(writer, parser.FormatLine(r.Variable.Name, r.Variable.Value, ann))
 	}
 
 	return writer.Flush()
@@ -250,8 +381,8 @@ func handleNonInteractiveSync(result *inspector.InspectionResult, _, targetFile
 			updates[v.Name] = "" // Empty value for optional
 			continue
 		}
-		if v.Value != "" {
-			updates[v.Name] = v.Value // Use default value
+		if value := generator.DistDefault(v, targetPath); value != "" {
+			updates[v.Name] = value // Use default value for the target's environment
 			continue
 		}
 		unresolvable = append(unresolvable, v.Name)
@@ -267,13 +398,32 @@ func handleNonInteractiveSync(result *inspector.InspectionResult, _, targetFile
 		os.Exit(2)
 	}
 
+	// With --prune, extras are removed instead of left in place
+	var pruned []string
+	removes := make(map[string]bool)
+	if inspectPrune {
+		for _, v := range result.ExtraInEnv {
+			pruned = append(pruned, v.Name)
+			removes[v.Name] = true
+		}
+	}
+
 	// Apply updates
-	if len(updates) > 0 {
-		if err := updateTargetFile(targetFile, targetPath, updates, nil); err != nil {
+	synced := len(updates)
+	if synced > 0 || len(removes) > 0 {
+		if err := updateTargetFile(targetFile, targetPath, updates, removes); err != nil {
 			return fmt.Errorf("failed to update target file: %w", err)
 		}
-		if !quiet {
-			fmt.Printf("✓ Auto-synced %d variable(s) in %s\n", len(updates), targetPath)
+	}
+	if !quiet {
+		if synced > 0 {
+			fmt.Printf("✓ Auto-synced %d variable(s) in %s\n", synced, targetPath)
+		}
+		if len(pruned) > 0 {
+			fmt.Printf("✓ Pruned %d variable(s) from %s:\n", len(pruned), targetPath)
+			for _, name := range pruned {
+				fmt.Printf("  - %s\n", name)
+			}
 		}
 	}
 
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/tui/sync"
+)
+
+func TestAddToDistributable_QuotesValues(t *testing.T) {
+	distPath := filepath.Join(t.TempDir(), ".env.dist")
+	require.NoError(t, os.WriteFile(distPath, []byte("PORT=8080"), 0644))
+
+	err := addToDistributable(distPath, []sync.Resolution{
+		{Variable: parser.Variable{Name: "GREETING", Value: "a b #1"}},
+		{
+			Variable:   parser.Variable{Name: "NOTE", Value: "x #y"},
+			Annotation: &parser.Annotation{PromptText: "Note?", Type: parser.TypeString},
+		},
+	})
+	require.NoError(t, err)
+
+	distFile, err := parser.ParseEnvFile(distPath)
+	require.NoError(t, err)
+	require.Len(t, distFile.Variables, 3)
+	assert.Equal(t, "a b #1", distFile.GetVariable("GREETING").Value)
+	note := distFile.GetVariable("NOTE")
+	assert.Equal(t, "x #y", note.Value)
+	require.NotNil(t, note.Annotation)
+	assert.Equal(t, "Note?", note.Annotation.PromptText)
+}
+
+func TestHandleNonInteractiveSync_EnvDefaults(t *testing.T) {
+	dist, err := parser.ParseEnvFileContent(
+		"LOG_LEVEL=info #prompt:Level?|enum;options:debug,info,warn;default@production:warn\n", ".env.dist")
+	require.NoError(t, err)
+	targetPath := writeTestFile(t, t.TempDir(), ".env.production", "")
+	target, err := parser.ParseEnvFile(targetPath)
+	require.NoError(t, err)
+	setGlobal(t, &quiet, true)
+
+	result := inspector.Inspect(dist, target)
+	require.NoError(t, handleNonInteractiveSync(result, dist, target, targetPath))
+	assert.Equal(t, "LOG_LEVEL=warn\n", readTestFile(t, targetPath))
+}
+
+func TestUpdateTargetFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		updates map[string]string
+		removes map[string]bool
+		want    string
+	}{
+		{
+			name:    "updates keep comments and annotations",
+			content: "# Server\nHOST=old # current host\nPORT=80 #prompt:Port?|int\n",
+			updates: map[string]string{"HOST": "new", "PORT": "8080"},
+			want:    "# Server\nHOST=new # current host\nPORT=8080 #prompt:Port?|int\n",
+		},
+		{
+			name:    "new variables appended in order",
+			content: "HOST=x\n",
+			updates: map[string]string{"ZETA": "z", "ALPHA": "a b"},
+			want:    "HOST=x\nALPHA=\"a b\"\nZETA=z\n",
+		},
+		{
+			name:    "removes drop the annotation above",
+			content: "A=1\n#prompt:Key?|string\nKEY=x\nB=2\n",
+			removes: map[string]bool{"KEY": true},
+			want:    "A=1\nB=2\n",
+		},
+		{
+			name:    "removing every variable",
+			content: "KEY=x\n",
+			removes: map[string]bool{"KEY": true},
+			want:    "",
+		},
+		{
+			name:    "heredoc body replaced",
+			content: heredocFile,
+			updates: map[string]string{"CERT": "new\ncert"},
+			want:    "# Certificates\nCERT=<<EOF #prompt:Certificate?|string\nnew\ncert\nEOF\nFOO=bar\n",
+		},
+		{
+			name:    "heredoc to single line",
+			content: heredocFile,
+			updates: map[string]string{"CERT": "none"},
+			want:    "# Certificates\nCERT=none #prompt:Certificate?|string\nFOO=bar\n",
+		},
+		{
+			name:    "heredoc removed without touching its body's lookalikes",
+			content: heredocFile,
+			removes: map[string]bool{"CERT": true},
+			want:    "# Certificates\nFOO=bar\n",
+		},
+		{
+			name:    "variable named in a heredoc body",
+			content: heredocFile,
+			updates: map[string]string{"FOO": "baz"},
+			want:    strings.Replace(heredocFile, "FOO=bar", "FOO=baz", 1),
+		},
+		{
+			name:    "appended multi-line value",
+			content: "HOST=x\n",
+			updates: map[string]string{"KEY": "a\nb"},
+			want:    "HOST=x\nKEY=<<EOF\na\nb\nEOF\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, t.TempDir(), ".env.local", tt.content)
+			target, err := parser.ParseEnvFile(path)
+			require.NoError(t, err)
+			setGlobal(t, &inspectBackup, "")
+
+			require.NoError(t, updateTargetFile(target, path, tt.updates, tt.removes))
+			assert.Equal(t, tt.want, readTestFile(t, path))
+		})
+	}
+}
+
+func TestRunInspect_FailOn(t *testing.T) {
+	dist := "PORT=8080 #prompt:Port?|int\nHOST=localhost #prompt:Host?|string\n"
+
+	tests := []struct {
+		name   string
+		target string
+		failOn []string
+		code   int
+	}{
+		{"no discrepancies", "PORT=80\nHOST=x\n", []string{"missing", "invalid", "extra"}, 0},
+		{"extra by default", "PORT=80\nHOST=x\nLEGACY=1\n", []string{"missing", "invalid", "extra"}, 1},
+		{"extra not selected", "PORT=80\nHOST=x\nLEGACY=1\n", []string{"missing", "invalid"}, 0},
+		{"missing selected", "PORT=80\n", []string{"missing"}, 1},
+		{"missing not selected", "PORT=80\n", []string{"invalid", "extra"}, 0},
+		{"invalid selected", "PORT=abc\nHOST=x\nLEGACY=1\n", []string{"invalid"}, 1},
+		{"invalid not selected", "PORT=abc\nHOST=x\n", []string{"missing"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setGlobal(t, &distPaths, []string{writeTestFile(t, dir, ".env.dist", dist)})
+			target := writeTestFile(t, dir, ".env.local", tt.target)
+			setGlobal(t, &inspectFailOn, tt.failOn)
+			setGlobal(t, &quiet, true)
+
+			code, _, _ := runExit(t, func() {
+				require.NoError(t, runInspect(nil, []string{target}))
+			})
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func TestRunInspect_FailOnInvalidKind(t *testing.T) {
+	setGlobal(t, &inspectFailOn, []string{"missing", "typo"})
+
+	err := runInspect(nil, []string{".env.local"})
+	assert.ErrorContains(t, err, `invalid --fail-on kind "typo"`)
+}
+
+func TestHandleNonInteractiveSync_Prune(t *testing.T) {
+	dist, err := parser.ParseEnvFileContent("PORT=8080 #prompt:Port?|int\nDEBUG= #prompt:Debug?|boolean;optional\n", ".env.dist")
+	require.NoError(t, err)
+	content := "# App\nPORT=80\nLEGACY=1\n#prompt:Old?|string\nOLD_HOST=x\n"
+
+	tests := []struct {
+		name   string
+		prune  bool
+		quiet  bool
+		want   string
+		stdout string
+	}{
+		{
+			name:   "without prune",
+			want:   content + "DEBUG=\n",
+			stdout: "✓ Auto-synced 1 variable(s) in %s\n",
+		},
+		{
+			name:   "prune",
+			prune:  true,
+			want:   "# App\nPORT=80\nDEBUG=\n",
+			stdout: "✓ Auto-synced 1 variable(s) in %[1]s\n✓ Pruned 2 variable(s) from %[1]s:\n  - LEGACY\n  - OLD_HOST\n",
+		},
+		{
+			name:  "prune quietly",
+			prune: true,
+			quiet: true,
+			want:  "# App\nPORT=80\nDEBUG=\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := writeTestFile(t, t.TempDir(), ".env.ci", content)
+			target, err := parser.ParseEnvFile(targetPath)
+			require.NoError(t, err)
+			setGlobal(t, &inspectPrune, tt.prune)
+			setGlobal(t, &quiet, tt.quiet)
+
+			out := captureStdout(t, func() {
+				result := inspector.Inspect(dist, target)
+				require.NoError(t, handleNonInteractiveSync(result, dist, target, targetPath))
+			})
+			assert.Equal(t, tt.want, readTestFile(t, targetPath))
+			want := ""
+			if tt.stdout != "" {
+				want = fmt.Sprintf(tt.stdout, targetPath)
+			}
+			assert.Equal(t, want, out)
+		})
+	}
+}
+
+func TestRunInspect_PruneRequiresSync(t *testing.T) {
+	setGlobal(t, &inspectPrune, true)
+	setGlobal(t, &inspectSync, true)
+	setGlobal(t, &nonInteractive, false)
+
+	assert.ErrorContains(t, runInspect(nil, []string{".env.ci"}), "--prune requires --sync and --non-interactive")
+}
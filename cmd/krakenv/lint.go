@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/lint"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Check the distributable for annotation problems",
+	Long: `Check a distributable file for common authoring problems:
+
+  - malformed annotations and unknown constraints or modifiers
+  - several variables sharing the same prompt after a copy-paste
+  - enums with a single option
+  - secrets with a default value
+  - patterns that are not valid regular expressions
+
+Exit codes:
+  0 - No problems found
+  1 - Problems found
+  2 - File not found or unreadable
+
+Examples:
+  krakenv lint
+  krakenv lint config/.env.template`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(_ *cobra.Command, args []string) error {
+	path := distPath
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", path)
+		os.Exit(2)
+	}
+
+	envFile, err := loadDistFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse %s: %v\n", path, err)
+		os.Exit(2)
+	}
+
+	issues := lint.Lint(envFile)
+
+	if len(issues) == 0 {
+		if !quiet {
+			fmt.Printf("✓ LINT PASSED: %s\n", path)
+		}
+		return nil
+	}
+
+	if !quiet {
+		printLintIssues(path, issues)
+	}
+
+	os.Exit(1)
+	return nil
+}
+
+// printLintIssues prints the lint report for a file with problems.
+func printLintIssues(path string, issues []lint.Issue) {
+	fmt.Printf("✗ LINT FAILED: %s\n\n", path)
+	for _, issue := range issues {
+		fmt.Print(issue.Format())
+	}
+	fmt.Printf("\nFound %d problem(s)\n", len(issues))
+}
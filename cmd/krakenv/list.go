@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var (
+	listJSON      bool
+	listNamesOnly bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List variables defined in the distributable",
+	Long: `List every variable defined in the distributable with its type,
+modifiers, prompt and description.
+
+Examples:
+  krakenv list
+  krakenv list --json | jq '.[] | select(.secret)'
+  krakenv list --names-only | xargs -n1 echo`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().BoolVarP(&listJSON, "json", "j", false,
+		"Output as JSON (for scripting)")
+	listCmd.Flags().BoolVar(&listNamesOnly, "names-only", false,
+		"Print only variable names, one per line")
+
+	rootCmd.AddCommand(listCmd)
+}
+
+// listEntry is a variable in `list --json` output.
+type listEntry struct {
+	inspector.JSONVariable
+	Optional bool `json:"optional"`
+	Secret   bool `json:"secret"`
+}
+
+func runList(_ *cobra.Command, _ []string) error {
+	if _, err := os.Stat(distPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: Distributable not found: %s\n", distPath)
+		os.Exit(2)
+	}
+
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	if listNamesOnly {
+		for _, v := range distFile.Variables {
+			fmt.Println(v.Name)
+		}
+		return nil
+	}
+
+	if listJSON {
+		entries := make([]listEntry, 0, len(distFile.Variables))
+		for _, v := range distFile.Variables {
+			entry := listEntry{JSONVariable: inspector.JSONVariable{Name: v.Name}}
+			if v.Annotation != nil {
+				entry.Prompt = v.Annotation.PromptText
+				entry.Description = v.Annotation.Description
+				entry.Type = v.Annotation.Type.String()
+				entry.Optional = v.Annotation.IsOptional
+				entry.Secret = v.Annotation.IsSecret
+			}
+			if !entry.Secret {
+				entry.Value = v.Value
+			}
+			entries = append(entries, entry)
+		}
+
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tFLAGS\tPROMPT\tDESCRIPTION")
+	for _, v := range distFile.Variables {
+		typ, flags, prompt, desc := "-", "-", "-", "-"
+		if v.Annotation != nil {
+			typ = v.Annotation.Type.String()
+			prompt = v.Annotation.PromptText
+			if v.Annotation.Description != "" {
+				desc = v.Annotation.Description
+			}
+			if f := annotationFlags(v.Annotation); f != "" {
+				flags = f
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.Name, typ, flags, prompt, desc)
+	}
+
+	return w.Flush()
+}
+
+// annotationFlags returns the annotation's modifiers as a comma-separated list.
+func annotationFlags(ann *parser.Annotation) string {
+	var flags []string
+	if ann.IsOptional {
+		flags = append(flags, "optional")
+	}
+	if ann.IsSecret {
+		flags = append(flags, "secret")
+	}
+	return strings.Join(flags, ",")
+}
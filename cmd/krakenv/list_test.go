@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/inspector"
+)
+
+const listFile = `PORT=8080 #prompt:Port?|int
+API_KEY=dev-key #prompt:API key?|string;secret;desc:Issued by ops
+DEBUG= #prompt:Debug?|boolean;optional
+PLAIN=x
+`
+
+func TestRunList(t *testing.T) {
+	setGlobal(t, &distPath, writeTestFile(t, t.TempDir(), ".env.dist", listFile))
+	setGlobal(t, &listJSON, false)
+	setGlobal(t, &listNamesOnly, false)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(nil, nil))
+	})
+	assert.Equal(t, ""+
+		"NAME     TYPE     FLAGS     PROMPT    DESCRIPTION\n"+
+		"PORT     int      -         Port?     -\n"+
+		"API_KEY  string   secret    API key?  Issued by ops\n"+
+		"DEBUG    boolean  optional  Debug?    -\n"+
+		"PLAIN    -        -         -         -\n", out)
+}
+
+func TestRunList_NamesOnly(t *testing.T) {
+	setGlobal(t, &distPath, writeTestFile(t, t.TempDir(), ".env.dist", listFile))
+	setGlobal(t, &listJSON, true)
+	setGlobal(t, &listNamesOnly, true)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(nil, nil))
+	})
+	assert.Equal(t, "PORT\nAPI_KEY\nDEBUG\nPLAIN\n", out)
+}
+
+func TestRunList_JSON(t *testing.T) {
+	setGlobal(t, &distPath, writeTestFile(t, t.TempDir(), ".env.dist", listFile))
+	setGlobal(t, &listJSON, true)
+	setGlobal(t, &listNamesOnly, false)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(nil, nil))
+	})
+
+	var entries []listEntry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	assert.Equal(t, []listEntry{
+		{JSONVariable: inspector.JSONVariable{Name: "PORT", Prompt: "Port?", Type: "int", Value: "8080"}},
+		{JSONVariable: inspector.JSONVariable{Name: "API_KEY", Prompt: "API key?", Description: "Issued by ops", Type: "string"}, Secret: true},
+		{JSONVariable: inspector.JSONVariable{Name: "DEBUG", Prompt: "Debug?", Type: "boolean"}, Optional: true},
+		{JSONVariable: inspector.JSONVariable{Name: "PLAIN", Value: "x"}},
+	}, entries)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setGlobal sets a command flag variable for the duration of a test.
+func setGlobal[T any](t *testing.T, p *T, v T) {
+	t.Helper()
+	old := *p
+	*p = v
+	t.Cleanup(func() { *p = old })
+}
+
+// writeTestFile writes content to name in dir and returns its path.
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// readTestFile returns the content of path.
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// captureStdout returns what fn prints to standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	fn()
+	w.Close()
+	return <-out
+}
+
+const (
+	// exitTestEnv names the test that runExit runs in a subprocess.
+	exitTestEnv = "KRAKENV_EXIT_TEST"
+	// exitTestFailed is the exit code of a subprocess whose test failed.
+	exitTestFailed = 99
+)
+
+// runExit runs the current test again in a subprocess, where fn is called and
+// the process exits, so commands that end with os.Exit can be tested. Setup
+// before runExit runs in both processes. Returns fn's exit code and output.
+func runExit(t *testing.T, fn func()) (code int, stdout, stderr string) {
+	t.Helper()
+	if os.Getenv(exitTestEnv) == t.Name() {
+		// Assertions in fn fail the subprocess, not fn's exit code
+		defer func() {
+			if t.Failed() {
+				os.Exit(exitTestFailed)
+			}
+		}()
+		fn()
+		os.Exit(0)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+regexp.QuoteMeta(t.Name())+"$")
+	// The subprocess exits before cleaning up its temporary directories
+	cmd.Env = append(os.Environ(), exitTestEnv+"="+t.Name(), "TMPDIR="+t.TempDir())
+	var out, errOut strings.Builder
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		require.NotEqual(t, exitTestFailed, exitErr.ExitCode(), "an assertion failed in the subprocess")
+		return exitErr.ExitCode(), out.String(), errOut.String()
+	}
+	require.NoError(t, err)
+	return 0, out.String(), errOut.String()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFiles []string
+	migrateForce bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:     "migrate <old-name> <new-name>",
+	Aliases: []string{"rename"},
+	Short:   "Rename a variable across the distributable and environment files",
+	Long: `Rename a variable in the distributable and environment files.
+
+Each definition of the old name is renamed in place; its value, annotation
+and position are kept, and references to it in annotations (like:NAME,
+requires and conflicts) are renamed too. By default the distributable and
+the .env.<env> file of every configured environment are updated;
+environment files that do not exist are skipped.
+
+Nothing is written if a file already defines the new name, unless --force
+is given: the existing definition is then replaced by the renamed one.
+
+Exit codes:
+  0 - Variable renamed
+  1 - New name already defined (without --force)
+  2 - Variable not found or file unreadable
+
+Examples:
+  krakenv migrate DB_URL DATABASE_URL
+  krakenv migrate DB_URL DATABASE_URL --files .env.dist,.env.local
+  krakenv migrate DB_URL DATABASE_URL --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMigrate,
+}
+
+func init() {
+	migrateCmd.Flags().StringSliceVar(&migrateFiles, "files", nil,
+		"Files to update (default: distributable and configured environment files)")
+	migrateCmd.Flags().BoolVarP(&migrateForce, "force", "f", false,
+		"Rename even if a file already defines the new name, replacing it")
+
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(_ *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+
+	if !variableNameRegex.MatchString(newName) {
+		return fmt.Errorf("invalid variable name %q: must be uppercase letters, numbers, and underscores, starting with a letter", newName)
+	}
+	if oldName == newName {
+		return fmt.Errorf("old and new names are the same")
+	}
+
+	files := migrateFiles
+	explicit := len(files) > 0
+	if !explicit {
+		var err error
+		files, err = defaultMigrateFiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Read everything and check for conflicts before writing anything
+	type fileLines struct {
+		path     string
+		lines    []string
+		refs     int
+		replaced bool
+	}
+	var toWrite []fileLines
+	defined := false
+	for _, path := range files {
+		lines, err := readFileLines(path)
+		if os.IsNotExist(err) && !explicit && path != distPath {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Cannot read %s: %v\n", path, err)
+			os.Exit(2)
+		}
+
+		hasOld, err := hasLineKey(lines, oldName)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		// Annotation references follow the rename; lines are only written
+		// once every file has been checked
+		refs, err := renameReferences(lines, oldName, newName)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if !hasOld && refs == 0 {
+			if verbose && !quiet {
+				fmt.Printf("  %s does not define %s, skipped\n", path, oldName)
+			}
+			continue
+		}
+		replaced := false
+		if hasOld {
+			hasNew, err := hasLineKey(lines, newName)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			if hasNew && !migrateForce {
+				return fmt.Errorf("%s already defines %s (use --force to replace it)", path, newName)
+			}
+			// With --force the renamed variable replaces the existing one
+			if hasNew {
+				if lines, _, err = removeLineKey(lines, newName); err != nil {
+					return fmt.Errorf("failed to parse %s: %w", path, err)
+				}
+				replaced = true
+			}
+			defined = true
+		}
+
+		toWrite = append(toWrite, fileLines{path: path, lines: lines, refs: refs, replaced: replaced})
+	}
+
+	if !defined {
+		fmt.Fprintf(os.Stderr, "ERROR: Variable %s not found in any of: %s\n", oldName, strings.Join(files, ", "))
+		os.Exit(2)
+	}
+
+	for _, f := range toWrite {
+		count, err := renameLineKey(f.lines, oldName, newName)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", f.path, err)
+		}
+		if err := writeFileLines(f.path, f.lines); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+		if !quiet {
+			fmt.Printf("  %s: %d line(s) renamed\n", f.path, count+f.refs)
+			if f.replaced {
+				fmt.Printf("  %s: replaced the existing %s\n", f.path, newName)
+			}
+		}
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Renamed %s to %s in %d file(s)\n", oldName, newName, len(toWrite))
+	}
+
+	return nil
+}
+
+// defaultMigrateFiles returns the distributable followed by the environment
+// file of each environment configured in it.
+func defaultMigrateFiles() ([]string, error) {
+	if _, err := os.Stat(distPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: Distributable not found: %s\n", distPath)
+		os.Exit(2)
+	}
+
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	files := []string{distPath}
+	if distFile.Config != nil {
+		for _, env := range distFile.Config.Environments {
+			files = append(files, ".env."+env)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMigrate_Heredoc(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist", heredocFile)
+	setGlobal(t, &migrateFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runMigrate(nil, []string{"FOO", "APP_FOO"}))
+	assert.Equal(t, strings.Replace(heredocFile, "FOO=bar", "APP_FOO=bar", 1), readTestFile(t, path))
+}
+
+func TestRunMigrate_HeredocVariable(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist", heredocFile)
+	setGlobal(t, &migrateFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runMigrate(nil, []string{"CERT", "TLS_CERT"}))
+	assert.Equal(t, strings.Replace(heredocFile, "CERT=<<EOF", "TLS_CERT=<<EOF", 1), readTestFile(t, path))
+}
+
+func TestRunMigrate_NameOnlyInHeredoc(t *testing.T) {
+	// FOO= inside the body does not make the file define FOO, so the new
+	// name is free
+	path := writeTestFile(t, t.TempDir(), ".env.dist", "APP_FOO=1\nCERT=<<EOF\nFOO=x\nEOF\n")
+	setGlobal(t, &migrateFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runMigrate(nil, []string{"APP_FOO", "FOO"}))
+	assert.Equal(t, "FOO=1\nCERT=<<EOF\nFOO=x\nEOF\n", readTestFile(t, path))
+}
+
+func TestRunMigrate_LikeReferences(t *testing.T) {
+	dir := t.TempDir()
+	dist := writeTestFile(t, dir, ".env.dist",
+		"DB_HOST=db #prompt:Host?|string;minlen:2\n#prompt:Replica?|like:DB_HOST\nREPLICA_HOST=\n")
+	extra := writeTestFile(t, dir, ".env.extra.dist", "CACHE_HOST= #prompt:Cache?|like:DB_HOST;optional\n")
+	setGlobal(t, &migrateFiles, []string{dist, extra})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runMigrate(nil, []string{"DB_HOST", "DATABASE_HOST"}))
+	assert.Equal(t,
+		"DATABASE_HOST=db #prompt:Host?|string;minlen:2\n#prompt:Replica?|like:DATABASE_HOST\nREPLICA_HOST=\n",
+		readTestFile(t, dist))
+	assert.Equal(t, "CACHE_HOST= #prompt:Cache?|like:DATABASE_HOST;optional\n", readTestFile(t, extra))
+}
+
+func TestRunMigrate_RelationReferences(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"DB_USER= #prompt:User?|string;requires:DB_PASS,DB_HOST\nDB_PASS= #prompt:Pass?|string;conflicts:DB_PASS_FILE\n")
+	setGlobal(t, &migrateFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runMigrate(nil, []string{"DB_PASS", "DB_PASSWORD"}))
+	assert.Equal(t,
+		"DB_USER= #prompt:User?|string;requires:DB_PASSWORD,DB_HOST\nDB_PASSWORD= #prompt:Pass?|string;conflicts:DB_PASS_FILE\n",
+		readTestFile(t, path))
+}
+
+func TestRunMigrate_NewNameDefined(t *testing.T) {
+	content := "DB_URL=postgres://db #prompt:Database?|url\n#prompt:Old database?|string\nDATABASE_URL=<<EOF\nlegacy\nEOF\nPORT=5432\n"
+
+	t.Run("refused without force", func(t *testing.T) {
+		path := writeTestFile(t, t.TempDir(), ".env.dist", content)
+		setGlobal(t, &migrateFiles, []string{path})
+		setGlobal(t, &migrateForce, false)
+		setGlobal(t, &quiet, true)
+
+		err := runMigrate(nil, []string{"DB_URL", "DATABASE_URL"})
+		assert.ErrorContains(t, err, "already defines DATABASE_URL")
+		assert.Equal(t, content, readTestFile(t, path))
+	})
+
+	t.Run("replaced with force", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeTestFile(t, dir, ".env.dist", content)
+		local := writeTestFile(t, dir, ".env.local", "DATABASE_URL=stale\nDB_URL=postgres://local\n")
+		setGlobal(t, &migrateFiles, []string{path, local})
+		setGlobal(t, &migrateForce, true)
+		setGlobal(t, &quiet, true)
+
+		require.NoError(t, runMigrate(nil, []string{"DB_URL", "DATABASE_URL"}))
+		assert.Equal(t, "DATABASE_URL=postgres://db #prompt:Database?|url\nPORT=5432\n", readTestFile(t, path))
+		assert.Equal(t, "DATABASE_URL=postgres://local\n", readTestFile(t, local))
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/generator"
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <target>",
+	Short: "List distributable variables the environment file does not define yet",
+	Long: `List the variables of the distributable that are missing from an
+environment file, typically ones added to the distributable since the file
+was generated.
+
+Each variable is printed as a NAME=default line, preceded by a comment with
+its prompt and type, ready to paste into the environment file. Defaults
+follow default@<env> annotations for the target's environment.
+
+Exit codes:
+  0 - Report printed, whether or not there are new variables
+  2 - File not found or unreadable
+
+Examples:
+  krakenv new .env.local
+  krakenv new .env.local >> .env.local`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNew,
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+}
+
+func runNew(_ *cobra.Command, args []string) error {
+	targetPath := args[0]
+
+	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", targetPath)
+		os.Exit(2)
+	}
+
+	distFile, err := loadDistributable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distLabel(), err)
+		os.Exit(2)
+	}
+
+	targetFile, err := parser.ParseEnvFile(targetPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse target %s: %v\n", targetPath, err)
+		os.Exit(2)
+	}
+
+	missing := inspector.Inspect(distFile, targetFile).MissingInEnv
+	if len(missing) == 0 {
+		if !quiet {
+			fmt.Printf("No new variables: %s defines everything in %s\n", targetPath, distLabel())
+		}
+		return nil
+	}
+
+	if !quiet {
+		fmt.Printf("# %d variable(s) in %s missing from %s\n", len(missing), distLabel(), targetPath)
+	}
+	for _, v := range missing {
+		fmt.Println(formatNewVariable(v, targetPath))
+	}
+
+	return nil
+}
+
+// formatNewVariable renders a missing variable as a comment with its prompt
+// and type, followed by a NAME=default line for the target.
+func formatNewVariable(v parser.Variable, targetPath string) string {
+	line := parser.FormatVariable(parser.Variable{
+		Name:  v.Name,
+		Value: generator.DistDefault(v, targetPath),
+	}, false)
+
+	ann := v.Annotation
+	if ann == nil {
+		return line
+	}
+
+	details := ann.Type.String()
+	if flags := annotationFlags(ann); flags != "" {
+		details += "; " + flags
+	}
+	comment := fmt.Sprintf("# %s [%s]", ann.PromptText, details)
+	if ann.Description != "" {
+		comment += " - " + ann.Description
+	}
+	return comment + "\n" + line
+}
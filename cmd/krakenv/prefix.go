@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var (
+	prefixFiles  []string
+	prefixDryRun bool
+)
+
+var prefixCmd = &cobra.Command{
+	Use:   "prefix",
+	Short: "Add or strip a prefix on every variable name",
+	Long: `Rename every variable of the distributable by adding or stripping a
+prefix, in the distributable and environment files.
+
+Renames work like migrate: values, annotations and positions are kept,
+like:NAME, requires and conflicts references follow the renamed variables,
+and by default the distributable and the .env.<env> file of every
+configured environment are updated. Variables that already have the prefix (add) or do not have it
+(strip) are left alone.
+
+Nothing is written if a resulting name is not a valid variable name or is
+already defined in one of the files.
+
+Exit codes:
+  0 - Variables renamed, or nothing to rename
+  1 - Invalid or conflicting resulting name
+  2 - Distributable not found or file unreadable
+
+Examples:
+  krakenv prefix add SVC_
+  krakenv prefix strip SVC_ --files .env.dist,.env.local
+  krakenv prefix add APP_ --dry-run`,
+}
+
+var prefixAddCmd = &cobra.Command{
+	Use:   "add <prefix>",
+	Short: "Add a prefix to every variable name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runPrefix(args[0], false)
+	},
+}
+
+var prefixStripCmd = &cobra.Command{
+	Use:   "strip <prefix>",
+	Short: "Strip a prefix from every variable name that has it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runPrefix(args[0], true)
+	},
+}
+
+func init() {
+	prefixCmd.PersistentFlags().StringSliceVar(&prefixFiles, "files", nil,
+		"Files to update (default: distributable and configured environment files)")
+	prefixCmd.PersistentFlags().BoolVar(&prefixDryRun, "dry-run", false,
+		"Print the renames without changing any file")
+
+	prefixCmd.AddCommand(prefixAddCmd, prefixStripCmd)
+	rootCmd.AddCommand(prefixCmd)
+}
+
+// prefixRename is one variable renamed by the prefix command.
+type prefixRename struct {
+	oldName string
+	newName string
+}
+
+func runPrefix(prefix string, strip bool) error {
+	if prefix == "" {
+		return fmt.Errorf("prefix must not be empty")
+	}
+
+	if _, err := os.Stat(distPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: Distributable not found: %s\n", distPath)
+		os.Exit(2)
+	}
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	renames, err := prefixRenames(distFile, prefix, strip)
+	if err != nil {
+		return err
+	}
+	if len(renames) == 0 {
+		if !quiet {
+			fmt.Println("Nothing to rename")
+		}
+		return nil
+	}
+
+	files := prefixFiles
+	explicit := len(files) > 0
+	if !explicit {
+		files, err = defaultMigrateFiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Read everything and check for conflicts before writing anything
+	type fileLines struct {
+		path  string
+		lines []string
+	}
+	var toWrite []fileLines
+	for _, path := range files {
+		lines, err := readFileLines(path)
+		if os.IsNotExist(err) && !explicit && path != distPath {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Cannot read %s: %v\n", path, err)
+			os.Exit(2)
+		}
+
+		for _, r := range renames {
+			exists, err := hasLineKey(lines, r.newName)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			if exists {
+				return fmt.Errorf("%s already defines %s, the new name of %s", path, r.newName, r.oldName)
+			}
+		}
+		toWrite = append(toWrite, fileLines{path: path, lines: lines})
+	}
+
+	if prefixDryRun {
+		for _, r := range renames {
+			fmt.Printf("%s → %s\n", r.oldName, r.newName)
+		}
+		return nil
+	}
+
+	for _, f := range toWrite {
+		count := 0
+		for _, r := range renames {
+			n, err := renameLineKey(f.lines, r.oldName, r.newName)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", f.path, err)
+			}
+			refs, err := renameReferences(f.lines, r.oldName, r.newName)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", f.path, err)
+			}
+			count += n + refs
+		}
+		if count == 0 {
+			continue
+		}
+		if err := writeFileLines(f.path, f.lines); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+		if !quiet {
+			fmt.Printf("  %s: %d line(s) renamed\n", f.path, count)
+		}
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Renamed %d variable(s)\n", len(renames))
+	}
+
+	return nil
+}
+
+// prefixRenames lists the renames that add or strip prefix on the
+// distributable's variables, failing if any resulting name is invalid.
+func prefixRenames(distFile *parser.EnvFile, prefix string, strip bool) ([]prefixRename, error) {
+	var renames []prefixRename
+	for _, v := range distFile.Variables {
+		hasPrefix := strings.HasPrefix(v.Name, prefix)
+		var newName string
+		switch {
+		case strip && hasPrefix:
+			newName = strings.TrimPrefix(v.Name, prefix)
+		case !strip && !hasPrefix:
+			newName = prefix + v.Name
+		default:
+			continue
+		}
+
+		if !variableNameRegex.MatchString(newName) {
+			return nil, fmt.Errorf("%s would become %q, which is not a valid variable name", v.Name, newName)
+		}
+		renames = append(renames, prefixRename{oldName: v.Name, newName: newName})
+	}
+	return renames, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPrefix_LikeReferences(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"HOST=db #prompt:Host?|string\nREPLICA= #prompt:Replica?|like:HOST\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &prefixFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runPrefix("APP_", false))
+	assert.Equal(t,
+		"APP_HOST=db #prompt:Host?|string\nAPP_REPLICA= #prompt:Replica?|like:APP_HOST\n",
+		readTestFile(t, path))
+}
+
+func TestRunPrefix_RelationReferences(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"APP_USER= #prompt:User?|string;requires:APP_PASS\nAPP_PASS= #prompt:Pass?|string;optional\n"+
+			"APP_TOKEN= #prompt:Token?|string;optional;conflicts:APP_USER, APP_PASS\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &prefixFiles, []string{path})
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runPrefix("APP_", true))
+	assert.Equal(t,
+		"USER= #prompt:User?|string;requires:PASS\nPASS= #prompt:Pass?|string;optional\n"+
+			"TOKEN= #prompt:Token?|string;optional;conflicts:USER, PASS\n",
+		readTestFile(t, path))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+var (
+	promoteVars        string
+	promoteAllowSecret bool
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <target>",
+	Short: "Copy values from an environment file into the distributable as defaults",
+	Long: `Promote the current values of selected variables in an environment file
+to be their defaults in the distributable.
+
+Only the value of each promoted line is changed; annotations, comments and
+the rest of the distributable are kept exactly as written. Secret variables
+are refused unless --allow-secret is given.
+
+Examples:
+  krakenv promote .env.local --vars PORT,LOG_LEVEL
+  krakenv promote .env.local --vars API_KEY --allow-secret`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromote,
+}
+
+func init() {
+	promoteCmd.Flags().StringVar(&promoteVars, "vars", "",
+		"Comma-separated variables to promote (required)")
+	promoteCmd.Flags().BoolVar(&promoteAllowSecret, "allow-secret", false,
+		"Allow promoting variables marked as secret")
+	promoteCmd.MarkFlagRequired("vars")
+
+	rootCmd.AddCommand(promoteCmd)
+}
+
+// promotion records a single default change in the distributable.
+type promotion struct {
+	name     string
+	oldValue string
+	newValue string
+}
+
+func runPromote(_ *cobra.Command, args []string) error {
+	targetPath := args[0]
+
+	// Check target exists
+	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", targetPath)
+		os.Exit(2)
+	}
+
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable %s: %w", distPath, err)
+	}
+
+	targetFile, err := parser.ParseEnvFile(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse target %s: %w", targetPath, err)
+	}
+
+	// Resolve every requested variable before touching the file
+	var promotions []promotion
+	for _, name := range strings.Split(promoteVars, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		distVar := distFile.GetVariable(name)
+		if distVar == nil {
+			return fmt.Errorf("variable %s is not defined in %s", name, distPath)
+		}
+
+		targetVar := targetFile.GetVariable(name)
+		if targetVar == nil {
+			return fmt.Errorf("variable %s is not set in %s", name, targetPath)
+		}
+
+		if distVar.Annotation != nil {
+			if distVar.Annotation.IsSecret && !promoteAllowSecret {
+				return fmt.Errorf("refusing to promote secret variable %s (use --allow-secret)", name)
+			}
+			if err := validator.ValidateValue(targetVar.Value, distVar.Annotation); err != nil {
+				return fmt.Errorf("cannot promote %s: %w", name, err)
+			}
+		}
+
+		promotions = append(promotions, promotion{
+			name:     name,
+			oldValue: distVar.Value,
+			newValue: targetVar.Value,
+		})
+	}
+
+	if len(promotions) == 0 {
+		return fmt.Errorf("no variables to promote")
+	}
+
+	// Rewrite only the promoted lines
+	lines, err := readFileLines(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to read distributable: %w", err)
+	}
+	for _, p := range promotions {
+		if lines, _, err = setLineValue(lines, p.name, p.newValue); err != nil {
+			return fmt.Errorf("failed to parse distributable: %w", err)
+		}
+	}
+	if err := writeFileLines(distPath, lines); err != nil {
+		return fmt.Errorf("failed to write distributable: %w", err)
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Promoted %d variable(s) to %s\n", len(promotions), distPath)
+		for _, p := range promotions {
+			fmt.Printf("  %-20s %q → %q\n", p.name, p.oldValue, p.newValue)
+		}
+	}
+
+	return nil
+}
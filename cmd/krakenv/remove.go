@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+var removeDryRun bool
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a variable from the distributable",
+	Long: `Remove a variable from the distributable file.
+
+Only the lines defining the variable are deleted; comments, config lines
+and the formatting of the rest of the file are kept as written. Annotations
+that are like:<name> get the type and constraints they inherit written out
+in full.
+
+Examples:
+  krakenv remove LEGACY_API_URL
+  krakenv remove LEGACY_API_URL --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemove,
+}
+
+func init() {
+	removeCmd.Flags().BoolVar(&removeDryRun, "dry-run", false,
+		"Show what would be removed without modifying the file")
+
+	rootCmd.AddCommand(removeCmd)
+}
+
+func runRemove(_ *cobra.Command, args []string) error {
+	varName := args[0]
+
+	// Check distributable exists
+	if _, err := os.Stat(distPath); os.IsNotExist(err) {
+		return fmt.Errorf("distributable not found: %s\nRun 'krakenv init' to create one", distPath)
+	}
+
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	if !distFile.HasVariable(varName) {
+		fmt.Fprintf(os.Stderr, "ERROR: Variable %s not found in %s\n", varName, distPath)
+		os.Exit(2)
+	}
+
+	lines, err := readFileLines(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to read distributable: %w", err)
+	}
+
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	// Annotations extending the variable take its type and constraints in full
+	flattened := flattenLikes(lines, spans, distFile, varName)
+
+	// Drop every definition of the variable, including duplicates and heredoc
+	// bodies, along with an annotation line directly above it
+	kept, removed, err := removeLineKey(lines, varName)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	if removeDryRun {
+		if !quiet {
+			fmt.Printf("Would remove %s from %s:\n", varName, distPath)
+			for _, i := range removed {
+				fmt.Printf("  Line %d: %s\n", i+1, strings.TrimSpace(lines[i]))
+			}
+			for _, name := range flattened {
+				fmt.Printf("Would expand like:%s in the annotation of %s\n", varName, name)
+			}
+		}
+		return nil
+	}
+
+	if err := writeFileLines(distPath, kept); err != nil {
+		return fmt.Errorf("failed to write distributable: %w", err)
+	}
+
+	if !quiet {
+		fmt.Printf("✓ Removed %s from %s\n", varName, distPath)
+		for _, name := range flattened {
+			fmt.Printf("  Expanded like:%s in the annotation of %s\n", varName, name)
+		}
+	}
+
+	return nil
+}
+
+// flattenLikes rewrites the annotations that are like:name with the type and
+// constraints they inherit written in full, so they survive name's removal.
+// Returns the names of the variables whose annotation was rewritten.
+func flattenLikes(lines []string, spans []lineSpan, distFile *parser.EnvFile, name string) []string {
+	annotations := make(map[string]string)
+	var flattened []string
+	for _, v := range distFile.Variables {
+		if v.Name != name && v.Annotation != nil && v.Annotation.Like == name {
+			annotations[v.Name] = parser.FormatAnnotation(v.Annotation.Flatten())
+			flattened = append(flattened, v.Name)
+		}
+	}
+
+	for i, s := range spans {
+		annotation, ok := annotations[s.Name]
+		if s.Kind != parser.LineVariable || !ok {
+			continue
+		}
+		// The annotation is inline, or on the line above
+		at := s.start
+		if _, _, inline, err := parser.TokenizeLine(lines[s.start]); err == nil && inline == "" &&
+			i > 0 && spans[i-1].Kind == parser.LineAnnotation {
+			at = spans[i-1].start
+		}
+		lines[at] = parser.ReplaceLineAnnotation(lines[at], annotation)
+	}
+	return flattened
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestRunRemove_Heredoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		varName string
+		want    string
+	}{
+		{
+			name:    "heredoc variable",
+			varName: "CERT",
+			want:    "# Certificates\nFOO=bar\n",
+		},
+		{
+			name:    "variable named in another heredoc",
+			varName: "FOO",
+			want:    "# Certificates\nCERT=<<EOF #prompt:Certificate?|string\n-----BEGIN-----\nFOO=inside the body\n# Database\n-----END-----\nEOF\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, t.TempDir(), ".env.dist", heredocFile)
+			setGlobal(t, &distPath, path)
+			setGlobal(t, &quiet, true)
+
+			require.NoError(t, runRemove(nil, []string{tt.varName}))
+			assert.Equal(t, tt.want, readTestFile(t, path))
+		})
+	}
+}
+
+func TestRunRemove_AnnotationAboveHeredoc(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"A=1\n#prompt:Key?|string\nKEY=<<EOF\nx\nEOF\nB=2\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runRemove(nil, []string{"KEY"}))
+	assert.Equal(t, "A=1\nB=2\n", readTestFile(t, path))
+}
+
+func TestRunRemove_FlattensLikeReferences(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"DB_HOST=db #prompt:Host?|string;minlen:2\n"+
+			"#prompt:Replica?|like:DB_HOST;optional\nREPLICA_HOST=\n"+
+			"CACHE_HOST= #prompt:Cache?|like:DB_HOST\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runRemove(nil, []string{"DB_HOST"}))
+	assert.Equal(t,
+		"#prompt:Replica?|string;minlen:2;optional\nREPLICA_HOST=\n"+
+			"CACHE_HOST= #prompt:Cache?|string;minlen:2\n",
+		readTestFile(t, path))
+
+	distFile, err := parser.ParseEnvFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2", distFile.GetVariable("CACHE_HOST").Annotation.GetConstraint("minlen"))
+}
+
+func TestRunRemove_KeepsFormatting(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.dist",
+		"#config:strict=true\n# Server\nPORT=8080   # listen port\nLEGACY_URL=x #prompt:Legacy?|url\n\n# Database\nDB_HOST=db\nLEGACY_URL=y\n")
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &removeDryRun, false)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runRemove(nil, []string{"LEGACY_URL"}))
+	assert.Equal(t, "#config:strict=true\n# Server\nPORT=8080   # listen port\n\n# Database\nDB_HOST=db\n", readTestFile(t, path))
+}
+
+func TestRunRemove_DryRun(t *testing.T) {
+	content := "PORT=8080\n#prompt:Legacy?|url\nLEGACY_URL=x\nMIRROR_URL= #prompt:Mirror?|like:LEGACY_URL\n"
+	path := writeTestFile(t, t.TempDir(), ".env.dist", content)
+	setGlobal(t, &distPath, path)
+	setGlobal(t, &removeDryRun, true)
+
+	t.Run("lists the lines", func(t *testing.T) {
+		setGlobal(t, &quiet, false)
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runRemove(nil, []string{"LEGACY_URL"}))
+		})
+		assert.Equal(t, "Would remove LEGACY_URL from "+path+":\n"+
+			"  Line 2: #prompt:Legacy?|url\n"+
+			"  Line 3: LEGACY_URL=x\n"+
+			"Would expand like:LEGACY_URL in the annotation of MIRROR_URL\n", out)
+		assert.Equal(t, content, readTestFile(t, path))
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		setGlobal(t, &quiet, true)
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runRemove(nil, []string{"LEGACY_URL"}))
+		})
+		assert.Empty(t, out)
+		assert.Equal(t, content, readTestFile(t, path))
+	})
+}
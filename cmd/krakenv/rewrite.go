@@ -0,0 +1,220 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/log"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// readFileLines reads a file and splits it into lines, keeping a trailing empty
+// element when the file ends with a newline so writeFileLines round-trips it.
+func readFileLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// writeFileLines joins lines with newlines and writes them to path.
+func writeFileLines(path string, lines []string) error {
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// writeReportFile writes a report to path, creating parent directories as needed.
+func writeReportFile(path, report string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		return err
+	}
+	log.Info("report written", "path", path, "bytes", len(report))
+	return nil
+}
+
+// lineSpan is one entry of parser.EnvFile.Lines located in a file's physical
+// lines: a single line, or a heredoc variable with its body and terminator.
+type lineSpan struct {
+	parser.Line
+	start, end int // Physical lines [start, end)
+}
+
+// lineSpans parses lines as read by readFileLines and locates each parsed line,
+// so edits never mistake heredoc body lines for variables or comments.
+func lineSpans(lines []string) ([]lineSpan, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	envFile, err := parser.ParseEnvFileContent(strings.Join(lines, "\n"), "",
+		parser.ParseOptions{KeepWhitespace: true, SkipLikes: true})
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make([]lineSpan, 0, len(envFile.Lines))
+	start := 0
+	for _, line := range envFile.Lines {
+		end := start + strings.Count(line.Text, "\n") + 1
+		spans = append(spans, lineSpan{Line: line, start: start, end: end})
+		start = end
+	}
+	return spans, nil
+}
+
+// setLineValue replaces the value of every variable defining name, or appends a
+// new NAME=value line if none does. Reports whether an existing variable was updated.
+func setLineValue(lines []string, name, value string) ([]string, bool, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Replace from the end so earlier spans keep their positions
+	updated := false
+	for i := len(spans) - 1; i >= 0; i-- {
+		s := spans[i]
+		if s.Kind != parser.LineVariable || s.Name != name {
+			continue
+		}
+		text := parser.ReplaceLineValue(strings.Join(lines[s.start:s.end], "\n"), value)
+		lines = append(lines[:s.start], append(strings.Split(text, "\n"), lines[s.end:]...)...)
+		updated = true
+	}
+	if updated {
+		return lines, true, nil
+	}
+
+	// Append before the trailing empty element left by a final newline
+	newLines := strings.Split(parser.FormatVariable(parser.Variable{Name: name, Value: value}, false), "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	return append(append(lines, newLines...), ""), false, nil
+}
+
+// renameLineKey renames every variable defining oldName to newName, keeping the
+// value, annotation and surrounding whitespace. Returns how many variables changed.
+func renameLineKey(lines []string, oldName, newName string) (int, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	renamed := 0
+	for _, s := range spans {
+		if s.Kind == parser.LineVariable && s.Name == oldName {
+			line := lines[s.start]
+			idx := strings.Index(line, oldName)
+			lines[s.start] = line[:idx] + newName + line[idx+len(oldName):]
+			renamed++
+		}
+	}
+	return renamed, nil
+}
+
+// removeLineKey drops every variable defining name, including duplicates and
+// heredoc bodies, along with an annotation line directly above it. Returns the
+// remaining lines and the indexes of the removed ones.
+func removeLineKey(lines []string, name string) ([]string, []int, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := make([]string, 0, len(lines))
+	var removed []int
+	var prev parser.LineKind = -1
+	for _, s := range spans {
+		if s.Kind != parser.LineVariable || s.Name != name {
+			kept = append(kept, lines[s.start:s.end]...)
+			prev = s.Kind
+			continue
+		}
+		if prev == parser.LineAnnotation {
+			removed = append(removed, s.start-1)
+			kept = kept[:len(kept)-1]
+		}
+		for i := s.start; i < s.end; i++ {
+			removed = append(removed, i)
+		}
+		prev = s.Kind
+	}
+	return kept, removed, nil
+}
+
+// renameReferences renames the references to oldName in every annotation
+// (like:NAME, requires and conflicts) to newName. Returns how many lines changed.
+func renameReferences(lines []string, oldName, newName string) (int, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, s := range spans {
+		if s.Kind != parser.LineVariable && s.Kind != parser.LineAnnotation {
+			continue
+		}
+		if line := parser.RenameReferences(lines[s.start], oldName, newName); line != lines[s.start] {
+			lines[s.start] = line
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// sectionInsertIndex returns where a variable belongs in the section headed
+// by a "# <section>" comment (matched case-insensitively, ignoring = and -
+// decorations), or -1 if there is no such header. The section runs until the
+// next comment that follows a blank line; the index is just after its last
+// variable, or just after the header if it has none.
+func sectionInsertIndex(lines []string, section string) (int, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	header := -1
+	for i, s := range spans {
+		if s.Kind == parser.LineComment &&
+			strings.EqualFold(strings.Trim(parser.ExtractCommentText(s.Text), "=- "), strings.TrimSpace(section)) {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return -1, nil
+	}
+
+	insert := spans[header].end
+	for i := header + 1; i < len(spans); i++ {
+		s := spans[i]
+		if s.Kind != parser.LineVariable && parser.IsComment(s.Text) && spans[i-1].Kind == parser.LineBlank {
+			break
+		}
+		if s.Kind == parser.LineVariable {
+			insert = s.end
+		}
+	}
+	return insert, nil
+}
+
+// hasLineKey reports whether any variable is named name.
+func hasLineKey(lines []string, name string) (bool, error) {
+	spans, err := lineSpans(lines)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range spans {
+		if s.Kind == parser.LineVariable && s.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
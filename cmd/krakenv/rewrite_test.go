@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// heredocFile defines FOO= inside CERT's heredoc body, which no edit may touch.
+const heredocFile = `# Certificates
+CERT=<<EOF #prompt:Certificate?|string
+-----BEGIN-----
+FOO=inside the body
+# Database
+-----END-----
+EOF
+FOO=bar
+`
+
+func TestSetLineValue_Heredoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		varName string
+		value   string
+		want    string
+		updated bool
+	}{
+		{
+			name:    "variable after heredoc",
+			varName: "FOO",
+			value:   "baz",
+			want:    strings.Replace(heredocFile, "FOO=bar", "FOO=baz", 1),
+			updated: true,
+		},
+		{
+			name:    "heredoc to single line",
+			varName: "CERT",
+			value:   "none",
+			want:    "# Certificates\nCERT=none #prompt:Certificate?|string\nFOO=bar\n",
+			updated: true,
+		},
+		{
+			name:    "heredoc body",
+			varName: "CERT",
+			value:   "a\nb",
+			want:    "# Certificates\nCERT=<<EOF #prompt:Certificate?|string\na\nb\nEOF\nFOO=bar\n",
+			updated: true,
+		},
+		{
+			name:    "appended heredoc",
+			varName: "KEY",
+			value:   "x\ny",
+			want:    heredocFile + "KEY=<<EOF\nx\ny\nEOF\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, updated, err := setLineValue(strings.Split(heredocFile, "\n"), tt.varName, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.updated, updated)
+			assert.Equal(t, tt.want, strings.Join(lines, "\n"))
+		})
+	}
+}
+
+func TestRenameLineKey_Heredoc(t *testing.T) {
+	lines := strings.Split(heredocFile, "\n")
+
+	count, err := renameLineKey(lines, "FOO", "APP_FOO")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, strings.Replace(heredocFile, "FOO=bar", "APP_FOO=bar", 1), strings.Join(lines, "\n"))
+}
+
+func TestHasLineKey_Heredoc(t *testing.T) {
+	lines := strings.Split("CERT=<<EOF\nKEY=x\nEOF\n", "\n")
+
+	has, err := hasLineKey(lines, "KEY")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	has, err = hasLineKey(lines, "CERT")
+	require.NoError(t, err)
+	assert.True(t, has)
+}
+
+func TestSectionInsertIndex_Heredoc(t *testing.T) {
+	lines := strings.Split(heredocFile, "\n")
+
+	// "# Database" inside the body is not a section header
+	idx, err := sectionInsertIndex(lines, "Database")
+	require.NoError(t, err)
+	assert.Equal(t, -1, idx)
+
+	// The section's last variable is FOO, after the heredoc terminator
+	idx, err = sectionInsertIndex(lines, "Certificates")
+	require.NoError(t, err)
+	assert.Equal(t, 8, idx)
+}
+
+func TestRewriteHelpers_UnterminatedHeredoc(t *testing.T) {
+	lines := strings.Split("CERT=<<EOF\nbody\n", "\n")
+
+	_, _, err := setLineValue(lines, "CERT", "x")
+	assert.Error(t, err)
+	_, err = hasLineKey(lines, "CERT")
+	assert.Error(t, err)
+}
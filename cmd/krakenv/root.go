@@ -2,15 +2,37 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
 	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/config"
+	"github.com/theburrowhub/krakenv/internal/log"
+	"github.com/theburrowhub/krakenv/internal/parser"
 )
 
+// distPathEnv is the environment variable that sets the distributable path
+// when --dist is not given.
+const distPathEnv = "KRAKENV_DIST"
+
 var (
 	// Global flags.
-	distPath       string
+	distFlags      []string
 	nonInteractive bool
 	quiet          bool
 	verbose        bool
+
+	// distPaths are the distributables to read, merged in order. distPath is
+	// the last of them, the one commands that edit the distributable write to.
+	distPaths []string
+	distPath  string
+
+	// projectConfig holds defaults from the nearest .krakenvrc (nil if none).
+	projectConfig *config.ProjectConfig
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -32,8 +54,9 @@ Features:
 
 Example annotation:
   DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535`,
-	SilenceUsage:  true,
-	SilenceErrors: true,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	PersistentPreRunE: resolveGlobalFlags,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -44,12 +67,148 @@ func Execute() error {
 
 func init() {
 	// Global flags available on all commands.
-	rootCmd.PersistentFlags().StringVarP(&distPath, "dist", "d", ".env.dist",
-		"Path to distributable file")
+	rootCmd.PersistentFlags().StringArrayVarP(&distFlags, "dist", "d", nil,
+		"Path to distributable file (default distPath from .krakenvrc, then $"+distPathEnv+", then .env.dist); repeat to merge several, later files override earlier ones")
 	rootCmd.PersistentFlags().BoolVarP(&nonInteractive, "non-interactive", "n", false,
 		"Disable TUI; fail on unresolved variables")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false,
 		"Suppress non-error output")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
-		"Enable detailed output")
+		"Enable detailed output and debug logs on stderr")
+}
+
+// resolveGlobalFlags fills in flags that were not given on the command line.
+// The distributable path must be known before the distributable (and any
+// config inside it) can be read, so it comes from --dist, then the nearest
+// .krakenvrc, then $KRAKENV_DIST, then the .env.dist default.
+func resolveGlobalFlags(cmd *cobra.Command, _ []string) error {
+	log.Setup(os.Stderr, verbose, quiet)
+
+	// Commands that never read the distributable do not depend on .krakenvrc
+	if !usesDistributable(cmd) {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	projectConfig, err = config.LoadProjectConfig(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", config.ProjectConfigFile, err)
+	}
+	if projectConfig != nil {
+		log.Debug("project config loaded", "path", projectConfig.Path)
+	}
+
+	distPaths = distFlags
+	if len(distPaths) == 0 {
+		path := ".env.dist"
+		if projectConfig != nil && projectConfig.DistPath != "" {
+			path = projectConfig.DistPath
+			if rel, err := filepath.Rel(cwd, path); err == nil {
+				path = rel
+			}
+		} else if env := os.Getenv(distPathEnv); env != "" {
+			path = env
+		}
+		distPaths = []string{path}
+	}
+	distPath = distPaths[len(distPaths)-1]
+	log.Debug("distributable resolved", "paths", distLabel())
+
+	if projectConfig != nil {
+		applyProjectDefaults(cmd, projectConfig)
+	}
+	return nil
+}
+
+// usesDistributable reports whether cmd reads the distributable or the
+// project defaults, which version, completion and help do not.
+func usesDistributable(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "version", "completion", "help":
+		return false
+	}
+	return true
+}
+
+// loadDistributable parses the distributables given with --dist and merges
+// them into one, later files overriding earlier ones. like:NAME references
+// are resolved after merging, so they may point into another file.
+func loadDistributable() (*parser.EnvFile, error) {
+	if len(distPaths) == 1 {
+		return parser.ParseEnvFile(distPaths[0])
+	}
+
+	files := make([]*parser.EnvFile, 0, len(distPaths))
+	for _, path := range distPaths {
+		f, err := parser.ParseEnvFile(path, parser.ParseOptions{SkipLikes: true})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		files = append(files, f)
+	}
+	log.Debug("distributables merged", "files", len(files))
+
+	merged := parser.MergeEnvFiles(files...)
+	if err := parser.ResolveLikes(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// loadDistFile parses a single file. When it is one of several distributables
+// given with --dist, its like:NAME references are resolved with the others as
+// context, so they may point into another file as they do once merged.
+func loadDistFile(path string) (*parser.EnvFile, error) {
+	if len(distPaths) == 1 || !slices.Contains(distPaths, path) {
+		return parser.ParseEnvFile(path)
+	}
+
+	envFile, err := parser.ParseEnvFile(path, parser.ParseOptions{SkipLikes: true})
+	if err != nil {
+		return nil, err
+	}
+	var context []*parser.EnvFile
+	for _, p := range distPaths {
+		if p == path {
+			continue
+		}
+		f, err := parser.ParseEnvFile(p, parser.ParseOptions{SkipLikes: true})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		context = append(context, f)
+	}
+	if err := parser.ResolveLikes(envFile, context...); err != nil {
+		return nil, err
+	}
+	return envFile, nil
+}
+
+// distLabel names the distributables for messages.
+func distLabel() string {
+	return strings.Join(distPaths, ", ")
+}
+
+// applyProjectDefaults uses .krakenvrc values as defaults for the flags of
+// cmd that were not given on the command line.
+func applyProjectDefaults(cmd *cobra.Command, rc *config.ProjectConfig) {
+	setDefault := func(name, value string) {
+		if f := cmd.Flags().Lookup(name); f != nil && !f.Changed && value != "" {
+			f.Value.Set(value)
+		}
+	}
+
+	switch cmd {
+	case generateCmd:
+		setDefault("format", rc.Format)
+	case validateCmd:
+		if rc.Strict {
+			setDefault("strict", "true")
+		}
+	case initCmd:
+		setDefault("environments", strings.Join(rc.Environments, ","))
+	}
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/config"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestLoadDistFile_AcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := writeTestFile(t, dir, "base.env.dist", "DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535\n")
+	service := writeTestFile(t, dir, "service.env.dist", "API_PORT= #prompt:API port?|like:DB_PORT;max:9000\n")
+	setGlobal(t, &distPaths, []string{base, service})
+	setGlobal(t, &distPath, service)
+
+	envFile, err := loadDistFile(service)
+	require.NoError(t, err)
+	api := envFile.GetVariable("API_PORT").Annotation
+	assert.Equal(t, parser.TypeInt, api.Type)
+	assert.Equal(t, "1", api.GetConstraint("min"))
+	assert.Equal(t, "9000", api.GetConstraint("max"))
+
+	// Commands that edit the distributable load it the same way
+	setGlobal(t, &quiet, true)
+	require.NoError(t, runLint(nil, nil))
+	require.NoError(t, runRemove(nil, []string{"API_PORT"}))
+}
+
+func TestLoadDistFile_OutsideDist(t *testing.T) {
+	dir := t.TempDir()
+	base := writeTestFile(t, dir, "base.env.dist", "DB_PORT=5432 #prompt:Database port?|int\n")
+	other := writeTestFile(t, dir, ".env.template", "API_PORT= #prompt:API port?|like:DB_PORT\n")
+	setGlobal(t, &distPaths, []string{base})
+
+	// A file that is not a --dist file is parsed on its own
+	_, err := loadDistFile(other)
+	assert.ErrorIs(t, err, parser.ErrUndefinedLike)
+}
+
+func TestResolveGlobalFlags_DistPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []string
+		rc    string
+		env   string
+		want  string
+	}{
+		{"default", nil, "", "", ".env.dist"},
+		{"environment", nil, "", "env.dist", "env.dist"},
+		{"project config over environment", nil, "distPath = rc.dist\n", "env.dist", "rc.dist"},
+		{"flag over both", []string{"flag.dist"}, "distPath = rc.dist\n", "env.dist", "flag.dist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.rc != "" {
+				writeTestFile(t, dir, config.ProjectConfigFile, tt.rc)
+			}
+			t.Chdir(dir)
+			t.Setenv(distPathEnv, tt.env)
+			setGlobal(t, &distFlags, tt.flags)
+			setGlobal(t, &distPaths, nil)
+			setGlobal(t, &distPath, "")
+			setGlobal(t, &projectConfig, nil)
+
+			require.NoError(t, resolveGlobalFlags(listCmd, nil))
+			assert.Equal(t, tt.want, distPath)
+		})
+	}
+}
+
+func TestResolveGlobalFlags_SkipsProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, config.ProjectConfigFile, "not a key value line\n")
+	t.Chdir(dir)
+	setGlobal(t, &projectConfig, nil)
+
+	require.NoError(t, resolveGlobalFlags(versionCmd, nil))
+	require.NoError(t, resolveGlobalFlags(completionCmd, nil))
+	assert.Error(t, resolveGlobalFlags(listCmd, nil))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/schema"
+)
+
+var schemaFormat string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema describing the distributable's variables",
+	Long: `Print a JSON Schema (draft 2020-12) describing the variables of the
+distributable, so other tools can validate environment files.
+
+Each variable becomes a property: types map to JSON Schema types, min/max to
+minimum/maximum, pattern to pattern and enum options to enum. Annotated
+variables that are not optional are required. With --format openapi the
+schema is wrapped in an OpenAPI 3.1 document as components.schemas.Environment.
+
+Examples:
+  krakenv schema > env.schema.json
+  krakenv schema --format openapi
+  krakenv schema --dist config/.env.template`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringVar(&schemaFormat, "format", schema.FormatJSONSchema,
+		"Output format: json-schema or openapi")
+
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(_ *cobra.Command, _ []string) error {
+	if !schema.IsValidFormat(schemaFormat) {
+		return fmt.Errorf("invalid format %q (use: json-schema, openapi)", schemaFormat)
+	}
+
+	if _, err := os.Stat(distPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "ERROR: Distributable not found: %s\n", distPath)
+		os.Exit(2)
+	}
+
+	distFile, err := loadDistFile(distPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse distributable: %w", err)
+	}
+
+	doc := schema.Build(distFile, filepath.Base(distPath), schemaFormat)
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format JSON: %w", err)
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+var (
+	setTarget string
+	setForce  bool
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <name> <value>",
+	Short: "Set a single variable in an environment file",
+	Long: `Set the value of a single variable in an environment file without
+running the wizard.
+
+The value is validated against the variable's annotation in the
+distributable. Existing lines are updated in place and every other line of
+the target file is kept as written; new variables are appended. The target
+file is created if it does not exist.
+
+Exit codes:
+  0 - Value written
+  2 - Validation failed or distributable not found
+
+Examples:
+  krakenv set DB_PORT 5432 --target .env.local
+  krakenv set FEATURE_FLAGS '{"beta":true}' --target .env.local
+  krakenv set LEGACY_VAR anything --target .env.local --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSet,
+}
+
+func init() {
+	setCmd.Flags().StringVar(&setTarget, "target", "",
+		"Environment file to update (required)")
+	setCmd.Flags().BoolVarP(&setForce, "force", "f", false,
+		"Skip validation against the distributable")
+	setCmd.MarkFlagRequired("target")
+	setCmd.RegisterFlagCompletionFunc("target", completeEnvFiles(-1))
+
+	rootCmd.AddCommand(setCmd)
+}
+
+func runSet(_ *cobra.Command, args []string) error {
+	name, value := args[0], args[1]
+
+	if !setForce {
+		if _, err := os.Stat(distPath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "ERROR: Distributable not found: %s\n", distPath)
+			os.Exit(2)
+		}
+
+		distFile, err := loadDistFile(distPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse distributable: %w", err)
+		}
+
+		distVar := distFile.GetVariable(name)
+		if distVar == nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Variable %s is not defined in %s (use --force to set it anyway)\n", name, distPath)
+			os.Exit(2)
+		}
+
+		if err := validator.ValidateValue(value, distVar.Annotation); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Invalid value for %s: %v\n", name, err)
+			if distVar.Annotation != nil {
+				fmt.Fprintf(os.Stderr, "  → %s\n", validator.GetSuggestion(distVar.Annotation))
+			}
+			os.Exit(2)
+		}
+		value = validator.NormalizeValue(value, distVar.Annotation)
+	}
+
+	var lines []string
+	if _, err := os.Stat(setTarget); err == nil {
+		lines, err = readFileLines(setTarget)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", setTarget, err)
+		}
+	}
+
+	lines, updated, err := setLineValue(lines, name, value)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", setTarget, err)
+	}
+	if err := writeFileLines(setTarget, lines); err != nil {
+		return fmt.Errorf("failed to write %s: %w", setTarget, err)
+	}
+
+	if !quiet {
+		if updated {
+			fmt.Printf("✓ Updated %s in %s\n", name, setTarget)
+		} else {
+			fmt.Printf("✓ Added %s to %s\n", name, setTarget)
+		}
+	}
+
+	return nil
+}
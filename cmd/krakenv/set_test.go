@@ -0,0 +1,115 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSet_Heredoc(t *testing.T) {
+	target := writeTestFile(t, t.TempDir(), ".env.local", heredocFile)
+	setGlobal(t, &setTarget, target)
+	setGlobal(t, &setForce, true)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runSet(nil, []string{"FOO", "baz"}))
+	assert.Equal(t, strings.Replace(heredocFile, "FOO=bar", "FOO=baz", 1), readTestFile(t, target))
+}
+
+func TestRunSet_HeredocValue(t *testing.T) {
+	target := writeTestFile(t, t.TempDir(), ".env.local", heredocFile)
+	setGlobal(t, &setTarget, target)
+	setGlobal(t, &setForce, true)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runSet(nil, []string{"CERT", "new\ncert"}))
+	assert.Equal(t, "# Certificates\nCERT=<<EOF #prompt:Certificate?|string\nnew\ncert\nEOF\nFOO=bar\n", readTestFile(t, target))
+}
+
+const setDistFile = `PORT=8080 #prompt:Port?|int;min:1;max:65535
+LEVEL=info #prompt:Level?|enum;options:Debug,Info;nocase
+`
+
+func TestRunSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		varName string
+		value   string
+		want    string
+	}{
+		{"update in place", "# App\nPORT=80 # http\nLEVEL=Info\n", "PORT", "5432", "# App\nPORT=5432 # http\nLEVEL=Info\n"},
+		{"append", "# App\nLEVEL=Info\n", "PORT", "5432", "# App\nLEVEL=Info\nPORT=5432\n"},
+		{"normalized enum", "LEVEL=Info\n", "LEVEL", "debug", "LEVEL=Debug\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setGlobal(t, &distPath, writeTestFile(t, dir, ".env.dist", setDistFile))
+			target := writeTestFile(t, dir, ".env.local", tt.content)
+			setGlobal(t, &setTarget, target)
+			setGlobal(t, &setForce, false)
+			setGlobal(t, &quiet, true)
+
+			require.NoError(t, runSet(nil, []string{tt.varName, tt.value}))
+			assert.Equal(t, tt.want, readTestFile(t, target))
+		})
+	}
+}
+
+func TestRunSet_CreatesTarget(t *testing.T) {
+	dir := t.TempDir()
+	setGlobal(t, &distPath, writeTestFile(t, dir, ".env.dist", setDistFile))
+	target := filepath.Join(dir, ".env.local")
+	setGlobal(t, &setTarget, target)
+	setGlobal(t, &setForce, false)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runSet(nil, []string{"PORT", "5432"}))
+	assert.Equal(t, "PORT=5432\n", readTestFile(t, target))
+}
+
+func TestRunSet_Force(t *testing.T) {
+	dir := t.TempDir()
+	setGlobal(t, &distPath, writeTestFile(t, dir, ".env.dist", setDistFile))
+	target := writeTestFile(t, dir, ".env.local", "PORT=80\n")
+	setGlobal(t, &setTarget, target)
+	setGlobal(t, &setForce, true)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runSet(nil, []string{"PORT", "not a port"}))
+	require.NoError(t, runSet(nil, []string{"UNDECLARED", "x"}))
+	assert.Equal(t, "PORT=\"not a port\"\nUNDECLARED=x\n", readTestFile(t, target))
+}
+
+func TestRunSet_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		varName string
+		value   string
+		stderr  string
+	}{
+		{"invalid value", "PORT", "70000", "ERROR: Invalid value for PORT"},
+		{"undeclared variable", "UNDECLARED", "x", "ERROR: Variable UNDECLARED is not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setGlobal(t, &distPath, writeTestFile(t, dir, ".env.dist", setDistFile))
+			setGlobal(t, &setTarget, writeTestFile(t, dir, ".env.local", "PORT=80\n"))
+			setGlobal(t, &setForce, false)
+			setGlobal(t, &quiet, true)
+
+			code, _, stderr := runExit(t, func() {
+				runSet(nil, []string{tt.varName, tt.value})
+			})
+			assert.Equal(t, 2, code)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+}
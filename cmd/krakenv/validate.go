@@ -1,27 +1,68 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/log"
 	"github.com/theburrowhub/krakenv/internal/parser"
 	"github.com/theburrowhub/krakenv/internal/validator"
 )
 
 var (
-	validateStrict bool
+	validateStrict          bool
+	validateContinueOnError bool
+	validateFormat          string
+	validateJSON            bool
+	validateFix             bool
+	validateQuietExit       bool
+	validateReport          string
+	validateMaxErrors       int
+)
+
+// Output formats for validate.
+const (
+	validateFormatText   = "text"
+	validateFormatJSON   = "json"
+	validateFormatGitHub = "github"
 )
 
 var validateCmd = &cobra.Command{
-	Use:   "validate <target>",
-	Short: "Validate an environment file against the distributable annotations",
-	Long: `Validate that all values in an environment file comply with the
-annotations defined in the distributable.
+	Use:   "validate <target>...",
+	Short: "Validate environment files against the distributable annotations",
+	Long: `Validate that all values in one or more environment files comply with
+the annotations defined in the distributable.
+
+Targets may be paths or glob patterns; each matching file is validated and
+reported in its own section. The distributable itself is skipped if matched.
 
 Useful for CI/CD pipelines or pre-commit hooks to catch configuration errors early.
 
+Use --fix to correct invalid values interactively: you are prompted for
+each one, entries are re-validated as you type them, and the corrections are
+written back to the target before it is validated.
+
+Use --json for an array with a summary of each file (file, valid,
+errorCount, errors and warnings), even for a single target. Use --format
+json for a flat array of errors, or --format github for GitHub Actions
+annotations.
+
+Use --report-file to also save the full report, in the chosen format, to a
+file; it is written even when --quiet or --quiet-exit silence the console.
+
+Use --max-errors to list at most N errors per file on the console; the rest
+are counted ("... and M more", or "omitted" with --json) and still fail
+validation. The report file always lists every error.
+
+Use --quiet-exit in shell conditions: nothing is printed, not even errors,
+and only the exit code tells the result.
+
 Exit codes:
   0 - All validations passed
   1 - Validation errors found
@@ -30,65 +71,305 @@ Exit codes:
 Examples:
   krakenv validate .env.local
   krakenv validate .env.testing --strict
-  krakenv validate .env.production --non-interactive`,
-	Args: cobra.ExactArgs(1),
+  krakenv validate '.env.*' --continue-on-error
+  krakenv validate .env.production --non-interactive
+  krakenv validate .env.local --json
+  krakenv validate .env.local --fix
+  krakenv validate .env.legacy --max-errors 10
+  if krakenv validate .env.local --quiet-exit; then ...; fi
+  krakenv validate .env.local --format json
+  krakenv validate '.env.*' --format github
+  krakenv validate '.env.*' --json --report-file reports/validate.json`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runValidate,
 }
 
 func init() {
 	validateCmd.Flags().BoolVarP(&validateStrict, "strict", "s", false,
 		"Require all variables to have annotations")
+	validateCmd.Flags().BoolVar(&validateContinueOnError, "continue-on-error", false,
+		"Keep validating remaining files when one cannot be read")
+	validateCmd.Flags().StringVar(&validateFormat, "format", validateFormatText,
+		"Output format: text, json or github")
+	validateCmd.Flags().BoolVarP(&validateJSON, "json", "j", false,
+		"Output a JSON array with a summary of each file (for scripting)")
+	validateCmd.Flags().BoolVar(&validateFix, "fix", false,
+		"Prompt for corrections to invalid values and write them to the target")
+	validateCmd.Flags().BoolVar(&validateQuietExit, "quiet-exit", false,
+		"Print nothing, not even errors; report only through the exit code")
+	validateCmd.Flags().IntVar(&validateMaxErrors, "max-errors", 0,
+		"List at most this many errors per file on the console (0: no limit)")
+	validateCmd.Flags().StringVar(&validateReport, "report-file", "",
+		"Also write the full report, in the chosen format, to this file")
 
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(_ *cobra.Command, args []string) error {
-	targetPath := args[0]
+	switch validateFormat {
+	case validateFormatText, validateFormatJSON, validateFormatGitHub:
+	default:
+		return fmt.Errorf("invalid format %q (use: text, json, github)", validateFormat)
+	}
+	if validateMaxErrors < 0 {
+		return fmt.Errorf("--max-errors must be 0 or more, got %d", validateMaxErrors)
+	}
+	if validateJSON && validateFormat != validateFormatText {
+		return fmt.Errorf("--json cannot be combined with --format %s", validateFormat)
+	}
 
-	// Check target exists
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "ERROR: File not found: %s\n", targetPath)
-		os.Exit(2)
+	if validateQuietExit {
+		if validateJSON || validateFormat != validateFormatText || validateFix {
+			return fmt.Errorf("--quiet-exit cannot be combined with --json, --format or --fix")
+		}
+		quiet = true
 	}
 
-	// Parse distributable
-	distFile, err := parser.ParseEnvFile(distPath)
+	targets, err := expandTargets(args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse distributable %s: %v\n", distPath, err)
-		os.Exit(2)
+		return err
 	}
 
-	// Parse target file
-	targetFile, err := parser.ParseEnvFile(targetPath)
+	if validateFix {
+		if nonInteractive {
+			return fmt.Errorf("--fix prompts for new values; run it without --non-interactive")
+		}
+		if len(targets) != 1 {
+			return fmt.Errorf("--fix takes a single target, got %d", len(targets))
+		}
+	}
+
+	// Parse distributable
+	distFile, err := loadDistributable()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to parse target %s: %v\n", targetPath, err)
+		validateError("Failed to parse distributable %s: %v", distLabel(), err)
 		os.Exit(2)
 	}
 
+	// Problems in the distributable apply to every target; report them once
+	if !quiet {
+		for _, w := range distFile.Warnings {
+			fmt.Fprintf(os.Stderr, "WARNING: %s %s\n", distLabel(), w)
+		}
+	}
+
 	// Override strict from config if set
 	strictMode := validateStrict
 	if !strictMode && distFile.Config != nil {
 		strictMode = distFile.Config.Strict
 	}
 
-	// Validate
-	result := validateFile(distFile, targetFile, strictMode)
+	if validateFix {
+		if err := fixInvalidValues(distFile, targets[0]); err != nil {
+			return err
+		}
+	}
+
+	exitCode := 0
+	passed, failed := 0, 0
+	jsonErrors := make([]validator.JSONError, 0)
+	shownJSONErrors := make([]validator.JSONError, 0)
+	jsonResults := make([]validator.JSONResult, 0, len(targets))
+	shownJSONResults := make([]validator.JSONResult, 0, len(targets))
 
-	// Output results
-	if !quiet {
-		fmt.Print(result.FormatErrors(targetPath))
+	// The report file gets the full report; the console gets it with at most
+	// --max-errors errors per file, when it is printed at all
+	var report strings.Builder
+	emit := func(full, shown string, console bool) {
+		report.WriteString(full)
+		if console {
+			fmt.Print(shown)
+		}
 	}
 
-	if !result.Valid {
-		os.Exit(1)
+	for _, targetPath := range targets {
+		// Check target exists
+		if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+			validateError("File not found: %s", targetPath)
+			if !validateContinueOnError {
+				os.Exit(2)
+			}
+			exitCode = 2
+			failed++
+			continue
+		}
+
+		// Parse target file
+		targetFile, err := parser.ParseEnvFile(targetPath)
+		if err != nil {
+			validateError("Failed to parse target %s: %v", targetPath, err)
+			if !validateContinueOnError {
+				os.Exit(2)
+			}
+			exitCode = 2
+			failed++
+			continue
+		}
+
+		// Validate
+		result := validateFile(distFile, targetFile, strictMode)
+		shown := result.Limit(validateMaxErrors)
+		log.Debug("file validated", "target", targetPath, "errors", result.ErrorCount(), "warnings", len(result.Warnings))
+
+		// Output results
+		switch {
+		case validateJSON:
+			jsonResults = append(jsonResults, result.JSONResult(targetPath))
+			shownJSONResults = append(shownJSONResults, shown.JSONResult(targetPath))
+		case validateFormat == validateFormatJSON:
+			jsonErrors = append(jsonErrors, result.JSONErrors(targetPath)...)
+			shownJSONErrors = append(shownJSONErrors, shown.JSONErrors(targetPath)...)
+			// A flat array of errors has no room for the count
+			if shown.Omitted > 0 && !quiet {
+				fmt.Fprintf(os.Stderr, "WARNING: %s: ... and %d more error(s) not listed\n", targetPath, shown.Omitted)
+			}
+		case validateFormat == validateFormatGitHub:
+			emit(result.FormatGitHub(targetPath), shown.FormatGitHub(targetPath), true)
+		default:
+			if passed+failed > 0 {
+				emit("\n", "\n", !quiet)
+			}
+			emit(result.FormatErrors(targetPath), shown.FormatErrors(targetPath), !quiet)
+		}
+
+		if result.Valid {
+			passed++
+		} else {
+			failed++
+			if exitCode == 0 {
+				exitCode = 1
+			}
+		}
 	}
 
+	if validateJSON || validateFormat == validateFormatJSON {
+		var full, shown any = jsonErrors, shownJSONErrors
+		if validateJSON {
+			full, shown = jsonResults, shownJSONResults
+		}
+		fullData, err := json.MarshalIndent(full, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		shownData, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		emit(string(fullData)+"\n", string(shownData)+"\n", true)
+	}
+
+	if len(targets) > 1 && validateFormat == validateFormatText && !validateJSON {
+		summary := fmt.Sprintf("\nValidated %d file(s): %d passed, %d failed\n", len(targets), passed, failed)
+		emit(summary, summary, !quiet)
+	}
+
+	if validateReport != "" {
+		if err := writeReportFile(validateReport, report.String()); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+
+	return nil
+}
+
+// validateError reports a file that could not be validated, unless
+// --quiet-exit leaves it to the exit code.
+func validateError(format string, args ...any) {
+	if !validateQuietExit {
+		fmt.Fprintf(os.Stderr, "ERROR: "+format+"\n", args...)
+	}
+}
+
+// fixInvalidValues runs the sync wizard over just the invalid values of the
+// target and writes the corrections back. A target that cannot be read is
+// left for validation to report.
+func fixInvalidValues(distFile *parser.EnvFile, targetPath string) error {
+	targetFile, err := parser.ParseEnvFile(targetPath)
+	if err != nil {
+		return nil
+	}
+
+	result := inspector.Inspect(distFile, targetFile)
+	if len(result.InvalidValues) == 0 {
+		if !quiet {
+			fmt.Printf("No invalid values to fix in %s\n\n", targetPath)
+		}
+		return nil
+	}
+
+	// Missing and extra variables are inspect --sync's job
+	result.MissingInEnv = nil
+	result.ExtraInEnv = nil
+	if err := runInteractiveSync(result, distFile, targetFile, targetPath); err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Println()
+	}
 	return nil
 }
 
+// expandTargets resolves target arguments into file paths. Arguments containing
+// glob characters are expanded; the distributable is never included in the result.
+// It fails if nothing but the distributable matched.
+func expandTargets(args []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, path := range distPaths {
+		seen[filepath.Clean(path)] = true
+	}
+
+	var targets []string
+	for _, arg := range args {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			globbed, err := filepath.Glob(arg)
+			if err == nil && len(globbed) > 0 {
+				matches = globbed
+			}
+		}
+
+		for _, m := range matches {
+			if seen[filepath.Clean(m)] {
+				continue
+			}
+			seen[filepath.Clean(m)] = true
+			targets = append(targets, m)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets matched %s (the distributable is not a target)", strings.Join(args, " "))
+	}
+	return targets, nil
+}
+
+// validateFile validates a target against the distributable, keeping every
+// error; ValidationResult.Limit trims them for display.
 func validateFile(distFile, targetFile *parser.EnvFile, strict bool) *validator.ValidationResult {
 	result := validator.NewValidationResult()
 
+	// Messages quoting a secret value are reported with it masked
+	redact := inspector.Redactor{Secrets: inspector.SecretNames(distFile)}
+
+	// Lines the parser skipped or ignored
+	for _, w := range targetFile.Warnings {
+		result.AddWarning(validator.NewParseWarning(w.Variable, w.LineNumber, w.Message))
+	}
+
+	// Duplicate definitions fail in strict mode and warn otherwise
+	for _, dup := range targetFile.Duplicates {
+		err := validator.NewDuplicateVariableError(dup.Name, dup.DuplicateLine, dup.FirstLine)
+		if strict {
+			result.AddError(err)
+		} else {
+			result.AddWarning(err)
+		}
+	}
+
 	// Validate each variable in target against dist annotations
 	for _, distVar := range distFile.Variables {
 		targetVar := targetFile.GetVariable(distVar.Name)
@@ -102,6 +383,8 @@ func validateFile(distFile, targetFile *parser.EnvFile, strict bool) *validator.
 					0,
 					distVar.Annotation.PromptText,
 				))
+			} else {
+				log.Debug("variable not validated", "name", distVar.Name, "reason", "missing and not required")
 			}
 			continue
 		}
@@ -116,6 +399,8 @@ func validateFile(distFile, targetFile *parser.EnvFile, strict bool) *validator.
 					Suggestion: "Add an annotation to the distributable",
 					Type:       validator.ErrorAnnotationSyntax,
 				})
+			} else {
+				log.Debug("variable not validated", "name", distVar.Name, "reason", "no annotation")
 			}
 			continue
 		}
@@ -125,13 +410,18 @@ func validateFile(distFile, targetFile *parser.EnvFile, strict bool) *validator.
 			result.AddError(validator.ValidationError{
 				Variable:   distVar.Name,
 				LineNumber: targetVar.LineNumber,
-				Message:    err.Error(),
-				Suggestion: validator.GetSuggestion(distVar.Annotation),
+				Message:    redact.Text(distVar.Name, targetVar.Value, err.Error()),
+				Suggestion: redact.Text(distVar.Name, targetVar.Value, validator.GetErrorSuggestion(err, distVar.Annotation)),
 				Example:    validator.GetExample(distVar.Annotation),
 				Type:       validator.ErrorInvalidType,
 			})
 		}
 	}
 
+	// Constraints between variables
+	for _, err := range validator.ValidateRelations(distFile, targetFile) {
+		result.AddError(err)
+	}
+
 	return result
 }
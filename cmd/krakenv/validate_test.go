@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/tui/sync"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+func TestValidateFile_EncodingSuggestion(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent(`KEY= #prompt:Key?|string;encoding:base64
+TOKEN= #prompt:Token?|string;encoding:hex
+`, ".env.dist")
+	require.NoError(t, err)
+	targetFile, err := parser.ParseEnvFileContent("KEY=not base64!\nTOKEN=xyz\n", ".env.local")
+	require.NoError(t, err)
+
+	result := validateFile(distFile, targetFile, false)
+
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "KEY", result.Errors[0].Variable)
+	assert.Equal(t, 1, result.Errors[0].LineNumber)
+	assert.Contains(t, result.Errors[0].Message, "invalid base64 encoding")
+	assert.Contains(t, result.Errors[0].Suggestion, "Re-encode the value as standard base64")
+	assert.Equal(t, validator.ErrorInvalidType, result.Errors[0].Type)
+	assert.Equal(t, "TOKEN", result.Errors[1].Variable)
+	assert.Contains(t, result.Errors[1].Suggestion, "Re-encode the value as hex")
+}
+
+func TestValidateFile_RedactsSecrets(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent(`API_KEY= #prompt:Key?|string;secret;pattern:^sk_
+PORT= #prompt:Port?|int;secret
+`, ".env.dist")
+	require.NoError(t, err)
+	targetFile, err := parser.ParseEnvFileContent("API_KEY=hunter2\nPORT=not-a-port\n", ".env.local")
+	require.NoError(t, err)
+
+	result := validateFile(distFile, targetFile, false)
+	require.Len(t, result.Errors, 2)
+
+	jsonOutput, err := json.Marshal(result.JSONResult(".env.local"))
+	require.NoError(t, err)
+	outputs := map[string]string{
+		"text":   result.FormatErrors(".env.local"),
+		"json":   string(jsonOutput),
+		"github": result.FormatGitHub(".env.local"),
+	}
+	for format, output := range outputs {
+		assert.NotContains(t, output, "hunter2", format)
+		assert.NotContains(t, output, "not-a-port", format)
+	}
+	assert.Contains(t, outputs["text"], "API_KEY")
+}
+
+func TestExpandTargets(t *testing.T) {
+	dir := t.TempDir()
+	dist := writeTestFile(t, dir, ".env.dist", "")
+	local := writeTestFile(t, dir, ".env.local", "")
+	prod := writeTestFile(t, dir, ".env.production", "")
+	setGlobal(t, &distPaths, []string{dist})
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"plain paths", []string{local, prod}, []string{local, prod}, false},
+		{"glob skips distributable", []string{filepath.Join(dir, ".env.*")}, []string{local, prod}, false},
+		{"duplicates dropped", []string{local, filepath.Join(dir, ".env.l*")}, []string{local}, false},
+		{"unmatched glob kept", []string{filepath.Join(dir, "*.missing")}, []string{filepath.Join(dir, "*.missing")}, false},
+		{"only the distributable", []string{dist}, nil, true},
+		{"glob matching only the distributable", []string{filepath.Join(dir, "*.dist")}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := expandTargets(tt.args)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "no targets matched")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, targets)
+		})
+	}
+}
+
+func TestRunValidate_JSONArray(t *testing.T) {
+	dir := t.TempDir()
+	dist := writeTestFile(t, dir, ".env.dist", "PORT=8080 #prompt:Port?|int\n")
+	target := writeTestFile(t, dir, ".env.local", "PORT=3000\n")
+	report := filepath.Join(dir, "report.json")
+	setGlobal(t, &distPaths, []string{dist})
+	setGlobal(t, &distPath, dist)
+	setGlobal(t, &validateJSON, true)
+	setGlobal(t, &validateReport, report)
+	setGlobal(t, &quiet, true)
+
+	require.NoError(t, runValidate(nil, []string{target}))
+
+	var results []validator.JSONResult
+	require.NoError(t, json.Unmarshal([]byte(readTestFile(t, report)), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, target, results[0].File)
+	assert.True(t, results[0].Valid)
+}
+
+func TestRunValidate_FixFlags(t *testing.T) {
+	dir := t.TempDir()
+	dist := writeTestFile(t, dir, ".env.dist", "PORT=8080 #prompt:Port?|int\n")
+	local := writeTestFile(t, dir, ".env.local", "PORT=abc\n")
+	prod := writeTestFile(t, dir, ".env.production", "PORT=abc\n")
+	setGlobal(t, &distPaths, []string{dist})
+	setGlobal(t, &validateFix, true)
+
+	t.Run("non-interactive", func(t *testing.T) {
+		setGlobal(t, &nonInteractive, true)
+		assert.ErrorContains(t, runValidate(nil, []string{local}), "run it without --non-interactive")
+	})
+
+	t.Run("several targets", func(t *testing.T) {
+		assert.ErrorContains(t, runValidate(nil, []string{local, prod}), "--fix takes a single target, got 2")
+	})
+
+	t.Run("quiet exit", func(t *testing.T) {
+		setGlobal(t, &validateQuietExit, true)
+		assert.ErrorContains(t, runValidate(nil, []string{local}), "cannot be combined")
+	})
+
+	assert.Equal(t, "PORT=abc\n", readTestFile(t, local))
+}
+
+func TestRunValidate_FixNothingInvalid(t *testing.T) {
+	dir := t.TempDir()
+	setGlobal(t, &distPaths, []string{writeTestFile(t, dir, ".env.dist", "PORT=8080 #prompt:Port?|int\n")})
+	target := writeTestFile(t, dir, ".env.local", "PORT=3000\n")
+	setGlobal(t, &validateFix, true)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runValidate(nil, []string{target}))
+	})
+	assert.Contains(t, out, "No invalid values to fix in "+target)
+	assert.Equal(t, "PORT=3000\n", readTestFile(t, target))
+}
+
+func TestApplyResolutions_FixedValues(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), ".env.local", "# App\nPORT=abc # http\nHOST=x\n")
+	targetFile, err := parser.ParseEnvFile(path)
+	require.NoError(t, err)
+	setGlobal(t, &quiet, true)
+
+	// The wizard's corrections for invalid values replace them in place
+	require.NoError(t, applyResolutions([]sync.Resolution{
+		{Variable: parser.Variable{Name: "PORT"}, Action: sync.ActionAdd, NewValue: "8080"},
+	}, nil, targetFile, path))
+	assert.Equal(t, "# App\nPORT=8080 # http\nHOST=x\n", readTestFile(t, path))
+}
+
+func TestRunValidate_QuietExit(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		code   int
+	}{
+		{"valid", "PORT=3000\n", 0},
+		{"invalid", "PORT=abc\nEXTRA=1\n", 1},
+		{"not found", "", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// The unknown constraint makes the distributable print a warning
+			setGlobal(t, &distPaths, []string{writeTestFile(t, dir, ".env.dist",
+				"PORT=8080 #prompt:Port?|int;mn:1\n")})
+			target := filepath.Join(dir, ".env.local")
+			if tt.target != "" {
+				writeTestFile(t, dir, ".env.local", tt.target)
+			}
+			setGlobal(t, &validateQuietExit, true)
+
+			code, stdout, stderr := runExit(t, func() {
+				require.NoError(t, runValidate(nil, []string{target}))
+			})
+			assert.Equal(t, tt.code, code)
+			assert.Empty(t, stdout)
+			assert.Empty(t, stderr)
+		})
+	}
+}
+
+func TestRunValidate_QuietExitFlags(t *testing.T) {
+	setGlobal(t, &validateQuietExit, true)
+	setGlobal(t, &quiet, false)
+
+	t.Run("json", func(t *testing.T) {
+		setGlobal(t, &validateJSON, true)
+		assert.ErrorContains(t, runValidate(nil, []string{".env.local"}), "--quiet-exit cannot be combined")
+	})
+
+	t.Run("format", func(t *testing.T) {
+		setGlobal(t, &validateFormat, validateFormatGitHub)
+		assert.ErrorContains(t, runValidate(nil, []string{".env.local"}), "--quiet-exit cannot be combined")
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// watchDebounce is how long to wait after a change before regenerating, so
+// the several events of a single save trigger one regeneration.
+const watchDebounce = 100 * time.Millisecond
+
+// watchDistributable regenerates targets once, then again whenever a
+// distributable changes, until interrupted. The directories holding the
+// distributables are watched rather than the files, so editors that save by
+// replacing the file are still noticed.
+func watchDistributable(targets []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watching: %w", err)
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool, len(distPaths))
+	dirs := make(map[string]bool)
+	for _, path := range distPaths {
+		watched[filepath.Clean(path)] = true
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	if !quiet {
+		fmt.Printf("Watching %s (Ctrl+C to stop)\n", distLabel())
+	}
+
+	regenerate := func() {
+		for _, target := range targets {
+			regenerateTarget(target)
+		}
+	}
+	regenerate()
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if !quiet {
+				fmt.Println("Stopped watching")
+			}
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if watched[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: Watch error: %v\n", err)
+		case <-debounce.C:
+			regenerate()
+		}
+	}
+}
+
+// regenerateTarget updates a target without prompting. Existing target lines
+// are kept, even empty ones; new variables are written only if they have a
+// default or are optional, and variables that need a value are reported.
+func regenerateTarget(targetPath string) {
+	stamp := time.Now().Format("15:04:05")
+
+	distFile, err := loadDistributable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ✗ Failed to parse %s: %v\n", stamp, distLabel(), err)
+		return
+	}
+
+	gen := newTargetGenerator(distFile, targetPath, false)
+	if err := gen.LoadTarget(); err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ✗ %v\n", stamp, err)
+		return
+	}
+
+	// Leave out new variables that would need a prompt
+	pending := make(map[string]bool)
+	for _, v := range gen.GetVariablesToPrompt() {
+		if v.Annotation != nil && !v.Annotation.IsOptional {
+			pending[v.Name] = true
+			fmt.Fprintf(os.Stderr, "[%s] ⚠ %s needs a value; run 'krakenv generate %s' to set it\n", stamp, v.Name, targetPath)
+		}
+	}
+
+	var variables []parser.Variable
+	for _, v := range gen.MergeVariables(nil) {
+		if !pending[v.Name] || (gen.TargetFile != nil && gen.TargetFile.HasVariable(v.Name)) {
+			variables = append(variables, v)
+		}
+	}
+
+	if err := gen.WriteFile(variables); err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ✗ Failed to write %s: %v\n", stamp, targetPath, err)
+		return
+	}
+
+	if !quiet {
+		fmt.Printf("[%s] ✓ Regenerated %s with %d variables (%d pending)\n", stamp, targetPath, len(variables), len(pending))
+	}
+}
@@ -2,15 +2,23 @@
 package config
 
 import (
+	"net/url"
 	"strings"
 )
 
 // KrakenvConfig represents project-level configuration extracted from distributable.
 // Configuration is stored as special comments: #krakenv:KEY=VALUE.
 type KrakenvConfig struct {
-	Environments []string // e.g., ["local", "testing", "production"]
-	Strict       bool     // If true, unannotated variables are errors
-	DistPath     string   // Override default .env.dist path
+	Environments   []string // e.g., ["local", "testing", "production"]
+	Strict         bool     // If true, unannotated variables are errors
+	DistPath       string   // Override default .env.dist path
+	Ignore         []string // Glob patterns of variables expected in environments but not in the dist
+	KeepWhitespace bool     // Keep whitespace around values instead of trimming it (trim=false)
+
+	// Defaults records, in a generated target, the dist default each value
+	// was written from (defaults=, URL query encoded), so a later merge can
+	// tell values left at their default from local overrides.
+	Defaults map[string]string
 }
 
 // DefaultConfig returns a KrakenvConfig with default values.
@@ -59,26 +67,60 @@ func ParseConfig(lines []string) *KrakenvConfig {
 
 		switch key {
 		case "environments":
-			envs := strings.Split(value, ",")
-			config.Environments = make([]string, 0, len(envs))
-			for _, env := range envs {
-				env = strings.TrimSpace(env)
-				if env != "" {
-					config.Environments = append(config.Environments, env)
-				}
-			}
+			config.Environments = splitList(value)
 		case "strict":
 			config.Strict = value == "true" || value == "1" || value == "yes"
 		case "distPath":
 			if value != "" {
 				config.DistPath = value
 			}
+		case "trim":
+			config.KeepWhitespace = value == "false" || value == "0" || value == "no"
+		case "ignore":
+			config.Ignore = append(config.Ignore, splitList(value)...)
+		case "defaults":
+			config.Defaults = ParseDefaults(value)
 		}
 	}
 
 	return config
 }
 
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// ParseDefaults decodes the value of a defaults= line. Malformed entries are
+// dropped.
+func ParseDefaults(value string) map[string]string {
+	query, _ := url.ParseQuery(value)
+	defaults := make(map[string]string, len(query))
+	for name, values := range query {
+		if name != "" && len(values) > 0 {
+			defaults[name] = values[len(values)-1]
+		}
+	}
+	return defaults
+}
+
+// FormatDefaults encodes recorded dist defaults as the value of a defaults=
+// line, sorted by name.
+func FormatDefaults(defaults map[string]string) string {
+	query := make(url.Values, len(defaults))
+	for name, value := range defaults {
+		query.Set(name, value)
+	}
+	return query.Encode()
+}
+
 // FormatConfigLine formats a configuration key-value pair as a comment line.
 func FormatConfigLine(key, value string) string {
 	return "#krakenv:" + key + "=" + value
@@ -96,5 +138,16 @@ func FormatConfig(config *KrakenvConfig) []string {
 		lines = append(lines, FormatConfigLine("strict", "true"))
 	}
 
+	if config.KeepWhitespace {
+		lines = append(lines, FormatConfigLine("trim", "false"))
+	}
+
+	if len(config.Ignore) > 0 {
+		lines = append(lines, FormatConfigLine("ignore", strings.Join(config.Ignore, ",")))
+	}
+	if len(config.Defaults) > 0 {
+		lines = append(lines, FormatConfigLine("defaults", FormatDefaults(config.Defaults)))
+	}
+
 	return lines
 }
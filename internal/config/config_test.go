@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,6 +109,35 @@ func TestParseConfig(t *testing.T) {
 	assert.Equal(t, "config/.env.dist", config.DistPath)
 }
 
+func TestParseConfig_Ignore(t *testing.T) {
+	lines := []string{
+		"#krakenv:ignore=KUBERNETES_*, CI_*,",
+		"#krakenv:ignore=HOSTNAME",
+	}
+
+	config := ParseConfig(lines)
+	require.NotNil(t, config)
+
+	assert.Equal(t, []string{"KUBERNETES_*", "CI_*", "HOSTNAME"}, config.Ignore)
+}
+
+func TestParseConfig_Trim(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"#krakenv:trim=false", true},
+		{"#krakenv:trim=no", true},
+		{"#krakenv:trim=true", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseConfig([]string{tt.input}).KeepWhitespace)
+		})
+	}
+}
+
 func TestParseConfig_Defaults(t *testing.T) {
 	config := ParseConfig(nil)
 	require.NotNil(t, config)
@@ -114,6 +145,8 @@ func TestParseConfig_Defaults(t *testing.T) {
 	assert.Equal(t, []string{"local"}, config.Environments)
 	assert.False(t, config.Strict)
 	assert.Equal(t, ".env.dist", config.DistPath)
+	assert.Empty(t, config.Ignore)
+	assert.False(t, config.KeepWhitespace)
 }
 
 func TestParseConfig_PartialOverride(t *testing.T) {
@@ -187,6 +220,22 @@ func TestFormatConfig(t *testing.T) {
 	assert.Equal(t, "#krakenv:strict=true", lines[1])
 }
 
+func TestFormatConfig_RoundTrip(t *testing.T) {
+	config := &KrakenvConfig{
+		Environments:   []string{"local", "prod"},
+		Strict:         true,
+		DistPath:       ".env.dist",
+		Ignore:         []string{"KUBERNETES_*", "CI_*"},
+		KeepWhitespace: true,
+	}
+
+	lines := FormatConfig(config)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "#krakenv:trim=false", lines[2])
+	assert.Equal(t, "#krakenv:ignore=KUBERNETES_*,CI_*", lines[3])
+	assert.Equal(t, config, ParseConfig(lines))
+}
+
 func TestFormatConfig_NoStrict(t *testing.T) {
 	config := &KrakenvConfig{
 		Environments: []string{"local"},
@@ -206,3 +255,83 @@ func TestDefaultConfig(t *testing.T) {
 	assert.False(t, config.Strict)
 	assert.Equal(t, ".env.dist", config.DistPath)
 }
+
+func TestLoadProjectConfig_SearchesUpward(t *testing.T) {
+	root := t.TempDir()
+	rc := `# Project defaults
+distPath = "services/api/.env.dist"
+environments = local, staging
+strict = true
+format = 'yaml'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ProjectConfigFile), []byte(rc), 0644))
+
+	nested := filepath.Join(root, "services", "api", "cmd")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	for _, dir := range []string{root, nested} {
+		cfg, err := LoadProjectConfig(dir)
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+
+		assert.Equal(t, filepath.Join(root, ProjectConfigFile), cfg.Path)
+		assert.Equal(t, filepath.Join(root, "services", "api", ".env.dist"), cfg.DistPath)
+		assert.Equal(t, []string{"local", "staging"}, cfg.Environments)
+		assert.True(t, cfg.Strict)
+		assert.Equal(t, "yaml", cfg.Format)
+	}
+}
+
+func TestLoadProjectConfig_NearestWins(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ProjectConfigFile), []byte("format=json\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, ProjectConfigFile), []byte("format=yaml\n"), 0644))
+
+	cfg, err := LoadProjectConfig(nested)
+	require.NoError(t, err)
+	assert.Equal(t, "yaml", cfg.Format)
+}
+
+func TestLoadProjectConfig_NotFound(t *testing.T) {
+	cfg, err := LoadProjectConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestLoadProjectConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "dist = .env.dist\n", `:1: unknown key "dist"`},
+		{"missing value", "# comment\nstrict\n", ":2: expected key = value"},
+		{"bad strict", "strict = maybe\n", `invalid strict value "maybe"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(tt.content), 0644))
+
+			_, err := LoadProjectConfig(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseConfig_RecordedDefaults(t *testing.T) {
+	defaults := map[string]string{"HOST": "localhost", "URL": "http://x?a=1&b=2", "GREETING": "hi there"}
+	line := FormatConfigLine("defaults", FormatDefaults(defaults))
+	assert.Equal(t, "#krakenv:defaults=GREETING=hi+there&HOST=localhost&URL=http%3A%2F%2Fx%3Fa%3D1%26b%3D2", line)
+
+	config := ParseConfig([]string{line})
+	assert.Equal(t, defaults, config.Defaults)
+
+	// Malformed entries are dropped
+	config = ParseConfig([]string{"#krakenv:defaults=A=1&%zz&=2"})
+	assert.Equal(t, map[string]string{"A": "1"}, config.Defaults)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectConfigFile is the name of the project config file.
+const ProjectConfigFile = ".krakenvrc"
+
+// ProjectConfig holds project defaults for command-line flags, read from a
+// .krakenvrc file. The file has one key = value pair per line; values may be
+// quoted and lines starting with # are comments:
+//
+//	distPath = "services/api/.env.dist"
+//	environments = local,testing,production
+//	strict = true
+//	format = dotenv
+type ProjectConfig struct {
+	Path         string   // File the config was read from
+	DistPath     string   // Default distributable path, relative to the config file
+	Environments []string // Environments to use when the distributable declares none
+	Strict       bool     // Validate in strict mode by default
+	Format       string   // Default output format for generate
+}
+
+// LoadProjectConfig finds the nearest .krakenvrc in startDir or one of its
+// parents and parses it. Returns nil without error if there is none.
+func LoadProjectConfig(startDir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return parseProjectConfigFile(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parseProjectConfigFile reads a .krakenvrc file.
+func parseProjectConfigFile(path string) (*ProjectConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg := &ProjectConfig{Path: path}
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		value = unquote(strings.TrimSpace(value))
+
+		switch key {
+		case "distPath":
+			if value != "" && !filepath.IsAbs(value) {
+				value = filepath.Join(filepath.Dir(path), value)
+			}
+			cfg.DistPath = value
+		case "environments":
+			for _, env := range strings.Split(value, ",") {
+				if env = strings.TrimSpace(env); env != "" {
+					cfg.Environments = append(cfg.Environments, env)
+				}
+			}
+		case "strict":
+			switch value {
+			case "true", "1", "yes":
+				cfg.Strict = true
+			case "false", "0", "no":
+				cfg.Strict = false
+			default:
+				return nil, fmt.Errorf("%s:%d: invalid strict value %q (use true or false)", path, lineNumber, value)
+			}
+		case "format":
+			cfg.Format = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q", path, lineNumber, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// unquote removes one pair of matching single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
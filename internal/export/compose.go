@@ -0,0 +1,39 @@
+package export
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// ComposeOptions controls Compose output.
+type ComposeOptions struct {
+	Service       string // Wrap the mapping in a services.<name> stub; empty for the bare environment block
+	InlineSecrets bool   // Write secret values instead of ${VAR} references
+}
+
+// Compose renders variables as a Docker Compose environment: mapping. Secret
+// variables become ${VAR} references, resolved by Compose from the shell or
+// its .env file, unless InlineSecrets is set. A literal $ in a value is
+// doubled so Compose does not interpolate it.
+func Compose(variables []parser.Variable, opts ComposeOptions) ([]byte, error) {
+	env := mapping()
+	for _, v := range variables {
+		value := strings.ReplaceAll(v.Value, "$", "$$")
+		if isSecret(v) && !opts.InlineSecrets {
+			value = "${" + v.Name + "}"
+		}
+		env.Content = append(env.Content, scalar(v.Name), scalar(value))
+	}
+	if len(env.Content) == 0 {
+		env.Style = yaml.FlowStyle
+	}
+
+	root := mapping(scalar("environment"), env)
+	if opts.Service != "" {
+		root = mapping(scalar("services"), mapping(scalar(opts.Service), root))
+	}
+	return marshal(root)
+}
@@ -0,0 +1,87 @@
+// Package export renders environment variables for deployment tools such as
+// Docker Compose and Kubernetes, and for POSIX shells.
+package export
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// Export formats.
+const (
+	FormatCompose      = "compose"
+	FormatK8s          = "k8s" // ConfigMap and Secret in one multi-document stream
+	FormatK8sConfigMap = "k8s-configmap"
+	FormatK8sSecret    = "k8s-secret"
+	FormatShell        = "shell"
+)
+
+// IsValidFormat reports whether format is a supported export format.
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatCompose, FormatK8s, FormatK8sConfigMap, FormatK8sSecret, FormatShell:
+		return true
+	default:
+		return false
+	}
+}
+
+// Merge returns the variables to export: every variable of the distributable
+// with its value from the target if set there, or its default otherwise,
+// followed by variables only the target defines. Annotations come from the
+// distributable. dist may be nil to export the target as is.
+func Merge(dist, target *parser.EnvFile) []parser.Variable {
+	if dist == nil {
+		return target.Variables
+	}
+
+	variables := make([]parser.Variable, 0, len(dist.Variables))
+	for _, v := range dist.Variables {
+		if t := target.GetVariable(v.Name); t != nil {
+			v.Value = t.Value
+			v.IsSet = t.IsSet
+		}
+		variables = append(variables, v)
+	}
+	for _, v := range target.Variables {
+		if !dist.HasVariable(v.Name) {
+			variables = append(variables, v)
+		}
+	}
+	return variables
+}
+
+// isSecret reports whether a variable is annotated secret.
+func isSecret(v parser.Variable) bool {
+	return v.Annotation != nil && v.Annotation.IsSecret
+}
+
+// mapping builds a YAML mapping node from alternating keys and values.
+func mapping(content ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Content: content}
+}
+
+// scalar builds a string node, quoted when YAML would otherwise read it as
+// another type (such as true or 8080).
+func scalar(value string) *yaml.Node {
+	node := &yaml.Node{}
+	_ = node.Encode(value) // encoding a string cannot fail
+	return node
+}
+
+// marshal encodes node as YAML with two-space indentation.
+func marshal(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
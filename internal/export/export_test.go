@@ -0,0 +1,172 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func parseFile(t *testing.T, content, path string) *parser.EnvFile {
+	t.Helper()
+	f, err := parser.ParseEnvFileContent(content, path)
+	require.NoError(t, err)
+	return f
+}
+
+func TestMerge(t *testing.T) {
+	dist := parseFile(t, `HOST=localhost #prompt:Host?|string
+PORT=8080 #prompt:Port?|int
+`, ".env.dist")
+	target := parseFile(t, "PORT=9090\nEXTRA=x\n", ".env.local")
+
+	variables := Merge(dist, target)
+
+	require.Len(t, variables, 3)
+	assert.Equal(t, "localhost", variables[0].Value)
+	assert.Equal(t, "9090", variables[1].Value)
+	assert.NotNil(t, variables[1].Annotation)
+	assert.Equal(t, "EXTRA", variables[2].Name)
+
+	assert.Equal(t, target.Variables, Merge(nil, target))
+}
+
+func TestCompose(t *testing.T) {
+	dist := parseFile(t, `PORT=8080 #prompt:Port?|int
+DEBUG=true #prompt:Debug?|boolean
+API_KEY= #prompt:Key?|string;secret
+GREETING="hello: $USER"
+`, ".env.dist")
+	target := parseFile(t, "API_KEY=s3cr3t\n", ".env.local")
+	variables := Merge(dist, target)
+
+	t.Run("environment block", func(t *testing.T) {
+		output, err := Compose(variables, ComposeOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, `environment:
+  PORT: "8080"
+  DEBUG: "true"
+  API_KEY: ${API_KEY}
+  GREETING: 'hello: $$USER'
+`, string(output))
+	})
+
+	t.Run("service stub with inline secrets", func(t *testing.T) {
+		output, err := Compose(variables, ComposeOptions{Service: "api", InlineSecrets: true})
+		require.NoError(t, err)
+
+		var doc struct {
+			Services map[string]struct {
+				Environment map[string]string `yaml:"environment"`
+			} `yaml:"services"`
+		}
+		require.NoError(t, yaml.Unmarshal(output, &doc))
+		assert.Equal(t, "s3cr3t", doc.Services["api"].Environment["API_KEY"])
+		assert.Equal(t, "8080", doc.Services["api"].Environment["PORT"])
+	})
+
+	t.Run("no variables", func(t *testing.T) {
+		output, err := Compose(nil, ComposeOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "environment: {}\n", string(output))
+	})
+}
+
+func TestKubernetes(t *testing.T) {
+	dist := parseFile(t, `PORT=8080 #prompt:Port?|int
+API_KEY= #prompt:Key?|string;secret
+`, ".env.dist")
+	target := parseFile(t, "API_KEY=s3cr3t\n", ".env.production")
+	variables := Merge(dist, target)
+	opts := KubernetesOptions{Name: "api", Namespace: "prod"}
+
+	type manifest struct {
+		APIVersion string            `yaml:"apiVersion"`
+		Kind       string            `yaml:"kind"`
+		Metadata   map[string]string `yaml:"metadata"`
+		Type       string            `yaml:"type"`
+		Data       map[string]string `yaml:"data"`
+		StringData map[string]string `yaml:"stringData"`
+	}
+
+	output, err := Kubernetes(variables, opts)
+	require.NoError(t, err)
+
+	docs := strings.Split(string(output), "---\n")
+	require.Len(t, docs, 2)
+
+	var configMap, secret manifest
+	require.NoError(t, yaml.Unmarshal([]byte(docs[0]), &configMap))
+	require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &secret))
+
+	assert.Equal(t, "v1", configMap.APIVersion)
+	assert.Equal(t, "ConfigMap", configMap.Kind)
+	assert.Equal(t, map[string]string{"name": "api", "namespace": "prod"}, configMap.Metadata)
+	assert.Equal(t, map[string]string{"PORT": "8080"}, configMap.Data)
+
+	assert.Equal(t, "Secret", secret.Kind)
+	assert.Equal(t, "Opaque", secret.Type)
+	assert.Equal(t, map[string]string{"API_KEY": "s3cr3t"}, secret.StringData)
+}
+
+func TestShell(t *testing.T) {
+	target := parseFile(t, `GREETING="hello $USER"
+QUOTE="it's"
+EMPTY=
+MULTI=<<EOF
+line one
+line two
+EOF
+`, ".env.local")
+
+	output := Shell(Merge(nil, target))
+
+	assert.Equal(t, `export GREETING='hello $USER'
+export QUOTE='it'\''s'
+export EMPTY=''
+export MULTI='line one
+line two'
+`, string(output))
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "''"},
+		{"plain", "'plain'"},
+		{"a b", "'a b'"},
+		{"$HOME `id`", "'$HOME `id`'"},
+		{"it's", `'it'\''s'`},
+		{"''", `''\'''\'''`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShellQuote(tt.value))
+		})
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".env.production", "env-production"},
+		{"config/.env.Local_Dev", "env-local-dev"},
+		{"...", "env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResourceName(tt.path))
+		})
+	}
+}
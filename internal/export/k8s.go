@@ -0,0 +1,94 @@
+package export
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// KubernetesOptions controls Kubernetes manifest output.
+type KubernetesOptions struct {
+	Name      string // metadata.name of the ConfigMap and Secret
+	Namespace string // metadata.namespace; omitted when empty
+}
+
+// ConfigMap renders the variables not annotated secret as a Kubernetes
+// ConfigMap manifest.
+func ConfigMap(variables []parser.Variable, opts KubernetesOptions) ([]byte, error) {
+	return marshal(k8sManifest("ConfigMap", "data", filterSecret(variables, false), opts))
+}
+
+// Secret renders the variables annotated secret as a Kubernetes Secret
+// manifest, with plain values under stringData.
+func Secret(variables []parser.Variable, opts KubernetesOptions) ([]byte, error) {
+	return marshal(k8sManifest("Secret", "stringData", filterSecret(variables, true), opts))
+}
+
+// Kubernetes renders both the ConfigMap and the Secret as one multi-document
+// stream, ready for kubectl apply -f -.
+func Kubernetes(variables []parser.Variable, opts KubernetesOptions) ([]byte, error) {
+	configMap, err := ConfigMap(variables, opts)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := Secret(variables, opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(configMap, "---\n"...), secret...), nil
+}
+
+// k8sManifest builds a v1 resource of the given kind with the variables as
+// a string map under dataKey.
+func k8sManifest(kind, dataKey string, variables []parser.Variable, opts KubernetesOptions) *yaml.Node {
+	metadata := mapping(scalar("name"), scalar(opts.Name))
+	if opts.Namespace != "" {
+		metadata.Content = append(metadata.Content, scalar("namespace"), scalar(opts.Namespace))
+	}
+
+	data := mapping()
+	for _, v := range variables {
+		data.Content = append(data.Content, scalar(v.Name), scalar(v.Value))
+	}
+
+	root := mapping(
+		scalar("apiVersion"), scalar("v1"),
+		scalar("kind"), scalar(kind),
+		scalar("metadata"), metadata,
+	)
+	if kind == "Secret" {
+		root.Content = append(root.Content, scalar("type"), scalar("Opaque"))
+	}
+	if len(data.Content) > 0 {
+		root.Content = append(root.Content, scalar(dataKey), data)
+	}
+	return root
+}
+
+// filterSecret returns the variables whose secret annotation matches secret.
+func filterSecret(variables []parser.Variable, secret bool) []parser.Variable {
+	var result []parser.Variable
+	for _, v := range variables {
+		if isSecret(v) == secret {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ResourceName derives a Kubernetes resource name from a file path, such as
+// "env-production" from ".env.production".
+func ResourceName(path string) string {
+	name := strings.ToLower(filepath.Base(path))
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "env"
+	}
+	return name
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// Shell renders variables as POSIX shell export statements, one per line, for
+// eval or source. Every value is single-quoted so the shell expands nothing.
+func Shell(variables []parser.Variable) []byte {
+	var b strings.Builder
+	for _, v := range variables {
+		b.WriteString("export ")
+		b.WriteString(v.Name)
+		b.WriteByte('=')
+		b.WriteString(ShellQuote(v.Value))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// ShellQuote single-quotes a value for a POSIX shell. Each embedded
+// apostrophe closes the quotes, is written as \' and reopens them.
+func ShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/theburrowhub/krakenv/internal/log"
+)
+
+// Backup modes for files about to be overwritten.
+const (
+	BackupSimple    = "simple"    // Copy to <path>.bak
+	BackupTimestamp = "timestamp" // Copy to <path>.<YYYYMMDD-HHMMSS>.bak
+)
+
+// IsValidBackupMode reports whether mode is a supported backup mode.
+func IsValidBackupMode(mode string) bool {
+	return mode == BackupSimple || mode == BackupTimestamp
+}
+
+// BackupFile copies an existing file before it is overwritten and returns the
+// backup path. It returns an empty path if the file does not exist.
+func BackupFile(path, mode string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Debug("no backup needed", "path", path, "reason", "file does not exist")
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s for backup: %w", path, err)
+	}
+
+	backupPath := path + ".bak"
+	if mode == BackupTimestamp {
+		backupPath = fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s for backup: %w", path, err)
+	}
+	if err := os.WriteFile(backupPath, data, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to write backup %s: %w", backupPath, err)
+	}
+	log.Debug("backup created", "path", path, "backup", backupPath)
+
+	return backupPath, nil
+}
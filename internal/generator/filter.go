@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// Filter restricts generation to a subset of variables by name.
+// A nil Filter selects every variable.
+type Filter struct {
+	only   map[string]bool
+	except map[string]bool
+}
+
+// NewFilter creates a Filter from --only and --except name lists. Either list
+// may be empty; it returns nil when both are.
+func NewFilter(only, except []string) *Filter {
+	if len(only) == 0 && len(except) == 0 {
+		return nil
+	}
+
+	f := &Filter{except: nameSet(except)}
+	if len(only) > 0 {
+		f.only = nameSet(only)
+	}
+	return f
+}
+
+// Includes reports whether the named variable is selected.
+func (f *Filter) Includes(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.only != nil && !f.only[name] {
+		return false
+	}
+	return !f.except[name]
+}
+
+// Unmatched returns the filter's names that are not defined in the
+// distributable, in sorted order.
+func (f *Filter) Unmatched(distFile *parser.EnvFile) []string {
+	if f == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var unmatched []string
+	for _, set := range []map[string]bool{f.only, f.except} {
+		for name := range set {
+			if !seen[name] && distFile.GetVariable(name) == nil {
+				seen[name] = true
+				unmatched = append(unmatched, name)
+			}
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
+}
+
+// nameSet builds a set from a list of names, ignoring surrounding spaces and
+// empty entries.
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+// Output formats supported by the generator.
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+	FormatYAML   = "yaml"
+)
+
+// IsValidFormat reports whether format is a supported output format.
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatDotenv, FormatJSON, FormatYAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatOutput renders variables as a JSON object or YAML map of name to value,
+// preserving variable order. Values of int, numeric and boolean variables are emitted
+// as native types when they parse; everything else is a string.
+func formatOutput(variables []parser.Variable, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		buf.WriteString("{\n")
+		for i, v := range variables {
+			key, err := json.Marshal(v.Name)
+			if err != nil {
+				return nil, err
+			}
+			value, err := json.Marshal(typedValue(v))
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteString("  " + string(key) + ": " + string(value))
+			if i < len(variables)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+		return buf.Bytes(), nil
+
+	case FormatYAML:
+		root := &yaml.Node{Kind: yaml.MappingNode}
+		for _, v := range variables {
+			valueNode := &yaml.Node{}
+			if err := valueNode.Encode(typedValue(v)); err != nil {
+				return nil, err
+			}
+			root.Content = append(root.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: v.Name},
+				valueNode,
+			)
+		}
+		if len(root.Content) == 0 {
+			return []byte("{}\n"), nil
+		}
+		return yaml.Marshal(root)
+
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// typedValue converts a variable's value to a native type based on its annotation.
+func typedValue(v parser.Variable) interface{} {
+	if v.Annotation == nil || v.Value == "" {
+		return v.Value
+	}
+
+	switch v.Annotation.Type {
+	case parser.TypeInt:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n
+		}
+	case parser.TypeNumeric:
+		if f, err := validator.ParseNumber(v.Value); err == nil {
+			return f
+		}
+	case parser.TypeBoolean:
+		switch strings.ToLower(v.Value) {
+		case "true", "yes", "1", "on":
+			return true
+		case "false", "no", "0", "off":
+			return false
+		}
+	}
+
+	return v.Value
+}
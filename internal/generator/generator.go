@@ -3,19 +3,44 @@ package generator
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/theburrowhub/krakenv/internal/config"
+	"github.com/theburrowhub/krakenv/internal/log"
 	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
 )
 
 // Generator handles generation of environment files from distributables.
 type Generator struct {
-	DistFile        *parser.EnvFile
-	TargetPath      string
-	TargetFile      *parser.EnvFile
-	KeepAnnotations bool
+	DistFile          *parser.EnvFile
+	TargetPath        string
+	TargetFile        *parser.EnvFile
+	KeepAnnotations   bool
+	OmitConfig        bool    // Leave the #krakenv: config block out of dotenv output
+	StripComments     bool    // Write only NAME=value lines: no config, comments, blanks or annotations
+	NormalizeBooleans bool    // Write boolean values as true/false, or their output constraint's style, whichever alias was given
+	AnnotationsAbove  bool    // With KeepAnnotations, write each annotation on its own line above the variable
+	Resolve           bool    // Write ${VAR} references as their resolved values
+	Format            string  // Output format: dotenv (default), json or yaml
+	Trace             *Trace  // When set, MergeVariables records its decisions here
+	Backup            string  // Backup mode for an existing target; empty disables backups
+	BackupPath        string  // Set by WriteFile to the backup it made, if any
+	Filter            *Filter // Restricts prompting and writing to selected variables
+	Sort              bool    // Write variables sorted by name
+	PromptAll         bool    // Prompt for every annotated variable, pre-filled with its current value
+	Merge             string  // Merge strategy for existing target values; empty is MergeKeepTarget
+	DroppedComments   int     // Set by Write to the comments left out in Sort mode
+
+	// Dist defaults the merged values were taken from, recorded in the
+	// config block so a later MergePreferDist can spot values left at them
+	defaults map[string]string
 }
 
 // NewGenerator creates a new Generator for the given distributable.
@@ -29,6 +54,7 @@ func NewGenerator(distFile *parser.EnvFile, targetPath string) *Generator {
 // LoadTarget loads an existing target file if it exists.
 func (g *Generator) LoadTarget() error {
 	if _, err := os.Stat(g.TargetPath); os.IsNotExist(err) {
+		log.Debug("target does not exist yet", "path", g.TargetPath)
 		g.TargetFile = nil
 		return nil
 	}
@@ -46,120 +72,578 @@ func (g *Generator) LoadTarget() error {
 // A variable needs prompting if:
 // - It has an annotation (interactive config)
 // - AND has no value in dist AND has no value in target
+// - AND its envdefault environment variable, if any, is unset
+// - AND it is selected by the Filter, if one is set
+//
+// With PromptAll every selected annotated variable is returned, its Value set
+// to the one it would otherwise get so the wizard can offer it.
 func (g *Generator) GetVariablesToPrompt() []parser.Variable {
 	var toPrompt []parser.Variable
 
 	for _, v := range g.DistFile.Variables {
+		if !g.Filter.Includes(v.Name) {
+			log.Debug("variable skipped", "name", v.Name, "reason", "excluded by filter")
+			continue
+		}
 		if v.Annotation == nil {
+			log.Debug("variable skipped", "name", v.Name, "reason", "no annotation")
 			continue // No annotation = no prompting needed
 		}
 
+		if g.PromptAll {
+			v.Value = g.currentValue(v)
+			log.Debug("variable prompted", "name", v.Name, "reason", "prompting for every variable")
+			toPrompt = append(toPrompt, v)
+			continue
+		}
+
 		// Check if dist has a default value
-		if v.Value != "" {
+		if g.distDefault(v) != "" {
+			log.Debug("variable skipped", "name", v.Name, "reason", "has a dist default")
 			continue // Has default value, no prompt needed
 		}
 
+		if envDefault(v) != "" {
+			log.Debug("variable skipped", "name", v.Name, "reason", "set from the host environment")
+			continue // Filled from the host environment
+		}
+
 		// Check if target has a valid value
 		if g.TargetFile != nil {
 			if existing := g.TargetFile.GetVariable(v.Name); existing != nil {
 				if existing.Value != "" {
 					// Has a value - check if valid
 					// For now, assume existing values are valid (validation done separately)
+					log.Debug("variable skipped", "name", v.Name, "reason", "target already has a value")
 					continue
 				}
 			}
 		}
 
 		// No existing valid value - needs prompting
+		log.Debug("variable prompted", "name", v.Name, "reason", "no default or existing value")
 		toPrompt = append(toPrompt, v)
 	}
 
 	return toPrompt
 }
 
+// Merge strategies for values an existing target already has.
+const (
+	MergeKeepTarget  = "keep-target" // Target values win over dist defaults
+	MergePreferDist  = "prefer-dist" // Dist defaults win over target values still at the default they were written from
+	MergeInteractive = "interactive" // The caller asks about each conflict; target values win otherwise
+)
+
+// IsValidMergeStrategy reports whether strategy is a supported merge strategy.
+func IsValidMergeStrategy(strategy string) bool {
+	switch strategy {
+	case MergeKeepTarget, MergePreferDist, MergeInteractive:
+		return true
+	}
+	return false
+}
+
+// MergeConflict is a variable whose target value differs from its dist default.
+type MergeConflict struct {
+	Variable parser.Variable // The distributable's variable
+	Target   string          // Value in the target
+	Dist     string          // Default in the distributable
+}
+
+// Conflicts returns the selected variables whose target value and dist default
+// are both non-empty and differ, in distributable order.
+func (g *Generator) Conflicts() []MergeConflict {
+	var conflicts []MergeConflict
+	for _, v := range g.DistFile.Variables {
+		if !g.Filter.Includes(v.Name) {
+			continue
+		}
+		existing := g.targetVariable(v.Name)
+		if existing == nil || existing.Value == "" {
+			continue
+		}
+		if distValue := g.distDefault(v); distValue != "" && distValue != existing.Value {
+			conflicts = append(conflicts, MergeConflict{Variable: v, Target: existing.Value, Dist: distValue})
+		}
+	}
+	return conflicts
+}
+
 // MergeVariables creates the final list of variables for output.
-// Priority: Target values > User-provided values > Dist defaults.
+// Priority: User-provided values > Target values > envdefault > Dist defaults.
+// With MergePreferDist, a target value still equal to the dist default it was
+// written from (as recorded in the target's defaults= line) is a default, not a
+// local choice, so the current dist default goes ahead of it.
+// Variables excluded by the Filter keep their target value as is, and are left
+// out if the target does not define them.
 func (g *Generator) MergeVariables(userValues map[string]string) []parser.Variable {
-	result := make([]parser.Variable, len(g.DistFile.Variables))
+	result := make([]parser.Variable, 0, len(g.DistFile.Variables))
+	g.defaults = make(map[string]string)
 
-	for i, v := range g.DistFile.Variables {
-		result[i] = v
+	for _, v := range g.DistFile.Variables {
+		if !g.Filter.Includes(v.Name) {
+			if existing := g.targetVariable(v.Name); existing != nil {
+				v.Value = existing.Value
+				v.InlineComment = existing.InlineComment
+				v.IsSet = true
+				result = append(result, v)
+				g.recordDefault(v.Name, existing.Value, false)
+				if g.Trace != nil {
+					source := []TraceSource{{Source: SourceTarget, Value: existing.Value}}
+					g.Trace.record(v, source, SourceTarget)
+				}
+			}
+			continue
+		}
+
+		result = append(result, v)
+		i := len(result) - 1
+
+		// Notes written next to the target's value are kept
+		if existing := g.targetVariable(v.Name); existing != nil && existing.InlineComment != "" {
+			result[i].InlineComment = existing.InlineComment
+		}
+
+		sources := make([]TraceSource, 0, 3)
+		winner := SourceNone
 
 		// Check for user-provided value
 		if userValue, ok := userValues[v.Name]; ok {
-			result[i].Value = userValue
-			result[i].IsSet = true
-			continue
+			sources = append(sources, TraceSource{Source: SourceUser, Value: userValue})
+		}
+
+		// Dist default, for the target's environment if it has its own
+		distValue := g.distDefault(v)
+		var distSource []TraceSource
+		if distValue != "" {
+			distSource = []TraceSource{{Source: SourceDist, Value: distValue}}
+		}
+		preferDist := g.atRecordedDefault(v.Name)
+		if preferDist {
+			sources = append(sources, distSource...)
 		}
 
 		// Check for existing target value
 		if g.TargetFile != nil {
 			if existing := g.TargetFile.GetVariable(v.Name); existing != nil && existing.Value != "" {
-				result[i].Value = existing.Value
-				result[i].IsSet = true
-				continue
+				sources = append(sources, TraceSource{Source: SourceTarget, Value: existing.Value})
 			}
 		}
 
-		// Use dist default (already set)
-		result[i].IsSet = v.Value != ""
+		// Host environment named by envdefault
+		if envValue := envDefault(v); envValue != "" {
+			sources = append(sources, TraceSource{Source: SourceEnv, Value: envValue})
+		}
+
+		if !preferDist {
+			sources = append(sources, distSource...)
+		}
+
+		// First source in priority order wins
+		if len(sources) > 0 {
+			winner = sources[0].Source
+			result[i].Value = sources[0].Value
+		}
+		result[i].IsSet = winner != SourceNone
+		fromDefault := distValue != "" && result[i].Value == distValue
+
+		// Store nocase enum values as the canonical option
+		normalized := validator.NormalizeValue(result[i].Value, v.Annotation)
+		changed := normalized != result[i].Value
+		result[i].Value = normalized
+
+		g.recordDefault(v.Name, normalized, fromDefault)
+
+		if g.Trace != nil {
+			g.Trace.record(result[i], sources, winner)
+			if changed {
+				g.Trace.addTransform(v.Name, "normalize")
+			}
+		}
+	}
+
+	return result
+}
+
+// currentValue returns the value a variable gets without user input: the
+// target's value, then the envdefault environment variable, then the dist
+// default, or the dist default first where MergePreferDist applies.
+func (g *Generator) currentValue(v parser.Variable) string {
+	if distValue := g.distDefault(v); g.atRecordedDefault(v.Name) && distValue != "" {
+		return distValue
+	}
+	if existing := g.targetVariable(v.Name); existing != nil && existing.Value != "" {
+		return existing.Value
+	}
+	if envValue := envDefault(v); envValue != "" {
+		return envValue
+	}
+	return g.distDefault(v)
+}
+
+// recordedDefaults returns the dist defaults recorded in the loaded target.
+func (g *Generator) recordedDefaults() map[string]string {
+	if g.TargetFile == nil || g.TargetFile.Config == nil {
+		return nil
+	}
+	return g.TargetFile.Config.Defaults
+}
+
+// atRecordedDefault reports whether MergePreferDist applies to a variable: the
+// target's value is still the dist default it was written from.
+func (g *Generator) atRecordedDefault(name string) bool {
+	if g.Merge != MergePreferDist {
+		return false
+	}
+	existing := g.targetVariable(name)
+	recorded, ok := g.recordedDefaults()[name]
+	return ok && existing != nil && existing.Value == recorded
+}
+
+// recordDefault records the dist default a merged value was written from:
+// the value itself if it came from the current default, or the default
+// recorded before if the value is still at it.
+func (g *Generator) recordDefault(name, value string, fromDefault bool) {
+	if fromDefault {
+		g.defaults[name] = value
+		return
+	}
+	if recorded, ok := g.recordedDefaults()[name]; ok && value == recorded {
+		g.defaults[name] = recorded
+	}
+}
+
+// distDefault returns the distributable's default for a variable in the target.
+func (g *Generator) distDefault(v parser.Variable) string {
+	return DistDefault(v, g.TargetPath)
+}
+
+// DistDefault returns the distributable's default for a variable in the given
+// target: its default@<env> value for the target's environment, or the line's value.
+func DistDefault(v parser.Variable, targetPath string) string {
+	if v.Annotation != nil {
+		if value, ok := v.Annotation.EnvDefault(EnvironmentName(targetPath)); ok {
+			return value
+		}
+	}
+	return v.Value
+}
+
+// EnvironmentName returns the environment a target file belongs to, taken
+// from a .env.<env> file name, or an empty string for any other name.
+func EnvironmentName(path string) string {
+	env, ok := strings.CutPrefix(filepath.Base(path), ".env.")
+	if !ok {
+		return ""
+	}
+	return env
+}
+
+// targetVariable returns the named variable from the loaded target, if any.
+func (g *Generator) targetVariable(name string) *parser.Variable {
+	if g.TargetFile == nil {
+		return nil
+	}
+	return g.TargetFile.GetVariable(name)
+}
+
+// envDefault returns the value of the host environment variable named by the
+// variable's envdefault constraint, or an empty string if there is none.
+func envDefault(v parser.Variable) string {
+	if v.Annotation == nil {
+		return ""
+	}
+	name := v.Annotation.GetConstraint("envdefault")
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
+
+// ResolveReferences replaces ${VAR} references in the variables' values with the
+// values they point to. It fails on undefined references and cycles.
+func (g *Generator) ResolveReferences(variables []parser.Variable) ([]parser.Variable, error) {
+	values, err := parser.Interpolate(&parser.EnvFile{Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]parser.Variable, len(variables))
+	for i, v := range variables {
+		result[i] = v
+		if resolved := values[v.Name]; resolved != v.Value {
+			result[i].Value = resolved
+			result[i].References = nil
+			if g.Trace != nil {
+				g.Trace.addTransform(v.Name, "interpolate")
+			}
+		}
 	}
 
+	return result, nil
+}
+
+// normalizeBooleans rewrites the values of boolean variables as true or false.
+// Unannotated, non-boolean and invalid values are left as they are.
+func (g *Generator) normalizeBooleans(variables []parser.Variable) []parser.Variable {
+	result := make([]parser.Variable, len(variables))
+	for i, v := range variables {
+		result[i] = v
+		if v.Annotation == nil || v.Annotation.Type != parser.TypeBoolean {
+			continue
+		}
+		style := v.Annotation.GetConstraint("output")
+		if canonical, ok := validator.FormatBoolean(v.Value, style); ok && canonical != v.Value {
+			result[i].Value = canonical
+			if g.Trace != nil {
+				g.Trace.addTransform(v.Name, "normalize")
+			}
+		}
+	}
 	return result
 }
 
 // WriteFile writes the generated environment file to disk.
+// Output is rendered before the target is touched, so a failure leaves any
+// existing file (and backup) untouched.
 func (g *Generator) WriteFile(variables []parser.Variable) error {
-	file, err := os.Create(g.TargetPath)
+	content, err := g.Render(variables)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
+	if g.Backup != "" {
+		backupPath, err := BackupFile(g.TargetPath, g.Backup)
+		if err != nil {
+			return err
+		}
+		g.BackupPath = backupPath
+	}
 
-	// Write config block if present
-	if g.DistFile.Config != nil {
-		for _, line := range formatConfigBlock(g.DistFile.Config) {
+	if err := os.WriteFile(g.TargetPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	log.Info("file written", "path", g.TargetPath, "variables", len(variables), "bytes", len(content))
+	return nil
+}
+
+// Render returns the generated environment file as WriteFile would write it,
+// without touching the target.
+func (g *Generator) Render(variables []parser.Variable) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := g.Write(&buf, variables); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// OutputVariables returns variables with the values Write gives them:
+// references resolved with Resolve and booleans normalized with
+// NormalizeBooleans.
+func (g *Generator) OutputVariables(variables []parser.Variable) ([]parser.Variable, error) {
+	if g.Resolve {
+		resolved, err := g.ResolveReferences(variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve references: %w", err)
+		}
+		variables = resolved
+	}
+
+	if g.NormalizeBooleans {
+		variables = g.normalizeBooleans(variables)
+	}
+	return variables, nil
+}
+
+// Write writes the generated environment file to w.
+func (g *Generator) Write(w io.Writer, variables []parser.Variable) error {
+	variables, err := g.OutputVariables(variables)
+	if err != nil {
+		return err
+	}
+
+	if g.KeepAnnotations && !g.StripComments {
+		variables = detachLikes(variables)
+	}
+
+	if g.Sort {
+		sorted := make([]parser.Variable, len(variables))
+		copy(sorted, variables)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		variables = sorted
+	}
+
+	// Structured formats contain only names and values
+	if g.Format != "" && g.Format != FormatDotenv {
+		data, err := formatOutput(variables, g.Format)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	writer := bufio.NewWriter(w)
+
+	// Write config block if present, with the dist defaults values came from
+	if (g.DistFile.Config != nil || len(g.defaults) > 0) && !g.OmitConfig && !g.StripComments {
+		var configLines []string
+		if g.DistFile.Config != nil {
+			configLines = formatConfigBlock(g.DistFile.Config)
+		}
+		if len(g.defaults) > 0 {
+			configLines = append(configLines, config.FormatConfigLine("defaults", config.FormatDefaults(g.defaults)))
+		}
+		for _, line := range configLines {
 			fmt.Fprintln(writer, line)
 		}
 		fmt.Fprintln(writer)
 	}
 
-	// Track current comment section
-	var lastCommentIdx int
-	for i, comment := range g.DistFile.Comments {
-		if comment.LineNumber > 0 {
-			lastCommentIdx = i
+	var lines []string
+	switch {
+	case g.StripComments:
+		for _, v := range variables {
+			lines = append(lines, g.formatVariableLine(v))
 		}
+	case g.Sort:
+		lines = g.sortedLines(variables)
+	default:
+		lines = g.layoutLines(variables)
+	}
+	for _, line := range lines {
+		fmt.Fprintln(writer, line)
 	}
-	_ = lastCommentIdx // Suppress unused warning for now
 
-	// Write variables with their comments
-	for i, v := range variables {
-		// Write any preceding comments
-		for _, comment := range g.DistFile.Comments {
-			// Find comments that belong before this variable
-			if i > 0 && i < len(g.DistFile.Variables) {
-				prevLine := g.DistFile.Variables[i-1].LineNumber
-				currLine := v.LineNumber
-				if comment.LineNumber > prevLine && comment.LineNumber < currLine {
-					fmt.Fprintf(writer, "# %s\n", comment.Text)
-				}
-			} else if i == 0 && comment.LineNumber < v.LineNumber {
-				fmt.Fprintf(writer, "# %s\n", comment.Text)
+	return writer.Flush()
+}
+
+// layoutLines lays out variables following the distributable's original line
+// structure: standalone comments and blank lines stay where they were and each
+// variable line carries its merged value. Config lines are skipped since the
+// config block is written separately, and variables the distributable has no
+// line for are appended at the end.
+func (g *Generator) layoutLines(variables []parser.Variable) []string {
+	byName := make(map[string]parser.Variable, len(variables))
+	for _, v := range variables {
+		byName[v.Name] = v
+	}
+
+	var lines []string
+	written := make(map[string]bool, len(variables))
+	for _, line := range g.DistFile.Lines {
+		switch line.Kind {
+		case parser.LineBlank:
+			// Leading blanks would double the config block separator
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+		case parser.LineComment:
+			lines = append(lines, strings.TrimRight(line.Text, " \t\r"))
+		case parser.LineVariable:
+			v, ok := byName[line.Name]
+			if !ok || written[line.Name] {
+				continue
 			}
+			written[line.Name] = true
+			lines = append(lines, g.formatVariableLine(v))
 		}
+	}
 
-		// Write variable
-		line := v.Name + "=" + v.Value
-		if g.KeepAnnotations && v.Annotation != nil {
-			line += " " + parser.FormatAnnotation(v.Annotation)
+	for _, v := range variables {
+		if !written[v.Name] {
+			lines = append(lines, g.formatVariableLine(v))
 		}
-		fmt.Fprintln(writer, line)
 	}
 
-	return writer.Flush()
+	// Drop trailing blank lines
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// sortedLines lays out variables, already sorted by name, one per line. A
+// comment block directly above a variable in the distributable moves with it;
+// other comments (such as section headers followed by a blank line) cannot
+// be placed and are counted in DroppedComments.
+func (g *Generator) sortedLines(variables []parser.Variable) []string {
+	attached := make(map[string][]string)
+	var pending []string
+	g.DroppedComments = 0
+
+	for _, line := range g.DistFile.Lines {
+		switch line.Kind {
+		case parser.LineComment:
+			pending = append(pending, strings.TrimRight(line.Text, " \t\r"))
+		case parser.LineVariable:
+			if _, seen := attached[line.Name]; !seen && len(pending) > 0 {
+				attached[line.Name] = pending
+			} else {
+				g.DroppedComments += len(pending)
+			}
+			pending = nil
+		case parser.LineAnnotation:
+			// Part of the next variable; its comments stay pending
+		default:
+			g.DroppedComments += len(pending)
+			pending = nil
+		}
+	}
+	g.DroppedComments += len(pending)
+
+	var lines []string
+	for _, v := range variables {
+		lines = append(lines, attached[v.Name]...)
+		delete(attached, v.Name)
+		lines = append(lines, g.formatVariableLine(v))
+	}
+
+	// Comments of variables that are not written
+	for _, comments := range attached {
+		g.DroppedComments += len(comments)
+	}
+
+	return lines
+}
+
+// detachLikes flattens the annotations whose like:NAME variable is not among
+// variables, such as one left out by the Filter, so the written file still
+// parses on its own.
+func detachLikes(variables []parser.Variable) []parser.Variable {
+	names := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		names[v.Name] = true
+	}
+
+	var detached []parser.Variable
+	for i, v := range variables {
+		if v.Annotation == nil || v.Annotation.Like == "" || names[v.Annotation.Like] {
+			continue
+		}
+		if detached == nil {
+			detached = make([]parser.Variable, len(variables))
+			copy(detached, variables)
+		}
+		detached[i].Annotation = v.Annotation.Flatten()
+	}
+	if detached == nil {
+		return variables
+	}
+	return detached
+}
+
+// formatVariableLine formats a variable as an output line.
+func (g *Generator) formatVariableLine(v parser.Variable) string {
+	if g.StripComments {
+		v.InlineComment = ""
+	}
+	if g.KeepAnnotations && !g.StripComments && g.AnnotationsAbove {
+		return parser.FormatVariableAbove(v)
+	}
+	return parser.FormatVariable(v, g.KeepAnnotations && !g.StripComments)
 }
 
 // formatConfigBlock formats the config as comment lines.
@@ -172,6 +656,10 @@ func formatConfigBlock(config *parser.KrakenvConfig) []string {
 	if config.Strict {
 		lines = append(lines, "#krakenv:strict=true")
 	}
+	// Whitespace kept in values is only read back with the same policy
+	if config.KeepWhitespace {
+		lines = append(lines, "#krakenv:trim=false")
+	}
 
 	return lines
 }
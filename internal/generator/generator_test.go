@@ -1,12 +1,16 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/theburrowhub/krakenv/internal/parser"
 )
@@ -83,6 +87,50 @@ func TestGenerator_GetVariablesToPrompt_WithExistingTarget(t *testing.T) {
 	assert.Equal(t, "VAR_B", toPrompt[0].Name)
 }
 
+func TestGenerator_GetVariablesToPrompt_PromptAll(t *testing.T) {
+	t.Setenv("KRAKENV_TEST_REGION", "eu-west-1")
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{
+				Name:       "VAR_A",
+				Value:      "dist_default",
+				Annotation: &parser.Annotation{PromptText: "A?", Type: parser.TypeString},
+			},
+			{
+				Name:       "VAR_B",
+				Value:      "dist_default",
+				Annotation: &parser.Annotation{PromptText: "B?", Type: parser.TypeString},
+			},
+			{
+				Name:  "VAR_C",
+				Value: "",
+				Annotation: &parser.Annotation{
+					PromptText:  "C?",
+					Type:        parser.TypeString,
+					Constraints: []parser.Constraint{{Name: "envdefault", Value: "KRAKENV_TEST_REGION"}},
+				},
+			},
+			{Name: "NO_ANNOTATION", Value: "value"},
+		},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "VAR_A", Value: "existing_value"},
+		},
+	}
+	gen.PromptAll = true
+
+	toPrompt := gen.GetVariablesToPrompt()
+
+	require.Len(t, toPrompt, 3)
+	assert.Equal(t, "existing_value", toPrompt[0].Value)
+	assert.Equal(t, "dist_default", toPrompt[1].Value)
+	assert.Equal(t, "eu-west-1", toPrompt[2].Value)
+	assert.Equal(t, "dist_default", distFile.Variables[0].Value, "dist must not be modified")
+}
+
 func TestGenerator_MergeVariables(t *testing.T) {
 	distFile := &parser.EnvFile{
 		Variables: []parser.Variable{
@@ -113,6 +161,277 @@ func TestGenerator_MergeVariables(t *testing.T) {
 	assert.Equal(t, "user_value", result[2].Value)   // VAR_C: from user
 }
 
+func TestGenerator_MergeVariables_Merge(t *testing.T) {
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "CONFLICT", Value: "dist"},
+			{Name: "SAME", Value: "same"},
+			{Name: "NO_DEFAULT", Value: ""},
+			{Name: "NOT_IN_TARGET", Value: "dist"},
+			{Name: "FROM_USER", Value: "dist"},
+		},
+	}
+	target := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "CONFLICT", Value: "target"},
+			{Name: "SAME", Value: "same"},
+			{Name: "NO_DEFAULT", Value: "target"},
+			{Name: "FROM_USER", Value: "target"},
+		},
+	}
+	userValues := map[string]string{"FROM_USER": "user"}
+
+	tests := []struct {
+		merge string
+		want  []string
+	}{
+		{"", []string{"target", "same", "target", "dist", "user"}},
+		{MergeKeepTarget, []string{"target", "same", "target", "dist", "user"}},
+		{MergeInteractive, []string{"target", "same", "target", "dist", "user"}},
+		{MergePreferDist, []string{"target", "same", "target", "dist", "user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.merge, func(t *testing.T) {
+			gen := NewGenerator(distFile, ".env.local")
+			gen.TargetFile = target
+			gen.Merge = tt.merge
+
+			var got []string
+			for _, v := range gen.MergeVariables(userValues) {
+				got = append(got, v.Value)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = target
+	conflicts := gen.Conflicts()
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "CONFLICT", conflicts[0].Variable.Name)
+	assert.Equal(t, "target", conflicts[0].Target)
+	assert.Equal(t, "dist", conflicts[0].Dist)
+	assert.Equal(t, "FROM_USER", conflicts[1].Variable.Name)
+}
+
+func TestGenerator_MergeVariables_PreferDist(t *testing.T) {
+	// The defaults of PORT and HOST changed since the target was generated.
+	// PORT is still at the default it was written from, HOST was overridden
+	// locally, and API_URL has no default.
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "PORT", Value: "9090"},
+			{Name: "HOST", Value: "db.internal"},
+			{Name: "API_URL", Value: ""},
+		},
+	}
+	target := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "PORT", Value: "8080"},
+			{Name: "HOST", Value: "localhost"},
+			{Name: "API_URL", Value: "http://localhost:4000"},
+		},
+		Config: &parser.KrakenvConfig{Defaults: map[string]string{"PORT": "8080", "HOST": "db.old"}},
+	}
+
+	tests := []struct {
+		merge    string
+		port     string
+		defaults string
+	}{
+		{MergeKeepTarget, "8080", "#krakenv:defaults=PORT=8080\n"},
+		{MergePreferDist, "9090", "#krakenv:defaults=PORT=9090\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.merge, func(t *testing.T) {
+			gen := NewGenerator(distFile, ".env.local")
+			gen.TargetFile = target
+			gen.Merge = tt.merge
+
+			result := gen.MergeVariables(nil)
+			require.Len(t, result, 3)
+			assert.Equal(t, tt.port, result[0].Value)
+			assert.Equal(t, "localhost", result[1].Value, "local override survives")
+			assert.Equal(t, "http://localhost:4000", result[2].Value, "value without a dist default survives")
+
+			content, err := gen.Render(result)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(content), tt.defaults), string(content))
+		})
+	}
+}
+
+func TestGenerator_RecordsDefaults(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent("HOST=localhost\nURL=http://x?a=1&b=2\nNAME=\n", ".env.dist")
+	require.NoError(t, err)
+	gen := NewGenerator(distFile, ".env.local")
+
+	content, err := gen.Render(gen.MergeVariables(map[string]string{"NAME": "app"}))
+	require.NoError(t, err)
+
+	// Written back, the recorded defaults are read as the target's config
+	target, err := parser.ParseEnvFileContent(string(content), ".env.local")
+	require.NoError(t, err)
+	require.NotNil(t, target.Config)
+	assert.Equal(t, map[string]string{"HOST": "localhost", "URL": "http://x?a=1&b=2"}, target.Config.Defaults)
+
+	// Nothing is recorded without a config block
+	gen.StripComments = true
+	content, err = gen.Render(gen.MergeVariables(nil))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "#krakenv:")
+}
+
+func TestGenerator_MergeVariables_InlineComment(t *testing.T) {
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "PORT", Value: "8080", InlineComment: "from dist"},
+			{Name: "HOST", Value: "localhost", InlineComment: "from dist"},
+		},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "PORT", Value: "9090", InlineComment: "legacy, remove after v2"},
+			{Name: "HOST", Value: "example.com"},
+		},
+	}
+
+	result := gen.MergeVariables(nil)
+	require.Len(t, result, 2)
+	assert.Equal(t, "legacy, remove after v2", result[0].InlineComment)
+	assert.Equal(t, "from dist", result[1].InlineComment)
+
+	var buf bytes.Buffer
+	gen.OmitConfig = true
+	require.NoError(t, gen.Write(&buf, result))
+	assert.Contains(t, buf.String(), "PORT=9090 # legacy, remove after v2\n")
+
+	buf.Reset()
+	gen.StripComments = true
+	require.NoError(t, gen.Write(&buf, result))
+	assert.Contains(t, buf.String(), "PORT=9090\n")
+}
+
+func TestGenerator_MergeVariables_EnvSpecificDefault(t *testing.T) {
+	ann := &parser.Annotation{
+		PromptText:  "Level?",
+		Type:        parser.TypeString,
+		EnvDefaults: map[string]string{"production": "warn", "local": "debug"},
+	}
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "LOG_LEVEL", Value: "info", Annotation: ann},
+		},
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{".env.production", "warn"},
+		{"config/.env.local", "debug"},
+		{".env.staging", "info"},
+		{"custom.env", "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			gen := NewGenerator(distFile, tt.target)
+			result := gen.MergeVariables(nil)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.want, result[0].Value)
+		})
+	}
+}
+
+func TestGenerator_GetVariablesToPrompt_EnvSpecificDefault(t *testing.T) {
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "LOG_LEVEL", Annotation: &parser.Annotation{
+				PromptText:  "Level?",
+				Type:        parser.TypeString,
+				EnvDefaults: map[string]string{"production": "warn"},
+			}},
+		},
+	}
+
+	assert.Empty(t, NewGenerator(distFile, ".env.production").GetVariablesToPrompt())
+	assert.Len(t, NewGenerator(distFile, ".env.local").GetVariablesToPrompt(), 1)
+}
+
+func TestEnvironmentName(t *testing.T) {
+	assert.Equal(t, "production", EnvironmentName(".env.production"))
+	assert.Equal(t, "local", EnvironmentName("config/.env.local"))
+	assert.Equal(t, "", EnvironmentName(".env"))
+	assert.Equal(t, "", EnvironmentName("production.env"))
+}
+
+func TestGenerator_MergeVariables_EnvDefault(t *testing.T) {
+	t.Setenv("KRAKENV_TEST_TOKEN", "from-env")
+
+	envAnn := func() *parser.Annotation {
+		return &parser.Annotation{
+			PromptText:  "Token?",
+			Type:        parser.TypeString,
+			Constraints: []parser.Constraint{{Name: "envdefault", Value: "KRAKENV_TEST_TOKEN"}},
+		}
+	}
+
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "FROM_ENV", Annotation: envAnn()},
+			{Name: "OVER_DIST", Value: "dist_default", Annotation: envAnn()},
+			{Name: "FROM_TARGET", Annotation: envAnn()},
+			{Name: "FROM_USER", Annotation: envAnn()},
+			{Name: "UNSET_ENV", Annotation: &parser.Annotation{
+				PromptText:  "Other?",
+				Type:        parser.TypeString,
+				Constraints: []parser.Constraint{{Name: "envdefault", Value: "KRAKENV_TEST_UNSET"}},
+			}},
+		},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = &parser.EnvFile{
+		Variables: []parser.Variable{{Name: "FROM_TARGET", Value: "target_value"}},
+	}
+
+	// Only the variable whose environment variable is unset needs prompting
+	toPrompt := gen.GetVariablesToPrompt()
+	require.Len(t, toPrompt, 1)
+	assert.Equal(t, "UNSET_ENV", toPrompt[0].Name)
+
+	result := gen.MergeVariables(map[string]string{"FROM_USER": "user_value"})
+
+	assert.Equal(t, "from-env", result[0].Value)
+	assert.True(t, result[0].IsSet)
+	assert.Equal(t, "from-env", result[1].Value)
+	assert.Equal(t, "target_value", result[2].Value)
+	assert.Equal(t, "user_value", result[3].Value)
+	assert.Empty(t, result[4].Value)
+	assert.False(t, result[4].IsSet)
+}
+
+func TestGenerator_MergeVariables_NormalizesNoCaseEnum(t *testing.T) {
+	ann, err := parser.ParseAnnotation("#prompt:Level?|enum;options:debug,info;nocase")
+	require.NoError(t, err)
+
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{{Name: "LOG_LEVEL", Annotation: ann}},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.Trace = NewTrace(".env.local")
+	result := gen.MergeVariables(map[string]string{"LOG_LEVEL": "INFO"})
+
+	assert.Equal(t, "info", result[0].Value)
+	assert.Equal(t, []string{"normalize"}, gen.Trace.Variables[0].Transforms)
+}
+
 func TestGenerator_WriteFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	targetPath := filepath.Join(tmpDir, ".env.local")
@@ -137,6 +456,33 @@ func TestGenerator_WriteFile(t *testing.T) {
 	assert.Contains(t, string(content), "DB_PORT=5432")
 }
 
+func TestGenerator_Render(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetPath := filepath.Join(tmpDir, ".env.local")
+
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "DB_HOST", Value: "localhost"},
+			{Name: "DB_PORT", Value: "5432"},
+		},
+	}
+
+	gen := NewGenerator(distFile, targetPath)
+	gen.Format = FormatJSON
+
+	rendered, err := gen.Render(distFile.Variables)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"DB_HOST": "localhost", "DB_PORT": "5432"}`, string(rendered))
+
+	_, err = os.Stat(targetPath)
+	assert.True(t, os.IsNotExist(err), "Render must not write the target")
+
+	require.NoError(t, gen.WriteFile(distFile.Variables))
+	written, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+	assert.Equal(t, rendered, written)
+}
+
 func TestGenerator_WriteFile_WithConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	targetPath := filepath.Join(tmpDir, ".env.local")
@@ -189,6 +535,38 @@ func TestGenerator_WriteFile_KeepAnnotations(t *testing.T) {
 	assert.Contains(t, string(content), "#prompt:Port?|int")
 }
 
+func TestGenerator_Write_KeepAnnotationsLike(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent(`DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535
+API_PORT=8080 #prompt:API port?|like:DB_PORT;max:9000
+`, ".env.dist")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   string
+	}{
+		{"reference kept", nil, "API_PORT=8080 #prompt:API port?|like:DB_PORT;max:9000\n"},
+		{"reference left out", NewFilter([]string{"API_PORT"}, nil), "API_PORT=8080 #prompt:API port?|int;min:1;max:9000\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGenerator(distFile, ".env.local")
+			gen.KeepAnnotations = true
+			gen.Filter = tt.filter
+
+			rendered, err := gen.Render(gen.MergeVariables(nil))
+			require.NoError(t, err)
+			assert.Contains(t, string(rendered), tt.want)
+
+			// The written file parses on its own
+			_, err = parser.ParseEnvFileContent(string(rendered), ".env.local")
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestGenerator_WriteFile_StripAnnotations(t *testing.T) {
 	tmpDir := t.TempDir()
 	targetPath := filepath.Join(tmpDir, ".env.local")
@@ -216,6 +594,204 @@ func TestGenerator_WriteFile_StripAnnotations(t *testing.T) {
 	assert.Contains(t, string(content), "PORT=8080")
 }
 
+func TestGenerator_WriteFile_PreservesLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetPath := filepath.Join(tmpDir, ".env.local")
+
+	distContent := `#krakenv:environments=local
+#krakenv:strict=true
+
+# Database
+DB_HOST=localhost #prompt:Host?|string
+DB_PORT=5432
+
+# Cache
+# Redis connection
+REDIS_URL= #prompt:Redis?|string
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, targetPath)
+	variables := gen.MergeVariables(map[string]string{"REDIS_URL": "redis://cache"})
+
+	require.NoError(t, gen.WriteFile(variables))
+
+	content, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+
+	expected := `#krakenv:environments=local
+#krakenv:strict=true
+#krakenv:defaults=DB_HOST=localhost&DB_PORT=5432
+
+# Database
+DB_HOST=localhost
+DB_PORT=5432
+
+# Cache
+# Redis connection
+REDIS_URL=redis://cache
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestGenerator_Write_Sort(t *testing.T) {
+	distContent := `#krakenv:environments=local
+
+# Database
+
+DB_PORT=5432
+
+# Redis connection
+REDIS_URL=redis://cache
+# Primary host
+DB_HOST=localhost
+API_KEY=secret
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.Sort = true
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+
+	expected := `#krakenv:environments=local
+#krakenv:defaults=API_KEY=secret&DB_HOST=localhost&DB_PORT=5432&REDIS_URL=redis%3A%2F%2Fcache
+
+API_KEY=secret
+# Primary host
+DB_HOST=localhost
+DB_PORT=5432
+# Redis connection
+REDIS_URL=redis://cache
+`
+	assert.Equal(t, expected, buf.String())
+	assert.Equal(t, 1, gen.DroppedComments, "the # Database header is followed by a blank line")
+}
+
+func TestGenerator_Write_StripComments(t *testing.T) {
+	distContent := `#krakenv:environments=local,prod
+#krakenv:strict=true
+
+# Database
+DB_HOST=localhost #prompt:Host?|string
+
+# Cache
+REDIS_URL=redis://cache #prompt:Redis?|url
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.StripComments = true
+	gen.KeepAnnotations = true
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+
+	assert.Equal(t, "DB_HOST=localhost\nREDIS_URL=redis://cache\n", buf.String())
+	for _, line := range strings.Split(buf.String(), "\n") {
+		assert.NotContains(t, line, "#")
+	}
+}
+
+func TestGenerator_Write_NormalizeBooleans(t *testing.T) {
+	distContent := `DEBUG=on #prompt:Debug?|boolean
+CACHE=0 #prompt:Cache?|boolean
+MODE=on #prompt:Mode?|string
+PLAIN=yes
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.NormalizeBooleans = true
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+
+	output := buf.String()
+	assert.Contains(t, output, "DEBUG=true\n")
+	assert.Contains(t, output, "CACHE=false\n")
+	assert.Contains(t, output, "MODE=on\n")
+	assert.Contains(t, output, "PLAIN=yes\n")
+}
+
+func TestGenerator_OutputVariables(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent("DEBUG=on #prompt:Debug?|boolean\nHOST=db\nURL=http://${HOST}\n", ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.NormalizeBooleans = true
+	gen.Resolve = true
+
+	variables := gen.MergeVariables(nil)
+	output, err := gen.OutputVariables(variables)
+	require.NoError(t, err)
+
+	values := make(map[string]string)
+	for _, v := range output {
+		values[v.Name] = v.Value
+	}
+	assert.Equal(t, map[string]string{"DEBUG": "true", "HOST": "db", "URL": "http://db"}, values)
+	assert.Equal(t, "on", variables[0].Value, "the merged variables are left as they were")
+}
+
+func TestGenerator_Write_NormalizeBooleans_Output(t *testing.T) {
+	distContent := `TRUE_FALSE=yes #prompt:A?|boolean;output:truefalse
+YES_NO=1 #prompt:B?|boolean;output:yesno
+ONE_ZERO=off #prompt:C?|boolean;output:10
+ALREADY=0 #prompt:D?|boolean;output:10
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.NormalizeBooleans = true
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+
+	output := buf.String()
+	assert.Contains(t, output, "TRUE_FALSE=true\n")
+	assert.Contains(t, output, "YES_NO=yes\n")
+	assert.Contains(t, output, "ONE_ZERO=0\n")
+	assert.Contains(t, output, "ALREADY=0\n")
+
+	// Without normalization the values are written as given
+	buf.Reset()
+	gen.NormalizeBooleans = false
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+	assert.Contains(t, buf.String(), "ONE_ZERO=off\n")
+}
+
+func TestGenerator_Write_AnnotationsAbove(t *testing.T) {
+	distContent := `# Server
+#prompt:Port?|int;min:1
+PORT=8080
+HOST=localhost #prompt:Host?|string
+`
+	distFile, err := parser.ParseEnvFileContent(distContent, ".env.dist")
+	require.NoError(t, err)
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.KeepAnnotations = true
+	gen.AnnotationsAbove = true
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+	defaults := "#krakenv:defaults=HOST=localhost&PORT=8080\n\n"
+	assert.Equal(t, defaults+"# Server\n#prompt:Port?|int;min:1\nPORT=8080\n#prompt:Host?|string\nHOST=localhost\n", buf.String())
+
+	// Annotation lines are never copied without KeepAnnotations
+	gen.KeepAnnotations = false
+	buf.Reset()
+	require.NoError(t, gen.Write(&buf, gen.MergeVariables(nil)))
+	assert.Equal(t, defaults+"# Server\nPORT=8080\nHOST=localhost\n", buf.String())
+}
+
 func TestGenerate_Integration(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -282,3 +858,223 @@ DB_PORT= #prompt:Port?|int
 	assert.Contains(t, string(content), "DB_HOST=existing_host") // Preserved
 	assert.Contains(t, string(content), "DB_PORT=3306")          // New value
 }
+
+func TestGenerator_MergeVariables_Trace(t *testing.T) {
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "FROM_USER", Value: "dist", Annotation: &parser.Annotation{Type: parser.TypeString}},
+			{Name: "FROM_TARGET", Value: "8080", Annotation: &parser.Annotation{Type: parser.TypeInt}},
+			{Name: "SECRET", Value: "", Annotation: &parser.Annotation{Type: parser.TypeString, IsSecret: true}},
+			{Name: "EMPTY", Value: "", Annotation: &parser.Annotation{Type: parser.TypeInt}},
+		},
+	}
+	targetFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "FROM_TARGET", Value: "3000"},
+		},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = targetFile
+	gen.Trace = NewTrace(".env.local")
+
+	gen.MergeVariables(map[string]string{
+		"FROM_USER": "typed",
+		"SECRET":    "hunter2",
+	})
+
+	entries := gen.Trace.Variables
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, SourceUser, entries[0].Winner)
+	assert.Equal(t, "typed", entries[0].Value)
+	assert.Len(t, entries[0].Sources, 2)
+
+	assert.Equal(t, SourceTarget, entries[1].Winner)
+	assert.Equal(t, "3000", entries[1].Value)
+	assert.True(t, entries[1].Valid)
+
+	assert.Equal(t, SourceUser, entries[2].Winner)
+	assert.Equal(t, "****", entries[2].Value)
+
+	assert.Equal(t, SourceNone, entries[3].Winner)
+	assert.False(t, entries[3].Valid)
+	assert.NotEmpty(t, entries[3].Error)
+}
+
+func TestGenerator_WriteFile_Resolve(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetPath := filepath.Join(tmpDir, ".env.local")
+
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "DB_HOST", Value: "localhost", LineNumber: 1},
+			{Name: "DATABASE_URL", Value: "postgres://${DB_HOST}/app", LineNumber: 2},
+		},
+	}
+
+	gen := NewGenerator(distFile, targetPath)
+	gen.Resolve = true
+	variables := gen.MergeVariables(nil)
+	require.NoError(t, gen.WriteFile(variables))
+
+	content, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "DATABASE_URL=postgres://localhost/app")
+
+	// Without Resolve, references are written as-is
+	gen.Resolve = false
+	require.NoError(t, gen.WriteFile(variables))
+	content, err = os.ReadFile(targetPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "DATABASE_URL=postgres://${DB_HOST}/app")
+}
+
+func TestFormatOutput(t *testing.T) {
+	variables := []parser.Variable{
+		{Name: "PORT", Value: "8080", Annotation: &parser.Annotation{Type: parser.TypeInt}},
+		{Name: "RATE", Value: "0.5", Annotation: &parser.Annotation{Type: parser.TypeNumeric}},
+		{Name: "DEBUG", Value: "on", Annotation: &parser.Annotation{Type: parser.TypeBoolean}},
+		{Name: "BAD_INT", Value: "abc", Annotation: &parser.Annotation{Type: parser.TypeInt}},
+		{Name: "NOT_FINITE", Value: "NaN", Annotation: &parser.Annotation{Type: parser.TypeNumeric}},
+		{Name: "HOST", Value: "localhost"},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := formatOutput(variables, FormatJSON)
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, float64(8080), decoded["PORT"])
+		assert.Equal(t, 0.5, decoded["RATE"])
+		assert.Equal(t, true, decoded["DEBUG"])
+		assert.Equal(t, "abc", decoded["BAD_INT"])
+		assert.Equal(t, "NaN", decoded["NOT_FINITE"])
+		assert.Equal(t, "localhost", decoded["HOST"])
+
+		// Order follows the variables
+		assert.Less(t, strings.Index(string(data), "PORT"), strings.Index(string(data), "HOST"))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := formatOutput(variables, FormatYAML)
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		assert.Equal(t, 8080, decoded["PORT"])
+		assert.Equal(t, true, decoded["DEBUG"])
+		assert.Equal(t, "localhost", decoded["HOST"])
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := formatOutput(variables, "toml")
+		assert.Error(t, err)
+	})
+}
+
+func TestBackupFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".env.local")
+
+	// No backup for a missing file
+	backupPath, err := BackupFile(path, BackupSimple)
+	require.NoError(t, err)
+	assert.Empty(t, backupPath)
+
+	require.NoError(t, os.WriteFile(path, []byte("OLD=1\n"), 0600))
+
+	backupPath, err = BackupFile(path, BackupSimple)
+	require.NoError(t, err)
+	assert.Equal(t, path+".bak", backupPath)
+	content, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	assert.Equal(t, "OLD=1\n", string(content))
+
+	backupPath, err = BackupFile(path, BackupTimestamp)
+	require.NoError(t, err)
+	assert.Regexp(t, `\.env\.local\.\d{8}-\d{6}\.bak$`, backupPath)
+}
+
+func TestGenerator_WriteFile_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetPath := filepath.Join(tmpDir, ".env.local")
+	require.NoError(t, os.WriteFile(targetPath, []byte("VAR=old\n"), 0644))
+
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{{Name: "VAR", Value: "new"}},
+	}
+
+	gen := NewGenerator(distFile, targetPath)
+	gen.Backup = BackupSimple
+	require.NoError(t, gen.WriteFile(distFile.Variables))
+
+	assert.Equal(t, targetPath+".bak", gen.BackupPath)
+	backup, err := os.ReadFile(gen.BackupPath)
+	require.NoError(t, err)
+	assert.Equal(t, "VAR=old\n", string(backup))
+
+	content, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+	assert.Equal(t, "VAR=new\n", string(content))
+}
+
+func TestFilter(t *testing.T) {
+	var none *Filter
+	assert.Nil(t, NewFilter(nil, nil))
+	assert.True(t, none.Includes("ANY"))
+
+	only := NewFilter([]string{"DB_HOST", " DB_PORT"}, nil)
+	assert.True(t, only.Includes("DB_PORT"))
+	assert.False(t, only.Includes("API_KEY"))
+
+	except := NewFilter(nil, []string{"API_KEY"})
+	assert.True(t, except.Includes("DB_PORT"))
+	assert.False(t, except.Includes("API_KEY"))
+
+	both := NewFilter([]string{"DB_HOST", "DB_PORT"}, []string{"DB_PORT"})
+	assert.True(t, both.Includes("DB_HOST"))
+	assert.False(t, both.Includes("DB_PORT"))
+
+	distFile := &parser.EnvFile{Variables: []parser.Variable{{Name: "DB_HOST"}}}
+	assert.Equal(t, []string{"DB_PORT", "TYPO"}, NewFilter([]string{"DB_HOST", "DB_PORT"}, []string{"TYPO"}).Unmatched(distFile))
+}
+
+func TestGenerator_Filter(t *testing.T) {
+	ann := &parser.Annotation{PromptText: "Value?", Type: parser.TypeString}
+	distFile := &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "DB_HOST", Annotation: ann},
+			{Name: "DB_PORT", Annotation: ann},
+			{Name: "API_KEY", Annotation: ann},
+			{Name: "LOG_LEVEL", Value: "info"},
+		},
+	}
+
+	gen := NewGenerator(distFile, ".env.local")
+	gen.TargetFile = &parser.EnvFile{
+		Variables: []parser.Variable{
+			{Name: "API_KEY", Value: "existing"},
+			{Name: "DB_PORT", Value: "5432"},
+		},
+	}
+	gen.Filter = NewFilter([]string{"DB_HOST", "DB_PORT"}, nil)
+
+	toPrompt := gen.GetVariablesToPrompt()
+	require.Len(t, toPrompt, 1)
+	assert.Equal(t, "DB_HOST", toPrompt[0].Name)
+
+	merged := gen.MergeVariables(map[string]string{"DB_HOST": "db"})
+	values := make(map[string]string)
+	for _, v := range merged {
+		values[v.Name] = v.Value
+	}
+
+	// Unselected variables keep their target value or are left out
+	assert.Equal(t, map[string]string{
+		"DB_HOST": "db",
+		"DB_PORT": "5432",
+		"API_KEY": "existing",
+	}, values)
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+// Trace source names, in the order they are considered.
+const (
+	SourceUser   = "user"   // Value entered in the wizard or supplied by the caller
+	SourceTarget = "target" // Value already present in the target file
+	SourceEnv    = "env"    // Host environment variable named by envdefault
+	SourceDist   = "dist"   // Default value from the distributable
+	SourceNone   = "none"   // No source provided a value
+)
+
+// traceSecretMask replaces secret values in trace output.
+const traceSecretMask = "****"
+
+// TraceFormatVersion is the version of the trace file format.
+const TraceFormatVersion = 1
+
+// TraceFile is the document written by `generate --trace`:
+//
+//	{
+//	  "version": 1,
+//	  "targets": [
+//	    {
+//	      "target": ".env.local",
+//	      "variables": [
+//	        {
+//	          "name": "PORT",
+//	          "sources": [
+//	            {"source": "target", "value": "3000"},
+//	            {"source": "dist", "value": "8080"}
+//	          ],
+//	          "winner": "target",
+//	          "value": "3000",
+//	          "valid": true
+//	        }
+//	      ]
+//	    }
+//	  ]
+//	}
+type TraceFile struct {
+	Version int      `json:"version"`
+	Targets []*Trace `json:"targets"`
+}
+
+// Trace records how each variable's final value was decided for one target.
+type Trace struct {
+	Target    string       `json:"target"`
+	Variables []TraceEntry `json:"variables"`
+}
+
+// TraceEntry is the decision log for a single variable.
+type TraceEntry struct {
+	Name       string        `json:"name"`
+	Sources    []TraceSource `json:"sources"`
+	Winner     string        `json:"winner"`
+	Value      string        `json:"value"`
+	Valid      bool          `json:"valid"`
+	Error      string        `json:"error,omitempty"`
+	Transforms []string      `json:"transforms,omitempty"`
+}
+
+// TraceSource is a candidate value considered for a variable.
+type TraceSource struct {
+	Source string `json:"source"`
+	Value  string `json:"value"`
+}
+
+// NewTrace creates an empty trace for a target file.
+func NewTrace(target string) *Trace {
+	return &Trace{
+		Target:    target,
+		Variables: make([]TraceEntry, 0),
+	}
+}
+
+// record appends the decision for a merged variable. Secret values are masked.
+func (t *Trace) record(v parser.Variable, sources []TraceSource, winner string) {
+	entry := TraceEntry{
+		Name:    v.Name,
+		Sources: sources,
+		Winner:  winner,
+		Value:   v.Value,
+		Valid:   true,
+	}
+
+	if v.Annotation != nil {
+		if err := validator.ValidateValue(v.Value, v.Annotation); err != nil {
+			entry.Valid = false
+			entry.Error = err.Error()
+		}
+		if v.Annotation.IsSecret {
+			entry.Value = maskTraceValue(entry.Value)
+			for i := range entry.Sources {
+				entry.Sources[i].Value = maskTraceValue(entry.Sources[i].Value)
+			}
+		}
+	}
+
+	t.Variables = append(t.Variables, entry)
+}
+
+// addTransform notes a transformation applied to a recorded variable's value.
+func (t *Trace) addTransform(name, transform string) {
+	for i := range t.Variables {
+		if t.Variables[i].Name == name {
+			t.Variables[i].Transforms = append(t.Variables[i].Transforms, transform)
+			return
+		}
+	}
+}
+
+func maskTraceValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return traceSecretMask
+}
+
+// WriteTraceFile writes the traces of one or more targets as JSON to path.
+func WriteTraceFile(path string, traces []*Trace) error {
+	doc := TraceFile{
+		Version: TraceFormatVersion,
+		Targets: traces,
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trace: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/tui/components"
+)
+
+// DiffResult holds the differences between two environment files.
+type DiffResult struct {
+	PathA          string            // First file path
+	PathB          string            // Second file path
+	OnlyInA        []parser.Variable // Variables only present in A
+	OnlyInB        []parser.Variable // Variables only present in B
+	Changed        []ValueChange     // Variables present in both with different values
+	IdenticalCount int               // Count of variables with identical values
+	Secrets        map[string]bool   // Variables whose values are masked in reports
+	ShowSecrets    bool              // Print secret values instead of masking them
+}
+
+// ValueChange describes a variable whose value differs between two files.
+type ValueChange struct {
+	Name   string
+	ValueA string
+	ValueB string
+}
+
+// Diff compares two environment files by variable name and value.
+// Annotations are not considered.
+func Diff(a, b *parser.EnvFile) *DiffResult {
+	result := &DiffResult{
+		PathA:   a.Path,
+		PathB:   b.Path,
+		OnlyInA: make([]parser.Variable, 0),
+		OnlyInB: make([]parser.Variable, 0),
+		Changed: make([]ValueChange, 0),
+	}
+
+	for _, va := range a.Variables {
+		vb := b.GetVariable(va.Name)
+		if vb == nil {
+			result.OnlyInA = append(result.OnlyInA, va)
+			continue
+		}
+		if va.Value != vb.Value {
+			result.Changed = append(result.Changed, ValueChange{
+				Name:   va.Name,
+				ValueA: va.Value,
+				ValueB: vb.Value,
+			})
+			continue
+		}
+		result.IdenticalCount++
+	}
+
+	for _, vb := range b.Variables {
+		if !a.HasVariable(vb.Name) {
+			result.OnlyInB = append(result.OnlyInB, vb)
+		}
+	}
+
+	return result
+}
+
+// HasDifferences returns true if the files differ.
+func (r *DiffResult) HasDifferences() bool {
+	return len(r.OnlyInA) > 0 || len(r.OnlyInB) > 0 || len(r.Changed) > 0
+}
+
+// FormatReport returns a formatted text report.
+func (r *DiffResult) FormatReport() string {
+	var b strings.Builder
+	redact := Redactor{Secrets: r.Secrets, Show: r.ShowSecrets}
+
+	b.WriteString(fmt.Sprintf("DIFF REPORT: %s vs %s\n\n", r.PathA, r.PathB))
+
+	if len(r.OnlyInA) > 0 {
+		b.WriteString(components.WarningStyle.Render(fmt.Sprintf("ONLY IN %s (%d):", r.PathA, len(r.OnlyInA))))
+		b.WriteString("\n")
+		for _, v := range r.OnlyInA {
+			b.WriteString(fmt.Sprintf("  %-20s %q\n", v.Name, redact.Value(v.Name, v.Value)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.OnlyInB) > 0 {
+		b.WriteString(components.InfoStyle.Render(fmt.Sprintf("ONLY IN %s (%d):", r.PathB, len(r.OnlyInB))))
+		b.WriteString("\n")
+		for _, v := range r.OnlyInB {
+			b.WriteString(fmt.Sprintf("  %-20s %q\n", v.Name, redact.Value(v.Name, v.Value)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Changed) > 0 {
+		b.WriteString(components.ErrorStyle.Render(fmt.Sprintf("DIFFERENT VALUES (%d):", len(r.Changed))))
+		b.WriteString("\n")
+		for _, c := range r.Changed {
+			b.WriteString(fmt.Sprintf("  %-20s %q → %q\n", c.Name, redact.Value(c.Name, c.ValueA), redact.Value(c.Name, c.ValueB)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(fmt.Sprintf("Summary: %d only in %s, %d only in %s, %d different, %d identical\n",
+		len(r.OnlyInA), r.PathA, len(r.OnlyInB), r.PathB, len(r.Changed), r.IdenticalCount))
+
+	return b.String()
+}
+
+// JSONDiffReport represents the JSON output format of a diff.
+type JSONDiffReport struct {
+	OnlyInA []JSONVariable    `json:"only_in_a"`
+	OnlyInB []JSONVariable    `json:"only_in_b"`
+	Changed []JSONValueChange `json:"changed"`
+}
+
+// JSONValueChange represents a changed value in JSON output.
+type JSONValueChange struct {
+	Name   string `json:"name"`
+	ValueA string `json:"a"`
+	ValueB string `json:"b"`
+}
+
+// FormatJSON returns a JSON formatted report.
+func (r *DiffResult) FormatJSON() (string, error) {
+	redact := Redactor{Secrets: r.Secrets, Show: r.ShowSecrets}
+	report := JSONDiffReport{
+		OnlyInA: make([]JSONVariable, 0, len(r.OnlyInA)),
+		OnlyInB: make([]JSONVariable, 0, len(r.OnlyInB)),
+		Changed: make([]JSONValueChange, 0, len(r.Changed)),
+	}
+
+	for _, v := range r.OnlyInA {
+		report.OnlyInA = append(report.OnlyInA, JSONVariable{Name: v.Name, Value: redact.Value(v.Name, v.Value)})
+	}
+
+	for _, v := range r.OnlyInB {
+		report.OnlyInB = append(report.OnlyInB, JSONVariable{Name: v.Name, Value: redact.Value(v.Name, v.Value)})
+	}
+
+	for _, c := range r.Changed {
+		report.Changed = append(report.Changed, JSONValueChange{
+			Name:   c.Name,
+			ValueA: redact.Value(c.Name, c.ValueA),
+			ValueB: redact.Value(c.Name, c.ValueB),
+		})
+	}
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,88 @@
+package inspector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestDiff(t *testing.T) {
+	a, err := parser.ParseEnvFileContent("HOST=localhost\nPORT=8080\nDEBUG=true\nAPI_KEY=old\n", ".env.a")
+	require.NoError(t, err)
+	b, err := parser.ParseEnvFileContent("HOST=localhost\nPORT=9090\nAPI_KEY=new\nLOG_LEVEL=info\n", ".env.b")
+	require.NoError(t, err)
+
+	result := Diff(a, b)
+
+	assert.True(t, result.HasDifferences())
+	assert.Equal(t, ".env.a", result.PathA)
+	assert.Equal(t, ".env.b", result.PathB)
+
+	require.Len(t, result.OnlyInA, 1)
+	assert.Equal(t, "DEBUG", result.OnlyInA[0].Name)
+	assert.Equal(t, "true", result.OnlyInA[0].Value)
+
+	require.Len(t, result.OnlyInB, 1)
+	assert.Equal(t, "LOG_LEVEL", result.OnlyInB[0].Name)
+	assert.Equal(t, "info", result.OnlyInB[0].Value)
+
+	assert.Equal(t, []ValueChange{
+		{Name: "PORT", ValueA: "8080", ValueB: "9090"},
+		{Name: "API_KEY", ValueA: "old", ValueB: "new"},
+	}, result.Changed)
+	assert.Equal(t, 1, result.IdenticalCount)
+}
+
+func TestDiff_Identical(t *testing.T) {
+	// Annotations are not compared
+	a, err := parser.ParseEnvFileContent("HOST=localhost #prompt:Host?|string\nPORT=8080\n", ".env.a")
+	require.NoError(t, err)
+	b, err := parser.ParseEnvFileContent("PORT=8080\nHOST=localhost\n", ".env.b")
+	require.NoError(t, err)
+
+	result := Diff(a, b)
+
+	assert.False(t, result.HasDifferences())
+	assert.Empty(t, result.OnlyInA)
+	assert.Empty(t, result.OnlyInB)
+	assert.Empty(t, result.Changed)
+	assert.Equal(t, 2, result.IdenticalCount)
+}
+
+func TestDiffResult_FormatJSON(t *testing.T) {
+	a, err := parser.ParseEnvFileContent("DEBUG=true\nAPI_KEY=old\n", ".env.a")
+	require.NoError(t, err)
+	b, err := parser.ParseEnvFileContent("API_KEY=new\nLOG_LEVEL=info\n", ".env.b")
+	require.NoError(t, err)
+
+	result := Diff(a, b)
+	result.Secrets = map[string]bool{"API_KEY": true}
+
+	out, err := result.FormatJSON()
+	require.NoError(t, err)
+
+	var report JSONDiffReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, []JSONVariable{{Name: "DEBUG", Value: "true"}}, report.OnlyInA)
+	assert.Equal(t, []JSONVariable{{Name: "LOG_LEVEL", Value: "info"}}, report.OnlyInB)
+	assert.Equal(t, []JSONValueChange{{Name: "API_KEY", ValueA: SecretMask, ValueB: SecretMask}}, report.Changed)
+}
+
+func TestDiffResult_FormatReport(t *testing.T) {
+	a, err := parser.ParseEnvFileContent("DEBUG=true\nPORT=8080\nHOST=x\n", ".env.a")
+	require.NoError(t, err)
+	b, err := parser.ParseEnvFileContent("PORT=9090\nLOG_LEVEL=info\nHOST=x\n", ".env.b")
+	require.NoError(t, err)
+
+	report := Diff(a, b).FormatReport()
+
+	assert.Contains(t, report, "ONLY IN .env.a (1):")
+	assert.Contains(t, report, "ONLY IN .env.b (1):")
+	assert.Contains(t, report, "DIFFERENT VALUES (1):")
+	assert.Contains(t, report, `"8080" → "9090"`)
+	assert.Contains(t, report, "Summary: 1 only in .env.a, 1 only in .env.b, 1 different, 1 identical")
+}
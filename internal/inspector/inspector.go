@@ -4,8 +4,12 @@ package inspector
 import (
 	"encoding/json"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+
 	"github.com/theburrowhub/krakenv/internal/parser"
 	"github.com/theburrowhub/krakenv/internal/tui/components"
 	"github.com/theburrowhub/krakenv/internal/validator"
@@ -18,7 +22,10 @@ type InspectionResult struct {
 	MissingInEnv  []parser.Variable           // Variables in dist but not in target
 	ExtraInEnv    []parser.Variable           // Variables in target but not in dist
 	InvalidValues []validator.ValidationError // Variables with invalid values
+	CurrentValues map[string]string           // Target values of InvalidValues, by variable name
 	ValidCount    int                         // Count of valid variables
+	Secrets       map[string]bool             // Variables annotated secret, masked in reports
+	ShowSecrets   bool                        // Print secret values instead of masking them
 }
 
 // Inspect compares a target file against the distributable.
@@ -29,6 +36,8 @@ func Inspect(distFile, targetFile *parser.EnvFile) *InspectionResult {
 		MissingInEnv:  make([]parser.Variable, 0),
 		ExtraInEnv:    make([]parser.Variable, 0),
 		InvalidValues: make([]validator.ValidationError, 0),
+		CurrentValues: make(map[string]string),
+		Secrets:       SecretNames(distFile),
 	}
 
 	// Track variable names in dist
@@ -54,9 +63,10 @@ func Inspect(distFile, targetFile *parser.EnvFile) *InspectionResult {
 					Variable:   distVar.Name,
 					LineNumber: targetVar.LineNumber,
 					Message:    err.Error(),
-					Suggestion: validator.GetSuggestion(distVar.Annotation),
+					Suggestion: validator.GetErrorSuggestion(err, distVar.Annotation),
 					Example:    validator.GetExample(distVar.Annotation),
 				})
+				result.CurrentValues[distVar.Name] = targetVar.Value
 				continue
 			}
 		}
@@ -64,9 +74,13 @@ func Inspect(distFile, targetFile *parser.EnvFile) *InspectionResult {
 		result.ValidCount++
 	}
 
-	// Find extra variables in target
+	// Find extra variables in target, except those the dist config ignores
+	var ignore []string
+	if distFile.Config != nil {
+		ignore = distFile.Config.Ignore
+	}
 	for _, targetVar := range targetFile.Variables {
-		if !distVarNames[targetVar.Name] {
+		if !distVarNames[targetVar.Name] && !matchesAny(targetVar.Name, ignore) {
 			result.ExtraInEnv = append(result.ExtraInEnv, targetVar)
 		}
 	}
@@ -74,20 +88,109 @@ func Inspect(distFile, targetFile *parser.EnvFile) *InspectionResult {
 	return result
 }
 
+// IgnoreExtras drops extra variables whose names match one of the glob
+// patterns, in path.Match syntax such as KUBERNETES_*. Returns an error for
+// a malformed pattern, leaving the result unchanged.
+func (r *InspectionResult) IgnoreExtras(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+
+	kept := r.ExtraInEnv[:0]
+	for _, v := range r.ExtraInEnv {
+		if !matchesAny(v.Name, patterns) {
+			kept = append(kept, v)
+		}
+	}
+	r.ExtraInEnv = kept
+	return nil
+}
+
+// matchesAny reports whether name matches one of the glob patterns.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // HasDiscrepancies returns true if there are any discrepancies.
 func (r *InspectionResult) HasDiscrepancies() bool {
 	return len(r.MissingInEnv) > 0 || len(r.ExtraInEnv) > 0 || len(r.InvalidValues) > 0
 }
 
+// IsValidKind reports whether kind names a kind of discrepancy: missing,
+// extra or invalid.
+func IsValidKind(kind string) bool {
+	switch kind {
+	case "missing", "extra", "invalid":
+		return true
+	default:
+		return false
+	}
+}
+
+// Count returns the number of discrepancies of one kind: missing, extra or
+// invalid.
+func (r *InspectionResult) Count(kind string) (int, error) {
+	switch kind {
+	case "missing":
+		return len(r.MissingInEnv), nil
+	case "extra":
+		return len(r.ExtraInEnv), nil
+	case "invalid":
+		return len(r.InvalidValues), nil
+	default:
+		return 0, fmt.Errorf("invalid discrepancy kind %q (use: missing, extra, invalid)", kind)
+	}
+}
+
+// FormatCount returns discrepancy counts as "missing=N extra=N invalid=N",
+// or just the number for a single kind (missing, extra or invalid).
+// An empty kind or "all" returns all three counts.
+func (r *InspectionResult) FormatCount(kind string) (string, error) {
+	if kind == "" || kind == "all" {
+		return fmt.Sprintf("missing=%d extra=%d invalid=%d",
+			len(r.MissingInEnv), len(r.ExtraInEnv), len(r.InvalidValues)), nil
+	}
+	n, err := r.Count(kind)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(n), nil
+}
+
 // FormatReport returns a formatted text report.
 func (r *InspectionResult) FormatReport() string {
+	return r.formatReport(true)
+}
+
+// FormatPlainReport returns the text report without styling, for logs and CI.
+func (r *InspectionResult) FormatPlainReport() string {
+	return r.formatReport(false)
+}
+
+func (r *InspectionResult) formatReport(color bool) string {
 	var b strings.Builder
+	redact := Redactor{Secrets: r.Secrets, Show: r.ShowSecrets}
+
+	heading := func(style lipgloss.Style, text string) {
+		if color {
+			text = style.Render(text)
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
 
 	b.WriteString(fmt.Sprintf("INSPECTION REPORT: %s vs %s\n\n", r.TargetPath, r.DistPath))
 
 	// Missing variables
 	if len(r.MissingInEnv) > 0 {
-		b.WriteString(components.WarningStyle.Render(fmt.Sprintf("MISSING IN %s (%d):\n", r.TargetPath, len(r.MissingInEnv))))
+		heading(components.WarningStyle, fmt.Sprintf("MISSING IN %s (%d):", r.TargetPath, len(r.MissingInEnv)))
 		for _, v := range r.MissingInEnv {
 			desc := ""
 			typeStr := ""
@@ -95,14 +198,18 @@ func (r *InspectionResult) FormatReport() string {
 				desc = v.Annotation.PromptText
 				typeStr = fmt.Sprintf("[%s]", v.Annotation.Type.String())
 			}
-			b.WriteString(fmt.Sprintf("  %-20s %q %s\n", v.Name, desc, typeStr))
+			line := fmt.Sprintf("  %-20s %q %s", v.Name, desc, typeStr)
+			if v.Value != "" {
+				line += fmt.Sprintf(" default=%q", redact.Value(v.Name, v.Value))
+			}
+			b.WriteString(line + "\n")
 		}
 		b.WriteString("\n")
 	}
 
 	// Extra variables
 	if len(r.ExtraInEnv) > 0 {
-		b.WriteString(components.InfoStyle.Render(fmt.Sprintf("EXTRA IN %s (%d):\n", r.TargetPath, len(r.ExtraInEnv))))
+		heading(components.InfoStyle, fmt.Sprintf("EXTRA IN %s (%d):", r.TargetPath, len(r.ExtraInEnv)))
 		for _, v := range r.ExtraInEnv {
 			b.WriteString(fmt.Sprintf("  %-20s (not in distributable)\n", v.Name))
 		}
@@ -111,9 +218,11 @@ func (r *InspectionResult) FormatReport() string {
 
 	// Invalid values
 	if len(r.InvalidValues) > 0 {
-		b.WriteString(components.ErrorStyle.Render(fmt.Sprintf("INVALID VALUES (%d):\n", len(r.InvalidValues))))
+		heading(components.ErrorStyle, fmt.Sprintf("INVALID VALUES (%d):", len(r.InvalidValues)))
 		for _, err := range r.InvalidValues {
-			b.WriteString(fmt.Sprintf("  %-20s %s\n", err.Variable, err.Message))
+			current := r.CurrentValues[err.Variable]
+			b.WriteString(fmt.Sprintf("  %-20s current=%q: %s\n", err.Variable,
+				redact.Value(err.Variable, current), redact.Text(err.Variable, current, err.Message)))
 		}
 		b.WriteString("\n")
 	}
@@ -134,10 +243,11 @@ type JSONReport struct {
 
 // JSONVariable represents a variable in JSON output.
 type JSONVariable struct {
-	Name   string `json:"name"`
-	Prompt string `json:"prompt,omitempty"`
-	Type   string `json:"type,omitempty"`
-	Value  string `json:"value,omitempty"`
+	Name        string `json:"name"`
+	Prompt      string `json:"prompt,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Value       string `json:"value,omitempty"`
 }
 
 // JSONValidationError represents a validation error in JSON output.
@@ -149,6 +259,7 @@ type JSONValidationError struct {
 
 // FormatJSON returns a JSON formatted report.
 func (r *InspectionResult) FormatJSON() (string, error) {
+	redact := Redactor{Secrets: r.Secrets, Show: r.ShowSecrets}
 	report := JSONReport{
 		Missing: make([]JSONVariable, 0, len(r.MissingInEnv)),
 		Extra:   make([]JSONVariable, 0, len(r.ExtraInEnv)),
@@ -156,9 +267,10 @@ func (r *InspectionResult) FormatJSON() (string, error) {
 	}
 
 	for _, v := range r.MissingInEnv {
-		jv := JSONVariable{Name: v.Name}
+		jv := JSONVariable{Name: v.Name, Value: redact.Value(v.Name, v.Value)}
 		if v.Annotation != nil {
 			jv.Prompt = v.Annotation.PromptText
+			jv.Description = v.Annotation.Description
 			jv.Type = v.Annotation.Type.String()
 		}
 		report.Missing = append(report.Missing, jv)
@@ -167,14 +279,16 @@ func (r *InspectionResult) FormatJSON() (string, error) {
 	for _, v := range r.ExtraInEnv {
 		report.Extra = append(report.Extra, JSONVariable{
 			Name:  v.Name,
-			Value: v.Value,
+			Value: redact.Value(v.Name, v.Value),
 		})
 	}
 
 	for _, err := range r.InvalidValues {
+		current := r.CurrentValues[err.Variable]
 		report.Invalid = append(report.Invalid, JSONValidationError{
 			Name:  err.Variable,
-			Error: err.Message,
+			Value: redact.Value(err.Variable, current),
+			Error: redact.Text(err.Variable, current, err.Message),
 		})
 	}
 
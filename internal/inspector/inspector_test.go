@@ -0,0 +1,30 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestInspect_EncodingSuggestion(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent(`KEY= #prompt:Key?|string;encoding:base64
+TOKEN= #prompt:Token?|string;encoding:hex
+PORT= #prompt:Port?|int
+`, ".env.dist")
+	require.NoError(t, err)
+	targetFile, err := parser.ParseEnvFileContent("KEY=not base64!\nTOKEN=xyz\nPORT=abc\n", ".env.local")
+	require.NoError(t, err)
+
+	result := Inspect(distFile, targetFile)
+
+	require.Len(t, result.InvalidValues, 3)
+	assert.Equal(t, "KEY", result.InvalidValues[0].Variable)
+	assert.Contains(t, result.InvalidValues[0].Message, "invalid base64 encoding")
+	assert.Contains(t, result.InvalidValues[0].Suggestion, "Re-encode the value as standard base64")
+	assert.Contains(t, result.InvalidValues[1].Suggestion, "Re-encode the value as hex")
+	assert.NotContains(t, result.InvalidValues[2].Suggestion, "Re-encode")
+	assert.Equal(t, "not base64!", result.CurrentValues["KEY"])
+}
@@ -0,0 +1,50 @@
+package inspector
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// SecretMask replaces the values of secret variables in reports.
+const SecretMask = "****"
+
+// SecretNames returns the names of the variables annotated secret in distFile.
+func SecretNames(distFile *parser.EnvFile) map[string]bool {
+	secrets := make(map[string]bool)
+	for _, v := range distFile.Variables {
+		if v.Annotation != nil && v.Annotation.IsSecret {
+			secrets[v.Name] = true
+		}
+	}
+	return secrets
+}
+
+// Redactor masks the values of secret variables in reports unless Show is set.
+type Redactor struct {
+	Secrets map[string]bool // Names of the secret variables, as from SecretNames
+	Show    bool            // Print secret values as they are
+}
+
+// Value returns the value to print for a variable. Empty values are kept,
+// since they reveal nothing.
+func (r Redactor) Value(name, value string) string {
+	if r.Show || value == "" || !r.Secrets[name] {
+		return value
+	}
+	return SecretMask
+}
+
+// Text masks every occurrence of a secret variable's value in text, such as
+// a validation message quoting the value, written as is or escaped by %q.
+func (r Redactor) Text(name, value, text string) string {
+	if r.Show || value == "" || !r.Secrets[name] {
+		return text
+	}
+	text = strings.ReplaceAll(text, value, SecretMask)
+	if quoted := strconv.Quote(value); quoted[1:len(quoted)-1] != value {
+		text = strings.ReplaceAll(text, quoted[1:len(quoted)-1], SecretMask)
+	}
+	return text
+}
@@ -0,0 +1,54 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestSecretNames(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent("API_KEY= #prompt:Key?|string;secret\nHOST=localhost #prompt:Host?|string\nPLAIN=x\n", ".env.dist")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"API_KEY": true}, SecretNames(distFile))
+}
+
+func TestRedactor_Value(t *testing.T) {
+	r := Redactor{Secrets: map[string]bool{"API_KEY": true}}
+
+	assert.Equal(t, SecretMask, r.Value("API_KEY", "s3cr3t"))
+	assert.Equal(t, "", r.Value("API_KEY", ""), "empty values reveal nothing")
+	assert.Equal(t, "localhost", r.Value("HOST", "localhost"))
+
+	r.Show = true
+	assert.Equal(t, "s3cr3t", r.Value("API_KEY", "s3cr3t"))
+}
+
+func TestRedactor_Text(t *testing.T) {
+	r := Redactor{Secrets: map[string]bool{"API_KEY": true}}
+
+	tests := []struct {
+		name  string
+		value string
+		text  string
+		want  string
+	}{
+		{"quoted", "s3cr3t", `Expected int, got "s3cr3t"`, `Expected int, got "****"`},
+		{"every occurrence", "ab", "ab is not ab", "**** is not ****"},
+		{"escaped by %q", `p"w\d`, `got "p\"w\\d"`, `got "****"`},
+		{"empty value", "", "Required variable has no value", "Required variable has no value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Text("API_KEY", tt.value, tt.text))
+		})
+	}
+
+	assert.Equal(t, `got "localhost"`, r.Text("HOST", "localhost", `got "localhost"`))
+	r.Show = true
+	assert.Equal(t, `got "s3cr3t"`, r.Text("API_KEY", "s3cr3t", `got "s3cr3t"`))
+}
@@ -0,0 +1,278 @@
+// Package lint provides checks for common authoring problems in distributable files.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+// Issue represents a single problem found in a distributable.
+type Issue struct {
+	Rule       string // Short rule identifier (e.g., "duplicate-prompt")
+	Variable   string // Variable the issue refers to (may be empty)
+	LineNumber int    // Line number in source file (1-indexed)
+	Message    string // User-friendly problem description
+}
+
+// Format returns a formatted issue line for terminal output.
+func (i Issue) Format() string {
+	if i.Variable == "" {
+		return fmt.Sprintf("  Line %d: ⚠ %s [%s]\n", i.LineNumber, i.Message, i.Rule)
+	}
+	return fmt.Sprintf("  Line %d: %s\n    ⚠ %s [%s]\n", i.LineNumber, i.Variable, i.Message, i.Rule)
+}
+
+// Lint runs all checks against a parsed distributable and returns the issues found,
+// ordered by line number.
+func Lint(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+
+	issues = append(issues, checkParseWarnings(envFile)...)
+	issues = append(issues, checkDuplicatePrompts(envFile)...)
+	issues = append(issues, checkSingleOptionEnums(envFile)...)
+	issues = append(issues, checkSecretDefaults(envFile)...)
+	issues = append(issues, checkPatterns(envFile)...)
+	issues = append(issues, checkBooleanOutputs(envFile)...)
+	issues = append(issues, checkEnvDefaults(envFile)...)
+	issues = append(issues, checkDateBounds(envFile)...)
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].LineNumber < issues[b].LineNumber
+	})
+
+	return issues
+}
+
+// checkParseWarnings reports annotation problems the parser ignored, such as
+// malformed annotations and unknown constraints.
+func checkParseWarnings(envFile *parser.EnvFile) []Issue {
+	issues := make([]Issue, 0, len(envFile.Warnings))
+	for _, w := range envFile.Warnings {
+		issues = append(issues, Issue{
+			Rule:       w.Code,
+			Variable:   w.Variable,
+			LineNumber: w.LineNumber,
+			Message:    w.Message,
+		})
+	}
+	return issues
+}
+
+// checkSingleOptionEnums flags enums that offer a single option, which leaves
+// nothing to choose.
+func checkSingleOptionEnums(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || v.Annotation.Type != parser.TypeEnum {
+			continue
+		}
+		if options := strings.Split(v.Annotation.GetConstraint("options"), ","); len(options) == 1 {
+			issues = append(issues, Issue{
+				Rule:       "single-option-enum",
+				Variable:   v.Name,
+				LineNumber: v.LineNumber,
+				Message:    fmt.Sprintf("enum has only one option (%s)", strings.TrimSpace(options[0])),
+			})
+		}
+	}
+	return issues
+}
+
+// checkSecretDefaults flags secrets with a default value, since the
+// distributable is committed and the default would leak.
+func checkSecretDefaults(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation != nil && v.Annotation.IsSecret && v.Value != "" {
+			issues = append(issues, Issue{
+				Rule:       "secret-default",
+				Variable:   v.Name,
+				LineNumber: v.LineNumber,
+				Message:    "secret has a default value in the distributable",
+			})
+		}
+	}
+	return issues
+}
+
+// checkPatterns flags pattern constraints that are not valid regular expressions.
+func checkPatterns(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || !v.Annotation.HasConstraint("pattern") {
+			continue
+		}
+		if _, err := regexp.Compile(v.Annotation.GetConstraint("pattern")); err != nil {
+			issues = append(issues, Issue{
+				Rule:       "invalid-pattern",
+				Variable:   v.Name,
+				LineNumber: v.LineNumber,
+				Message:    fmt.Sprintf("pattern does not compile: %v", err),
+			})
+		}
+	}
+	return issues
+}
+
+// checkBooleanOutputs flags output constraints on non-boolean variables,
+// where they have no effect, and output styles that do not exist.
+func checkBooleanOutputs(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || !v.Annotation.HasConstraint("output") {
+			continue
+		}
+
+		var message string
+		style := v.Annotation.GetConstraint("output")
+		switch {
+		case v.Annotation.Type != parser.TypeBoolean:
+			message = fmt.Sprintf("output applies only to boolean variables, not %s", v.Annotation.Type)
+		case !validator.IsValidBooleanOutput(style):
+			message = fmt.Sprintf("unknown boolean output %q (use: truefalse, yesno, 10)", style)
+		default:
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:       "invalid-output",
+			Variable:   v.Name,
+			LineNumber: v.LineNumber,
+			Message:    message,
+		})
+	}
+	return issues
+}
+
+// checkEnvDefaults flags default@<env> values that the variable's own type
+// and constraints would reject.
+func checkEnvDefaults(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || len(v.Annotation.EnvDefaults) == 0 {
+			continue
+		}
+		envs := make([]string, 0, len(v.Annotation.EnvDefaults))
+		for env := range v.Annotation.EnvDefaults {
+			envs = append(envs, env)
+		}
+		sort.Strings(envs)
+
+		for _, env := range envs {
+			if err := validator.ValidateValue(v.Annotation.EnvDefaults[env], v.Annotation); err != nil {
+				issues = append(issues, Issue{
+					Rule:       "invalid-env-default",
+					Variable:   v.Name,
+					LineNumber: v.LineNumber,
+					Message:    fmt.Sprintf("default@%s is invalid: %v", env, err),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// checkDateBounds flags after and before bounds that do not parse with the
+// date's layout, which would make every value fail validation.
+func checkDateBounds(envFile *parser.EnvFile) []Issue {
+	var issues []Issue
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || v.Annotation.Type != parser.TypeDate {
+			continue
+		}
+		layout := validator.DateLayout(v.Annotation)
+		for _, bound := range []string{"after", "before"} {
+			value := v.Annotation.GetConstraint(bound)
+			if value == "" {
+				continue
+			}
+			if _, err := time.Parse(layout, value); err != nil {
+				issues = append(issues, Issue{
+					Rule:       "invalid-date-bound",
+					Variable:   v.Name,
+					LineNumber: v.LineNumber,
+					Message:    fmt.Sprintf("%s bound %q does not parse with layout %s", bound, value, layout),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// checkDuplicatePrompts flags variables sharing the same prompt text, which usually
+// means a line was copy-pasted without updating its annotation.
+func checkDuplicatePrompts(envFile *parser.EnvFile) []Issue {
+	byPrompt := make(map[string][]parser.Variable)
+	var order []string
+
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil || isGeneratedPrompt(v.Name, v.Annotation.PromptText) {
+			continue
+		}
+		prompt := v.Annotation.PromptText
+		if _, seen := byPrompt[prompt]; !seen {
+			order = append(order, prompt)
+		}
+		byPrompt[prompt] = append(byPrompt[prompt], v)
+	}
+
+	var issues []Issue
+	for _, prompt := range order {
+		vars := byPrompt[prompt]
+		if len(vars) < 2 {
+			continue
+		}
+
+		names := make([]string, len(vars))
+		for i, v := range vars {
+			names[i] = v.Name
+		}
+
+		msg := fmt.Sprintf("prompt %q is shared by %s", prompt, strings.Join(names, ", "))
+		if sameConstraints(vars) {
+			msg += " (with identical constraints)"
+		}
+
+		// Report on the copies, not the first occurrence
+		for _, v := range vars[1:] {
+			issues = append(issues, Issue{
+				Rule:       "duplicate-prompt",
+				Variable:   v.Name,
+				LineNumber: v.LineNumber,
+				Message:    msg,
+			})
+		}
+	}
+
+	return issues
+}
+
+// isGeneratedPrompt reports whether a prompt is one of the placeholders krakenv
+// writes when no prompt is given (e.g., "Enter string value" or "Enter DB_HOST").
+func isGeneratedPrompt(name, prompt string) bool {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" || prompt == "Enter "+name {
+		return true
+	}
+	if strings.HasPrefix(prompt, "Enter ") && strings.HasSuffix(prompt, " value") {
+		typeName := strings.TrimSuffix(strings.TrimPrefix(prompt, "Enter "), " value")
+		return parser.ParseVariableType(typeName).String() == typeName
+	}
+	return false
+}
+
+// sameConstraints reports whether all variables share the same type and constraints.
+func sameConstraints(vars []parser.Variable) bool {
+	first := parser.FormatAnnotation(vars[0].Annotation)
+	for _, v := range vars[1:] {
+		if parser.FormatAnnotation(v.Annotation) != first {
+			return false
+		}
+	}
+	return true
+}
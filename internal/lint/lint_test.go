@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestLint_DuplicatePrompts(t *testing.T) {
+	input := `DB_HOST=localhost #prompt:Database host?|string
+DB_PORT=5432 #prompt:Database port?|int;min:1
+REDIS_HOST=localhost #prompt:Database host?|string
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "duplicate-prompt", issues[0].Rule)
+	assert.Equal(t, "REDIS_HOST", issues[0].Variable)
+	assert.Equal(t, 3, issues[0].LineNumber)
+	assert.Contains(t, issues[0].Message, "DB_HOST, REDIS_HOST")
+	assert.Contains(t, issues[0].Message, "identical constraints")
+}
+
+func TestLint_DuplicatePrompts_DifferentConstraints(t *testing.T) {
+	input := `A_PORT= #prompt:Port?|int;min:1
+B_PORT= #prompt:Port?|int;min:1024
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+	require.Len(t, issues, 1)
+	assert.NotContains(t, issues[0].Message, "identical constraints")
+}
+
+func TestLint_IgnoresGeneratedPrompts(t *testing.T) {
+	input := `VAR_A= #prompt:Enter string value|string
+VAR_B= #prompt:Enter string value|string
+VAR_C= #prompt:Enter VAR_C|string
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	assert.Empty(t, Lint(envFile))
+}
+
+func TestLint_BooleanOutput(t *testing.T) {
+	input := `DEBUG=false #prompt:Debug?|boolean;output:10
+VERBOSE=no #prompt:Verbose?|boolean;output:onoff
+PORT=8080 #prompt:Port?|int;output:10
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "invalid-output", issues[0].Rule)
+	assert.Equal(t, "VERBOSE", issues[0].Variable)
+	assert.Contains(t, issues[0].Message, `"onoff"`)
+	assert.Equal(t, "PORT", issues[1].Variable)
+	assert.Contains(t, issues[1].Message, "only to boolean")
+}
+
+func TestLint_AnnotationProblems(t *testing.T) {
+	input := `BROKEN= #prompt:No type separator
+TYPO= #prompt:Value?|int;mn:1;optinal
+MODE=dev #prompt:Mode?|enum;options:dev
+API_KEY=abc123 #prompt:API key?|string;secret
+CODE= #prompt:Code?|string;pattern:[a-z
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+
+	var rules []string
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{
+		"invalid-annotation",
+		"unknown-constraint",
+		"unknown-modifier",
+		"single-option-enum",
+		"secret-default",
+		"invalid-pattern",
+	}, rules)
+
+	assert.Equal(t, 2, issues[1].LineNumber)
+	assert.Equal(t, "TYPO", issues[1].Variable)
+	assert.Contains(t, issues[1].Message, `"mn"`)
+}
+
+func TestLint_EnvDefaults(t *testing.T) {
+	input := `LOG_LEVEL=info #prompt:Level?|enum;options:debug,info,warn;default@production:warn;default@local:trace
+PORT=8080 #prompt:Port?|int;max:9000;default@staging:9999;default@production:80
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "invalid-env-default", issues[0].Rule)
+	assert.Equal(t, "LOG_LEVEL", issues[0].Variable)
+	assert.Contains(t, issues[0].Message, "default@local")
+	assert.Equal(t, "PORT", issues[1].Variable)
+	assert.Contains(t, issues[1].Message, "default@staging")
+}
+
+func TestLint_DateBounds(t *testing.T) {
+	input := `START= #prompt:Start?|date;after:2024-01-01;before:2024-12-31
+EU_START= #prompt:EU start?|date;layout:02/01/2006;after:2024-01-01;before:31/12/2024
+`
+	envFile, err := parser.ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	issues := Lint(envFile)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "invalid-date-bound", issues[0].Rule)
+	assert.Equal(t, "EU_START", issues[0].Variable)
+	assert.Contains(t, issues[0].Message, "after bound")
+}
@@ -0,0 +1,45 @@
+// Package log provides the leveled diagnostic logger behind --verbose.
+//
+// Entries are written to stderr as logfmt lines without timestamps. Until
+// Setup is called, and unless --verbose is set, nothing is written; problems
+// users need to see are printed by the commands themselves.
+package log
+
+import (
+	"io"
+	"log/slog"
+)
+
+// logger is replaced by Setup; it discards everything until then.
+var logger = slog.New(slog.DiscardHandler)
+
+// Setup directs log entries to w when verbose is set and quiet is not, and
+// discards them otherwise.
+func Setup(w io.Writer, verbose, quiet bool) {
+	if !verbose || quiet {
+		logger = slog.New(slog.DiscardHandler)
+		return
+	}
+
+	logger = slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Timestamps only add noise to a single command run
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// Debug logs a step that explains what a command is doing, such as a file
+// parsed or a variable skipped, as alternating key-value args.
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// Info logs a notable event, such as a file written.
+func Info(msg string, args ...any) {
+	logger.Info(msg, args...)
+}
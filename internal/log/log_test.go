@@ -0,0 +1,47 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		verbose bool
+		quiet   bool
+		want    string
+	}{
+		{
+			name: "default",
+			want: "",
+		},
+		{
+			name:    "verbose",
+			verbose: true,
+			want:    "level=DEBUG msg=debug key=value\nlevel=INFO msg=info\n",
+		},
+		{
+			name:    "quiet wins over verbose",
+			verbose: true,
+			quiet:   true,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			previous := logger
+			t.Cleanup(func() { logger = previous })
+			Setup(&buf, tt.verbose, tt.quiet)
+
+			Debug("debug", "key", "value")
+			Info("info")
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
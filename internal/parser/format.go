@@ -0,0 +1,121 @@
+package parser
+
+import "strings"
+
+// FormatEnvFile renders a parsed file in canonical form:
+//   - config lines first, followed by a blank line
+//   - NAME=value with no spaces around '=' and one space before #prompt:
+//   - inline comments written as " # note"
+//   - annotation segments without surrounding spaces
+//   - no trailing whitespace, runs of blank lines collapsed to one
+//
+// Comments, variable order, heredoc bodies and lines the parser ignored are
+// preserved. The result ends with a single newline.
+func FormatEnvFile(f *EnvFile) string {
+	var out []string
+
+	for _, line := range f.Lines {
+		if line.Kind == LineConfig {
+			out = append(out, strings.TrimSpace(line.Text))
+		}
+	}
+	configEnd := len(out)
+
+	for _, line := range f.Lines {
+		switch line.Kind {
+		case LineBlank:
+			// Skip leading blanks and collapse runs
+			if len(out) > configEnd && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+		case LineComment, LineUnparsed:
+			out = append(out, strings.TrimSpace(line.Text))
+		case LineAnnotation:
+			out = append(out, formatAnnotationText(line.Text))
+		case LineVariable:
+			out = append(out, formatVariableText(line.Text))
+		}
+	}
+
+	for len(out) > configEnd && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	// Separate the config block from the rest of the file
+	if configEnd > 0 && len(out) > configEnd {
+		out = append(out[:configEnd], append([]string{""}, out[configEnd:]...)...)
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// FormatLine builds a variable line in the canonical form of FormatEnvFile:
+// NAME=value, quoted if needed, and one space before the annotation. An empty
+// value still gets the space, since "NAME=#prompt:" would read the annotation
+// as the value.
+func FormatLine(name, value, annotation string) string {
+	line := name + "=" + QuoteValue(value)
+	if annotation != "" {
+		line += " " + formatAnnotationText(annotation)
+	}
+	return line
+}
+
+// formatVariableText canonicalizes the first line of a variable's raw text,
+// leaving any heredoc body untouched.
+func formatVariableText(text string) string {
+	first, body, hasBody := strings.Cut(text, "\n")
+
+	first = strings.TrimSpace(first)
+	eqIdx := strings.Index(first, "=")
+	if eqIdx == -1 {
+		return text
+	}
+
+	name := strings.TrimSpace(first[:eqIdx])
+	rest := first[eqIdx+1:]
+
+	annotation := ""
+	if annotationIdx := annotationIndex(rest); annotationIdx != -1 {
+		annotation = formatAnnotationText(rest[annotationIdx+1:])
+		rest = rest[:annotationIdx]
+	}
+
+	rest, comment := splitInlineComment(rest)
+
+	line := name + "=" + strings.TrimSpace(rest)
+	if comment != "" {
+		line += " # " + comment
+	}
+	if annotation != "" {
+		line += " " + annotation
+	}
+	if hasBody {
+		line += "\n" + body
+	}
+	return line
+}
+
+// formatAnnotationText normalizes spacing inside a raw annotation without
+// interpreting it, so unknown or malformed constraints are kept.
+func formatAnnotationText(annotation string) string {
+	annotation = strings.TrimSpace(annotation)
+	content := strings.TrimPrefix(annotation, "#prompt:")
+
+	prompt, rest, ok := strings.Cut(content, "|")
+	if !ok {
+		return annotation
+	}
+
+	var parts []string
+	for _, part := range strings.Split(rest, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return "#prompt:" + strings.TrimSpace(prompt) + "|" + strings.Join(parts, ";")
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEnvFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "already canonical",
+			input: "#krakenv:strict=true\n\n# DB\nDB_HOST=localhost #prompt:Host?|string\n",
+			want:  "#krakenv:strict=true\n\n# DB\nDB_HOST=localhost #prompt:Host?|string\n",
+		},
+		{
+			name:  "spacing around equals and annotation",
+			input: "DB_PORT = 5432    #prompt: Port? | int ; min:1 ;max:65535\n",
+			want:  "DB_PORT=5432 #prompt:Port?|int;min:1;max:65535\n",
+		},
+		{
+			name:  "config moved to top",
+			input: "# Header\nA=1\n#krakenv:environments=local\n",
+			want:  "#krakenv:environments=local\n\n# Header\nA=1\n",
+		},
+		{
+			name:  "blank lines collapsed and trimmed",
+			input: "\n\nA=1   \n\n\n\nB=2\n\n\n",
+			want:  "A=1\n\nB=2\n",
+		},
+		{
+			name:  "unparsed lines and heredoc bodies kept",
+			input: "bad line\nCERT=<<EOF\n  indented  \nEOF\n",
+			want:  "bad line\nCERT=<<EOF\n  indented  \nEOF\n",
+		},
+		{
+			name:  "inline comment spacing",
+			input: "PORT=8080   #legacy, remove after v2   #prompt:Port?|int\n",
+			want:  "PORT=8080 # legacy, remove after v2 #prompt:Port?|int\n",
+		},
+		{
+			name:  "annotation line above variable",
+			input: "  #prompt: Port? | int ;min:1\nPORT=5432\n",
+			want:  "#prompt:Port?|int;min:1\nPORT=5432\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envFile, err := ParseEnvFileContent(tt.input, "test.env")
+			require.NoError(t, err)
+
+			got := FormatEnvFile(envFile)
+			assert.Equal(t, tt.want, got)
+
+			// Formatting is idempotent
+			again, err := ParseEnvFileContent(got, "test.env")
+			require.NoError(t, err)
+			assert.Equal(t, got, FormatEnvFile(again))
+		})
+	}
+}
+
+func TestFormatLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		annotation string
+		want       string
+	}{
+		{"empty default", "", "#prompt:Key?|string;secret", "API_KEY= #prompt:Key?|string;secret"},
+		{"default", "8080", "#prompt:Port?|int", "API_KEY=8080 #prompt:Port?|int"},
+		{"default with spaces", "a b", "#prompt:Text?|string", `API_KEY="a b" #prompt:Text?|string`},
+		{"annotation spacing", "", "  #prompt: Key? |string; secret ", "API_KEY= #prompt:Key?|string;secret"},
+		{"no annotation", "", "", "API_KEY="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := FormatLine("API_KEY", tt.value, tt.annotation)
+			assert.Equal(t, tt.want, line)
+
+			// The line parses back to the same value and annotation
+			envFile, err := ParseEnvFileContent(line, "test.env")
+			require.NoError(t, err)
+			v := envFile.GetVariable("API_KEY")
+			require.NotNil(t, v)
+			assert.Equal(t, tt.value, v.Value)
+			assert.Equal(t, tt.annotation != "", v.Annotation != nil)
+			assert.Equal(t, line, FormatEnvFile(envFile)[:len(line)])
+		})
+	}
+}
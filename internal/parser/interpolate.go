@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// referencePattern matches ${VAR} references inside a value.
+var referencePattern = regexp.MustCompile(`\$\{([A-Z][A-Z0-9_]*)\}`)
+
+// ErrUndefinedReference indicates a ${VAR} reference to a variable not in the file.
+var ErrUndefinedReference = errors.New("undefined variable reference")
+
+// ErrReferenceCycle indicates variables reference each other in a loop.
+var ErrReferenceCycle = errors.New("variable reference cycle")
+
+// ExtractReferences returns the names referenced as ${VAR} in a value, in order of
+// first appearance and without duplicates.
+func ExtractReferences(value string) []string {
+	matches := referencePattern.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	refs := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			refs = append(refs, m[1])
+		}
+	}
+	return refs
+}
+
+// Interpolate resolves ${VAR} references between the variables of a file and returns
+// the final value of every variable. References are taken from the current values, so
+// values changed after parsing are honored. Undefined references and cycles are errors.
+func Interpolate(envFile *EnvFile) (map[string]string, error) {
+	raw := make(map[string]string, len(envFile.Variables))
+	for _, v := range envFile.Variables {
+		raw[v.Name] = v.Value
+	}
+
+	resolved := make(map[string]string, len(raw))
+	visiting := make(map[string]bool)
+
+	var resolve func(name string, chain []string) (string, error)
+	resolve = func(name string, chain []string) (string, error) {
+		if value, ok := resolved[name]; ok {
+			return value, nil
+		}
+		if visiting[name] {
+			return "", fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(append(chain, name), " -> "))
+		}
+
+		visiting[name] = true
+		chain = append(chain, name)
+
+		value := raw[name]
+		for _, ref := range ExtractReferences(value) {
+			if _, ok := raw[ref]; !ok {
+				return "", fmt.Errorf("%w: %s references ${%s}", ErrUndefinedReference, name, ref)
+			}
+			refValue, err := resolve(ref, chain)
+			if err != nil {
+				return "", err
+			}
+			value = strings.ReplaceAll(value, "${"+ref+"}", refValue)
+		}
+
+		visiting[name] = false
+		resolved[name] = value
+		return value, nil
+	}
+
+	for _, v := range envFile.Variables {
+		if _, err := resolve(v.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
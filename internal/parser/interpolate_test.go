@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractReferences(t *testing.T) {
+	assert.Nil(t, ExtractReferences("plain"))
+	assert.Equal(t, []string{"DB_HOST", "DB_PORT"}, ExtractReferences("postgres://${DB_HOST}:${DB_PORT}/${DB_HOST}"))
+	assert.Nil(t, ExtractReferences("$DB_HOST ${lower}"))
+}
+
+func TestParseEnvFile_References(t *testing.T) {
+	input := `DB_HOST=localhost
+DATABASE_URL=postgres://${DB_HOST}/app
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	assert.Empty(t, envFile.GetVariable("DB_HOST").References)
+	assert.Equal(t, []string{"DB_HOST"}, envFile.GetVariable("DATABASE_URL").References)
+}
+
+func TestInterpolate(t *testing.T) {
+	// Order in the file does not matter
+	input := `DATABASE_URL=postgres://${DB_USER}@${DB_HOST}:${DB_PORT}/app
+DB_HOST=${DB_DOMAIN}
+DB_DOMAIN=db.local
+DB_PORT=5432
+DB_USER=app
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	values, err := Interpolate(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app@db.local:5432/app", values["DATABASE_URL"])
+	assert.Equal(t, "db.local", values["DB_HOST"])
+	assert.Equal(t, "5432", values["DB_PORT"])
+}
+
+func TestInterpolate_Undefined(t *testing.T) {
+	envFile, err := ParseEnvFileContent("URL=http://${MISSING}/\n", "test.env")
+	require.NoError(t, err)
+
+	_, err = Interpolate(envFile)
+	assert.ErrorIs(t, err, ErrUndefinedReference)
+	assert.Contains(t, err.Error(), "MISSING")
+}
+
+func TestInterpolate_Cycle(t *testing.T) {
+	input := `VAR_A=${VAR_B}
+VAR_B=${VAR_C}
+VAR_C=${VAR_A}
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	_, err = Interpolate(envFile)
+	assert.ErrorIs(t, err, ErrReferenceCycle)
+	assert.Contains(t, err.Error(), "VAR_A -> VAR_B -> VAR_C -> VAR_A")
+}
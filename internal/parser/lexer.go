@@ -16,50 +16,271 @@ var ErrInvalidVariableName = errors.New("variable name must start with uppercase
 // TokenizeLine parses a single line from an .env file.
 // Returns the variable name, value, annotation string, and any error.
 // For comments or empty lines, returns empty name with no error.
+// A trailing "# note" comment is left out of the value; the parser keeps it
+// in Variable.InlineComment.
 func TokenizeLine(line string) (name, value, annotation string, err error) {
-	line = strings.TrimSpace(line)
+	name, value, annotation, _, err = tokenizeLine(line, true)
+	return name, value, annotation, err
+}
+
+// tokenizeLine is TokenizeLine with optional whitespace trimming, also
+// returning the inline comment. Without trimming, an unquoted value is
+// everything after '=' up to the comment or annotation, and a quoted value is
+// everything between its quotes.
+func tokenizeLine(line string, trim bool) (name, value, annotation, comment string, err error) {
+	if trim {
+		line = strings.TrimSpace(line)
+	} else {
+		// Only the line ending goes; padding is kept
+		line = strings.TrimSuffix(strings.TrimLeft(line, " \t"), "\r")
+	}
 
 	// Empty line
 	if line == "" {
-		return "", "", "", nil
+		return "", "", "", "", nil
 	}
 
 	// Full-line comment (including krakenv config lines)
 	if strings.HasPrefix(line, "#") {
-		return "", "", "", nil
+		return "", "", "", "", nil
 	}
 
 	// Find the first equals sign
 	eqIdx := strings.Index(line, "=")
 	if eqIdx == -1 {
-		return "", "", "", nil
+		return "", "", "", "", nil
 	}
 
 	// Extract variable name
 	name = strings.TrimSpace(line[:eqIdx])
 	if name == "" {
-		return "", "", "", nil
+		return "", "", "", "", nil
 	}
 
 	// Validate variable name
 	if !variableNamePattern.MatchString(name) {
-		return "", "", "", ErrInvalidVariableName
+		return "", "", "", "", ErrInvalidVariableName
 	}
 
 	// Extract value and potential annotation
 	rest := line[eqIdx+1:]
 
 	// Check for annotation (#prompt:...)
-	annotationIdx := strings.Index(rest, " #prompt:")
+	annotationIdx := annotationIndex(rest)
 	if annotationIdx != -1 {
 		annotation = strings.TrimSpace(rest[annotationIdx+1:])
-		rest = rest[:annotationIdx]
+		// The whitespace separating the value from the annotation is not padding
+		rest = strings.TrimRight(rest[:annotationIdx], " \t")
 	}
 
+	// A "# note" after the value is a comment, not part of it
+	rest, comment = splitInlineComment(rest)
+
 	// Parse the value
+	if !trim && !isQuoted(strings.TrimSpace(rest)) {
+		return name, rest, annotation, comment, nil
+	}
 	value = parseValue(strings.TrimSpace(rest))
 
-	return name, value, annotation, nil
+	return name, value, annotation, comment, nil
+}
+
+// splitInlineComment splits the value part of a line, annotation excluded,
+// into the value and the text of its inline comment, if any.
+func splitInlineComment(rest string) (value, comment string) {
+	commentIdx := inlineCommentIndex(rest)
+	if commentIdx == -1 {
+		return rest, ""
+	}
+	comment = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest[commentIdx:]), "#"))
+	return strings.TrimRight(rest[:commentIdx], " \t"), comment
+}
+
+// inlineCommentIndex returns the index of the whitespace before a '#' that
+// starts a comment in the value part of a line, annotation excluded, or -1.
+// A '#' inside a quoted value or not preceded by whitespace is part of the value.
+func inlineCommentIndex(rest string) int {
+	start := 0
+	trimmed := strings.TrimLeft(rest, " \t")
+	offset := len(rest) - len(trimmed)
+	switch {
+	case strings.HasPrefix(trimmed, `"`):
+		if end := closingQuote(trimmed); end != -1 {
+			start = offset + end + 1
+		}
+	case strings.HasPrefix(trimmed, "'"):
+		if end := strings.Index(trimmed[1:], "'"); end != -1 {
+			start = offset + end + 2
+		}
+	}
+
+	for i := max(start, 1); i < len(rest); i++ {
+		if rest[i] == '#' && (rest[i-1] == ' ' || rest[i-1] == '\t') {
+			return i - 1
+		}
+	}
+	return -1
+}
+
+// isQuoted reports whether s is wrapped in matching single or double quotes.
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+// ReplaceLineValue returns a variable line with its value replaced, keeping the
+// name and any trailing comment and annotation exactly as written. The line may
+// be a whole heredoc variable as recorded in Line.Text; a multi-line value is
+// written as a heredoc and a single-line one on the opener line.
+func ReplaceLineValue(line, value string) string {
+	opener, _, _ := strings.Cut(line, "\n")
+	eqIdx := strings.Index(opener, "=")
+	if eqIdx == -1 {
+		return line
+	}
+
+	rest := opener[eqIdx+1:]
+	end := len(rest)
+	if annotationIdx := annotationIndex(rest); annotationIdx != -1 {
+		end = annotationIdx
+	}
+	if commentIdx := inlineCommentIndex(rest[:end]); commentIdx != -1 {
+		end = commentIdx
+	}
+
+	if strings.Contains(value, "\n") {
+		terminator := heredocTerminator(value)
+		return opener[:eqIdx+1] + "<<" + terminator + rest[end:] + "\n" + value + "\n" + terminator
+	}
+	return opener[:eqIdx+1] + QuoteValue(value) + rest[end:]
+}
+
+// ReplaceLineAnnotation returns a variable or annotation line with its
+// annotation replaced, keeping everything before it and any heredoc body.
+// Lines without an annotation are returned unchanged.
+func ReplaceLineAnnotation(line, annotation string) string {
+	opener, body, heredoc := strings.Cut(line, "\n")
+	idx := lineAnnotationIndex(opener)
+	if idx == -1 {
+		return line
+	}
+
+	opener = opener[:idx] + annotation
+	if heredoc {
+		opener += "\n" + body
+	}
+	return opener
+}
+
+// RenameReferences returns a variable or annotation line with the references
+// to oldName in its annotation (like:NAME and the requires/conflicts lists)
+// renamed to newName. The rest of the line is kept as written.
+func RenameReferences(line, oldName, newName string) string {
+	opener, body, heredoc := strings.Cut(line, "\n")
+	idx := lineAnnotationIndex(opener)
+	if idx == -1 {
+		return line
+	}
+	annotation := opener[idx:]
+	pipeIdx := strings.Index(annotation, "|")
+	if pipeIdx == -1 {
+		return line
+	}
+
+	parts := strings.Split(annotation[pipeIdx+1:], ";")
+	for i, part := range parts {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "like":
+			parts[i] = name + ":" + renameReference(value, oldName, newName)
+		case "requires", "conflicts":
+			names := strings.Split(value, ",")
+			for j := range names {
+				names[j] = renameReference(names[j], oldName, newName)
+			}
+			parts[i] = name + ":" + strings.Join(names, ",")
+		}
+	}
+
+	opener = opener[:idx] + annotation[:pipeIdx+1] + strings.Join(parts, ";")
+	if heredoc {
+		opener += "\n" + body
+	}
+	return opener
+}
+
+// renameReference renames a single reference, keeping its surrounding
+// whitespace, if it names oldName.
+func renameReference(ref, oldName, newName string) string {
+	if strings.TrimSpace(ref) != oldName {
+		return ref
+	}
+	return strings.Replace(ref, oldName, newName, 1)
+}
+
+// lineAnnotationIndex returns the index of "#prompt:" in a variable or
+// annotation line, or -1. The annotation runs to the end of the line.
+func lineAnnotationIndex(line string) int {
+	if IsStandaloneAnnotation(line) {
+		return strings.Index(line, "#prompt:")
+	}
+	eqIdx := strings.Index(line, "=")
+	if eqIdx == -1 || IsComment(line) {
+		return -1
+	}
+	idx := annotationIndex(line[eqIdx+1:])
+	if idx == -1 {
+		return -1
+	}
+	return eqIdx + 1 + idx + 1
+}
+
+// annotationIndex returns the index of the " #prompt:" annotation marker in
+// the part of a line after '=', or -1. A marker inside a double-quoted value
+// is part of the value.
+func annotationIndex(rest string) int {
+	start := 0
+	trimmed := strings.TrimLeft(rest, " \t")
+	if strings.HasPrefix(trimmed, `"`) {
+		offset := len(rest) - len(trimmed)
+		if end := closingQuote(trimmed); end != -1 {
+			start = offset + end + 1
+		}
+	}
+
+	idx := strings.Index(rest[start:], " #prompt:")
+	if idx == -1 {
+		return -1
+	}
+	return start + idx
+}
+
+// closingQuote returns the index of the double quote closing the quoted
+// string at the start of s, skipping backslash-escaped characters, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// QuoteValue returns value as it should be written after '='. Values with
+// whitespace, '#' or quotes, and values starting with "<<" that would read as
+// a heredoc opener, are double-quoted, escaping backslashes and double
+// quotes; other values are written as is.
+func QuoteValue(value string) string {
+	if !strings.ContainsAny(value, " \t#\"'") && !strings.HasPrefix(value, "<<") {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + escaped + `"`
 }
 
 // parseValue handles quoted and unquoted values.
@@ -69,8 +290,11 @@ func parseValue(s string) string {
 		return ""
 	}
 
-	// Handle double-quoted values
+	// Handle double-quoted values, unescaping \" and \\
 	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if closingQuote(s) == len(s)-1 {
+			return unescapeDoubleQuoted(s[1 : len(s)-1])
+		}
 		return s[1 : len(s)-1]
 	}
 
@@ -82,6 +306,23 @@ func parseValue(s string) string {
 	return s
 }
 
+// unescapeDoubleQuoted resolves the \" and \\ escapes written by QuoteValue.
+// Other backslashes are kept as written.
+func unescapeDoubleQuoted(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
 // IsComment checks if a line is a comment.
 func IsComment(line string) bool {
 	return strings.HasPrefix(strings.TrimSpace(line), "#")
@@ -97,9 +338,10 @@ func IsKrakenvConfigLine(line string) bool {
 	return strings.HasPrefix(strings.TrimSpace(line), "#krakenv:")
 }
 
-// IsAnnotationLine checks if a line contains a krakenv annotation.
-func IsAnnotationLine(line string) bool {
-	return strings.Contains(line, " #prompt:")
+// IsStandaloneAnnotation checks if a line holds only an annotation, which
+// applies to the variable on the next line.
+func IsStandaloneAnnotation(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#prompt:")
 }
 
 // ExtractCommentText extracts the text from a comment line (without the #).
@@ -121,6 +121,50 @@ func TestTokenizeLine_WithAnnotation(t *testing.T) {
 	}
 }
 
+func TestTokenizeLine_InlineComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantVal     string
+		wantComment string
+		wantAnn     string
+	}{
+		{"trailing comment", "PORT=8080 # legacy, remove after v2", "8080", "legacy, remove after v2", ""},
+		{"comment before annotation", "PORT=8080 # legacy #prompt:Port?|int", "8080", "legacy", "#prompt:Port?|int"},
+		{"empty value", "KEY= # fill in later", "", "fill in later", ""},
+		{"tab before comment", "PORT=8080\t# note", "8080", "note", ""},
+		{"hash without space", "URL=http://host/#anchor", "http://host/#anchor", "", ""},
+		{"double-quoted hash", `MSG="a # b" # note`, "a # b", "note", ""},
+		{"single-quoted hash", "MSG='a # b' # note", "a # b", "note", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, value, annotation, comment, err := tokenizeLine(tt.input, true)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVal, value)
+			assert.Equal(t, tt.wantComment, comment)
+			assert.Equal(t, tt.wantAnn, annotation)
+		})
+	}
+}
+
+func TestFormatVariable_InlineComment(t *testing.T) {
+	ann, err := ParseAnnotation("#prompt:Port?|int")
+	require.NoError(t, err)
+
+	v := Variable{Name: "PORT", Value: "8080", InlineComment: "legacy", Annotation: ann}
+	line := FormatVariable(v, true)
+	assert.Equal(t, "PORT=8080 # legacy #prompt:Port?|int", line)
+
+	envFile, err := ParseEnvFileContent(line, "test.env")
+	require.NoError(t, err)
+	parsed := envFile.GetVariable("PORT")
+	assert.Equal(t, "8080", parsed.Value)
+	assert.Equal(t, "legacy", parsed.InlineComment)
+	assert.NotNil(t, parsed.Annotation)
+}
+
 func TestTokenizeLine_Comments(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -199,3 +243,139 @@ func TestTokenizeLine_InvalidVariableName(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceLineValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		value string
+		want  string
+	}{
+		{"plain", "PORT=3000", "8080", "PORT=8080"},
+		{"keeps annotation", "PORT=3000 #prompt:Port?|int", "8080", "PORT=8080 #prompt:Port?|int"},
+		{"empty to value", "HOST= #prompt:Host?|string", "localhost", "HOST=localhost #prompt:Host?|string"},
+		{"value to empty", "HOST=localhost", "", "HOST="},
+		{"keeps comment", "PORT=3000 # legacy #prompt:Port?|int", "8080", "PORT=8080 # legacy #prompt:Port?|int"},
+		{"not a variable", "# comment", "x", "# comment"},
+		{"heredoc to single line", "CERT=<<EOF #prompt:Cert?|string\nline 1\nline 2\nEOF", "x", "CERT=x #prompt:Cert?|string"},
+		{"heredoc to heredoc", "CERT=<<END\nold\nEND", "a\nb", "CERT=<<EOF\na\nb\nEOF"},
+		{"single line to heredoc", "CERT=x # pem", "a\nEOF", "CERT=<<EOF1 # pem\na\nEOF\nEOF1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReplaceLineValue(tt.line, tt.value))
+		})
+	}
+}
+
+func TestQuoteValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"localhost", "localhost"},
+		{"", ""},
+		{"hello world", `"hello world"`},
+		{"#channel", `"#channel"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`it's`, `"it's"`},
+		{`C:\dir name`, `"C:\\dir name"`},
+		{"<<EOF", `"<<EOF"`},
+		{"<<", `"<<"`},
+		{"a<<b", "a<<b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, QuoteValue(tt.value))
+		})
+	}
+}
+
+func TestFormatVariable_QuotedRoundTrip(t *testing.T) {
+	values := []string{
+		`hello "world" #1`,
+		`value #prompt:Not an annotation?|string`,
+		`  padded  `,
+		`back\slash and "quote"`,
+		`'single'`,
+		`<<EOF`,
+	}
+
+	ann, err := ParseAnnotation("#prompt:Message?|string")
+	require.NoError(t, err)
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			line := FormatVariable(Variable{Name: "MESSAGE", Value: value, Annotation: ann}, true)
+
+			name, parsed, annotation, err := TokenizeLine(line)
+			require.NoError(t, err)
+			assert.Equal(t, "MESSAGE", name)
+			assert.Equal(t, value, parsed)
+			assert.Equal(t, "#prompt:Message?|string", annotation)
+		})
+	}
+}
+
+func TestQuoteValue_HeredocOpener(t *testing.T) {
+	envFile, err := ParseEnvFileContent("MARKER="+QuoteValue("<<EOF")+"\nNEXT=1\n", "test.env")
+	require.NoError(t, err)
+	assert.Equal(t, "<<EOF", envFile.GetVariable("MARKER").Value)
+	assert.Equal(t, "1", envFile.GetVariable("NEXT").Value)
+}
+
+func TestReplaceLineValue_Quotes(t *testing.T) {
+	line := ReplaceLineValue("GREETING=hi #prompt:Greeting?|string", `hello "world" #1`)
+	assert.Equal(t, `GREETING="hello \"world\" #1" #prompt:Greeting?|string`, line)
+
+	_, value, annotation, err := TokenizeLine(line)
+	require.NoError(t, err)
+	assert.Equal(t, `hello "world" #1`, value)
+	assert.Equal(t, "#prompt:Greeting?|string", annotation)
+}
+
+func TestReplaceLineAnnotation(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"inline", "PORT=3000 # web #prompt:Port?|like:BASE", "PORT=3000 # web #prompt:New?|int"},
+		{"standalone", "  #prompt:Port?|like:BASE", "  #prompt:New?|int"},
+		{"heredoc", "CERT=<<EOF #prompt:Cert?|like:BASE\nbody\nEOF", "CERT=<<EOF #prompt:New?|int\nbody\nEOF"},
+		{"no annotation", "PORT=3000", "PORT=3000"},
+		{"quoted marker", `NOTE="a #prompt:b|c"`, `NOTE="a #prompt:b|c"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReplaceLineAnnotation(tt.line, "#prompt:New?|int"))
+		})
+	}
+}
+
+func TestRenameReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"type position", "B= #prompt:B?|like:OLD;optional", "B= #prompt:B?|like:NEW;optional"},
+		{"constraint", "B= #prompt:B?|string;like: OLD ;minlen:3", "B= #prompt:B?|string;like: NEW ;minlen:3"},
+		{"standalone", "#prompt:B?|like:OLD", "#prompt:B?|like:NEW"},
+		{"other reference", "B= #prompt:B?|like:OLDER", "B= #prompt:B?|like:OLDER"},
+		{"value and prompt untouched", "OLD=like:OLD #prompt:like:OLD?|string", "OLD=like:OLD #prompt:like:OLD?|string"},
+		{"heredoc", "B=<<EOF #prompt:B?|like:OLD\nlike:OLD\nEOF", "B=<<EOF #prompt:B?|like:NEW\nlike:OLD\nEOF"},
+		{"requires list", "B= #prompt:B?|string;requires:A, OLD,OLDER", "B= #prompt:B?|string;requires:A, NEW,OLDER"},
+		{"conflicts", "#prompt:B?|string;conflicts:OLD", "#prompt:B?|string;conflicts:NEW"},
+		{"all references", "B= #prompt:B?|like:OLD;requires:OLD;conflicts:C", "B= #prompt:B?|like:NEW;requires:NEW;conflicts:C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RenameReferences(tt.line, "OLD", "NEW"))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUndefinedLike indicates a like reference to a variable that is not in
+// the file or has no annotation.
+var ErrUndefinedLike = errors.New("undefined like reference")
+
+// ErrLikeCycle indicates annotations extend each other in a loop.
+var ErrLikeCycle = errors.New("like reference cycle")
+
+// ResolveLikes merges the annotation of each like:NAME reference into the
+// annotations that use it. The referenced type and constraints are the base;
+// the annotation's own type and constraints override them. References may
+// point forward in the file and may be chained.
+//
+// Parsing resolves the references of each file. Files parsed with SkipLikes
+// are resolved here once merged, so references can cross files, or on their
+// own with the other files as context: references may then also point to the
+// annotations of the context files, the file's own taking precedence.
+func ResolveLikes(envFile *EnvFile, context ...*EnvFile) error {
+	annotations := make(map[string]*Annotation, len(envFile.Variables))
+	for _, f := range append(context[:len(context):len(context)], envFile) {
+		for _, v := range f.Variables {
+			if v.Annotation != nil {
+				annotations[v.Name] = v.Annotation
+			}
+		}
+	}
+
+	resolved := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var resolve func(name string, chain []string) error
+	resolve = func(name string, chain []string) error {
+		ann := annotations[name]
+		if resolved[name] || ann.Like == "" {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("%w: %s", ErrLikeCycle, strings.Join(append(chain, name), " -> "))
+		}
+
+		visiting[name] = true
+		chain = append(chain, name)
+
+		base, ok := annotations[ann.Like]
+		if !ok {
+			return fmt.Errorf("%w: %s is like:%s, which is not an annotated variable", ErrUndefinedLike, name, ann.Like)
+		}
+		if err := resolve(ann.Like, chain); err != nil {
+			return err
+		}
+		extend(ann, base)
+
+		visiting[name] = false
+		resolved[name] = true
+		return nil
+	}
+
+	for _, v := range envFile.Variables {
+		if v.Annotation == nil {
+			continue
+		}
+		if err := resolve(v.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extend merges a base annotation into ann: the base's constraints come
+// first, replaced by ann's own constraints of the same name, and the base's
+// type is used when ann has none of its own. Only ann's own constraints are
+// kept apart, so extending again starts from them rather than the result.
+func extend(ann, base *Annotation) {
+	if ann.inheritType {
+		ann.Type = base.Type
+	}
+
+	own := ann.ownConstraints
+	if own == nil {
+		own = ann.Constraints
+		ann.ownConstraints = own
+	}
+	overridden := make(map[string]bool, len(own))
+	for _, c := range own {
+		overridden[c.Name] = true
+	}
+
+	constraints := make([]Constraint, 0, len(base.Constraints)+len(own))
+	for _, c := range base.Constraints {
+		if !overridden[c.Name] {
+			constraints = append(constraints, c)
+		}
+	}
+	ann.Constraints = append(constraints, own...)
+
+	checkEnumOptions(ann)
+}
+
+// Flatten returns a copy of a resolved annotation without its like reference,
+// written with the inherited type and constraints in full. It is for output
+// where the referenced variable is not written alongside.
+func (a *Annotation) Flatten() *Annotation {
+	flat := *a
+	flat.Like = ""
+	flat.inheritType = false
+	flat.ownConstraints = nil
+	return &flat
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/config"
+)
+
+// MergeEnvFiles combines several parsed files into one, as if they were a
+// single file: a variable defined in more than one file takes its value and
+// annotation from the last file but keeps the position of its first
+// definition, and is not reported as a duplicate. Config keys set in later
+// files override those set earlier. Lines, comments and warnings are kept in
+// file order. Returns nil if no files are given.
+func MergeEnvFiles(files ...*EnvFile) *EnvFile {
+	switch len(files) {
+	case 0:
+		return nil
+	case 1:
+		return files[0]
+	}
+
+	merged := &EnvFile{}
+	positions := make(map[string]int)
+	var paths, configLines []string
+
+	for _, f := range files {
+		paths = append(paths, f.Path)
+
+		for _, v := range f.Variables {
+			if i, ok := positions[v.Name]; ok {
+				merged.Variables[i] = v
+				continue
+			}
+			positions[v.Name] = len(merged.Variables)
+			merged.Variables = append(merged.Variables, v)
+		}
+
+		// Keep files apart so a trailing comment does not run into the next file
+		if len(merged.Lines) > 0 && len(f.Lines) > 0 && merged.Lines[len(merged.Lines)-1].Kind != LineBlank {
+			merged.Lines = append(merged.Lines, Line{Kind: LineBlank})
+		}
+		for _, line := range f.Lines {
+			merged.Lines = append(merged.Lines, line)
+			if line.Kind == LineConfig {
+				configLines = append(configLines, line.Text)
+			}
+		}
+
+		merged.Comments = append(merged.Comments, f.Comments...)
+		merged.Duplicates = append(merged.Duplicates, f.Duplicates...)
+		merged.Warnings = append(merged.Warnings, f.Warnings...)
+	}
+
+	merged.Path = strings.Join(paths, ", ")
+
+	if len(configLines) > 0 {
+		cfg := config.ParseConfig(configLines)
+		merged.Config = &KrakenvConfig{
+			Environments:   cfg.Environments,
+			Strict:         cfg.Strict,
+			DistPath:       cfg.DistPath,
+			Ignore:         cfg.Ignore,
+			KeepWhitespace: cfg.KeepWhitespace,
+			Defaults:       cfg.Defaults,
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeEnvFiles(t *testing.T) {
+	base, err := ParseEnvFileContent(`#krakenv:environments=local,production
+#krakenv:strict=true
+
+# Shared
+DB_HOST=localhost #prompt:Host?|string
+LOG_LEVEL=info #prompt:Level?|enum;options:debug,info
+`, "base.env.dist")
+	require.NoError(t, err)
+
+	service, err := ParseEnvFileContent(`#krakenv:environments=local,staging
+
+LOG_LEVEL=debug #prompt:Level?|enum;options:debug,info,warn
+API_PORT=8080 #prompt:Port?|int
+`, "service.env.dist")
+	require.NoError(t, err)
+
+	merged := MergeEnvFiles(base, service)
+
+	assert.Equal(t, "base.env.dist, service.env.dist", merged.Path)
+
+	names := make([]string, 0, len(merged.Variables))
+	for _, v := range merged.Variables {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{"DB_HOST", "LOG_LEVEL", "API_PORT"}, names)
+
+	logLevel := merged.GetVariable("LOG_LEVEL")
+	require.NotNil(t, logLevel)
+	assert.Equal(t, "debug", logLevel.Value)
+	assert.Equal(t, "debug,info,warn", logLevel.Annotation.GetConstraint("options"))
+	assert.Empty(t, merged.Duplicates)
+
+	// Keys set later win; keys set only earlier are kept
+	require.NotNil(t, merged.Config)
+	assert.Equal(t, []string{"local", "staging"}, merged.Config.Environments)
+	assert.True(t, merged.Config.Strict)
+}
+
+func TestMergeEnvFiles_Single(t *testing.T) {
+	f := &EnvFile{Path: ".env.dist"}
+
+	assert.Same(t, f, MergeEnvFiles(f))
+	assert.Nil(t, MergeEnvFiles())
+}
@@ -4,35 +4,80 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/theburrowhub/krakenv/internal/config"
+	"github.com/theburrowhub/krakenv/internal/log"
 )
 
 // ErrInvalidAnnotation indicates the annotation syntax is invalid.
 var ErrInvalidAnnotation = errors.New("invalid annotation syntax")
 
+// ErrUnterminatedHeredoc indicates a heredoc value has no terminator line.
+var ErrUnterminatedHeredoc = errors.New("unterminated heredoc")
+
+// heredocOpener matches a heredoc value opener such as <<EOF.
+var heredocOpener = regexp.MustCompile(`^<<([A-Za-z_][A-Za-z0-9_]*)$`)
+
 // knownConstraints lists all valid constraint names.
 var knownConstraints = map[string]bool{
-	"min":      true,
-	"max":      true,
-	"minlen":   true,
-	"maxlen":   true,
-	"pattern":  true,
-	"options":  true,
-	"format":   true,
-	"encoding": true,
+	"min":          true,
+	"max":          true,
+	"minlen":       true,
+	"maxlen":       true,
+	"pattern":      true,
+	"options":      true,
+	"format":       true,
+	"encoding":     true,
+	"schemes":      true,
+	"allowName":    true,
+	"error":        true,
+	"lenmode":      true,
+	"envdefault":   true,
+	"schema":       true,
+	"exclusiveMin": true,
+	"exclusiveMax": true,
+	"desc":         true,
+	"step":         true,
+	"version":      true,
+	"vprefix":      true,
+	"requires":     true,
+	"conflicts":    true,
+	"exists":       true,
+	"kind":         true,
+	"abs":          true,
+	"allowip":      true,
+	"pattern-desc": true,
+	"layout":       true,
+	"after":        true,
+	"before":       true,
+	"entropy":      true,
+	"output":       true,
+	"like":         true,
 }
 
 // ParseAnnotation parses an annotation string into an Annotation struct.
 // Annotation format: #prompt:MESSAGE|TYPE;CONSTRAINT:VALUE;...
+// Unknown constraints and modifiers are ignored.
 func ParseAnnotation(s string) (*Annotation, error) {
+	ann, _, err := parseAnnotation(s)
+	return ann, err
+}
+
+// parseAnnotation parses an annotation and also returns warnings for the
+// parts it ignored. The warnings carry no line information.
+func parseAnnotation(s string) (*Annotation, []ParseWarning, error) {
+	var warnings []ParseWarning
 	s = strings.TrimSpace(s)
 
 	// Must start with #prompt:
 	if !strings.HasPrefix(s, "#prompt:") {
-		return nil, fmt.Errorf("%w: must start with #prompt", ErrInvalidAnnotation)
+		return nil, nil, fmt.Errorf("%w: must start with #prompt", ErrInvalidAnnotation)
 	}
 
 	// Remove prefix
@@ -41,7 +86,7 @@ func ParseAnnotation(s string) (*Annotation, error) {
 	// Split by | to separate message from type+constraints
 	pipeIdx := strings.Index(content, "|")
 	if pipeIdx == -1 {
-		return nil, fmt.Errorf("%w: missing | separator", ErrInvalidAnnotation)
+		return nil, nil, fmt.Errorf("%w: missing | separator", ErrInvalidAnnotation)
 	}
 
 	ann := &Annotation{
@@ -51,17 +96,22 @@ func ParseAnnotation(s string) (*Annotation, error) {
 
 	// Parse type and constraints
 	rest := content[pipeIdx+1:]
-	parts := strings.Split(rest, ";")
+	parts := splitConstraints(rest)
 
 	if len(parts) == 0 || parts[0] == "" {
-		return nil, fmt.Errorf("%w: missing type", ErrInvalidAnnotation)
+		return nil, nil, fmt.Errorf("%w: missing type", ErrInvalidAnnotation)
 	}
 
-	// First part is the type
-	ann.Type = ParseVariableType(strings.TrimSpace(parts[0]))
+	// First part is the type, unless it is taken from a like reference
+	if strings.HasPrefix(strings.TrimSpace(parts[0]), "like:") {
+		ann.inheritType = true
+	} else {
+		ann.Type = ParseVariableType(strings.TrimSpace(parts[0]))
+		parts = parts[1:]
+	}
 
 	// Remaining parts are constraints/modifiers
-	for _, part := range parts[1:] {
+	for _, part := range parts {
 		part = strings.TrimSpace(part)
 		if part == "" {
 			continue
@@ -76,20 +126,52 @@ func ParseAnnotation(s string) (*Annotation, error) {
 			ann.IsSecret = true
 			continue
 		}
+		if part == "nocase" {
+			ann.NoCase = true
+			continue
+		}
 
 		// Parse constraint with colon
 		colonIdx := strings.Index(part, ":")
 		if colonIdx == -1 {
 			// Unknown modifier without colon - ignore (FR-041)
+			warnings = append(warnings, ParseWarning{
+				Code:    WarnUnknownModifier,
+				Message: fmt.Sprintf("unknown modifier %q ignored", part),
+			})
 			continue
 		}
 
 		constraintName := strings.TrimSpace(part[:colonIdx])
 		constraintValue := strings.TrimSpace(part[colonIdx+1:])
 
+		// default@<env>:value is the default for one environment
+		if env, ok := strings.CutPrefix(constraintName, "default@"); ok && env != "" {
+			if ann.EnvDefaults == nil {
+				ann.EnvDefaults = make(map[string]string)
+			}
+			ann.EnvDefaults[env] = constraintValue
+			continue
+		}
+
 		// Check if it's a known constraint (FR-041: ignore unknown)
 		if !knownConstraints[constraintName] {
-			// Unknown constraint - ignore with warning (TODO: add warning system)
+			warnings = append(warnings, ParseWarning{
+				Code:    WarnUnknownConstraint,
+				Message: fmt.Sprintf("unknown constraint %q ignored", constraintName),
+			})
+			continue
+		}
+
+		// The description is documentation, not a validation rule
+		if constraintName == "desc" {
+			ann.Description = descriptionUnescaper.Replace(constraintValue)
+			continue
+		}
+
+		// The base annotation is merged in once the whole file is parsed
+		if constraintName == "like" {
+			ann.Like = constraintValue
 			continue
 		}
 
@@ -99,156 +181,477 @@ func ParseAnnotation(s string) (*Annotation, error) {
 		})
 	}
 
-	// FR-042: Enum with empty options becomes string
-	if ann.Type == TypeEnum {
-		options := ann.GetConstraint("options")
-		if options == "" {
-			ann.Type = TypeString
-		}
+	// Options may come from the like reference; checked after merging it
+	if ann.Like == "" {
+		checkEnumOptions(ann)
+	} else {
+		ann.ownConstraints = ann.Constraints
+	}
+
+	return ann, warnings, nil
+}
+
+// checkEnumOptions applies FR-042: an enum with empty options becomes a string.
+func checkEnumOptions(ann *Annotation) {
+	if ann.Type == TypeEnum && ann.GetConstraint("options") == "" {
+		ann.Type = TypeString
 	}
+}
+
+// ParseOptions changes how env files are parsed. The zero value is the
+// default behavior.
+type ParseOptions struct {
+	// KeepWhitespace keeps whitespace around values instead of trimming it,
+	// as a #krakenv:trim=false line in the file does.
+	KeepWhitespace bool
 
-	return ann, nil
+	// SkipLikes leaves like:NAME references unresolved, for files that are
+	// merged with MergeEnvFiles and then resolved with ResolveLikes.
+	SkipLikes bool
 }
 
-// ParseEnvFile parses an .env file from disk.
-func ParseEnvFile(path string) (*EnvFile, error) {
+// ParseEnvFile parses an .env file from disk. At most one ParseOptions is used.
+func ParseEnvFile(path string, opts ...ParseOptions) (*EnvFile, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
+	return ParseEnvFileStream(file, path, opts...)
+}
+
+// ParseEnvFileStream parses .env content from a reader one line at a time,
+// without holding the raw content in memory. At most one ParseOptions is used.
+func ParseEnvFileStream(r io.Reader, path string, opts ...ParseOptions) (*EnvFile, error) {
+	p := newLineParser(path, opts)
+
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+		p.parseLine(scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return parseLines(lines, path)
+	return p.finish()
 }
 
-// ParseEnvFileContent parses .env content from a string.
-func ParseEnvFileContent(content string, path string) (*EnvFile, error) {
-	lines := strings.Split(content, "\n")
-	return parseLines(lines, path)
+// ParseEnvFileStrict parses an .env file like ParseEnvFile, but also returns a
+// *ParseWarningsError if the parser had to skip or ignore anything. The parsed
+// file is returned either way.
+func ParseEnvFileStrict(path string) (*EnvFile, error) {
+	envFile, err := ParseEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(envFile.Warnings) > 0 {
+		return envFile, &ParseWarningsError{Path: path, Warnings: envFile.Warnings}
+	}
+	return envFile, nil
 }
 
-// parseLines parses a slice of lines into an EnvFile.
-func parseLines(lines []string, path string) (*EnvFile, error) {
-	envFile := &EnvFile{
-		Path:      path,
-		Variables: make([]Variable, 0),
-		Comments:  make([]Comment, 0),
+// ParseEnvFileContent parses .env content from a string. At most one
+// ParseOptions is used.
+func ParseEnvFileContent(content string, path string, opts ...ParseOptions) (*EnvFile, error) {
+	p := newLineParser(path, opts)
+	for _, line := range strings.Split(content, "\n") {
+		p.parseLine(line)
 	}
+	return p.finish()
+}
 
-	// Collect config lines
-	var configLines []string
+// notAboveVariable is the warning for an annotation line with no variable below it.
+const notAboveVariable = "annotation line is not directly above a variable, ignored"
 
-	// Track variable positions for duplicate detection
-	varPositions := make(map[string]int)
+// lineParser builds an EnvFile from lines fed to it one at a time.
+//
+// A #krakenv:trim=false line may come after the values it applies to, so
+// values are read untrimmed and trimmed by finish unless whitespace is kept.
+type lineParser struct {
+	envFile        *EnvFile
+	path           string
+	keepWhitespace bool
+	skipLikes      bool
+	lineNumber     int
 
-	for lineNum, line := range lines {
-		lineNumber := lineNum + 1 // 1-indexed
+	// Collected config lines
+	configLines []string
 
-		// Handle krakenv config lines
-		if config.IsConfigLine(line) {
-			configLines = append(configLines, line)
-			continue
-		}
+	// Variable positions and first definitions for duplicate detection
+	varPositions map[string]int
+	firstLines   map[string]int
 
-		// Handle standalone comments
-		if IsComment(line) && !IsAnnotationLine(line) {
-			text := ExtractCommentText(line)
-			if text != "" {
-				envFile.Comments = append(envFile.Comments, Comment{
-					Text:       text,
-					LineNumber: lineNumber,
-				})
-			}
-			continue
-		}
+	// Variables whose value is a heredoc body, never trimmed
+	heredocs map[string]bool
 
-		// Handle empty lines
-		if IsEmptyLine(line) {
-			continue
-		}
+	// An annotation on its own line waits for the variable on the next line
+	pendingAnnotation string
+	pendingLine       int
 
-		// Parse variable line
-		name, value, annotationStr, err := TokenizeLine(line)
-		if err != nil {
-			// Invalid variable name - skip with warning
-			continue
+	// A heredoc being collected up to its terminator line
+	heredoc *pendingHeredoc
+}
+
+// pendingHeredoc is a variable whose heredoc body is still being read.
+type pendingHeredoc struct {
+	variable   Variable
+	annotation string
+	terminator string
+	lines      []string // The opener line and the body read so far
+}
+
+func newLineParser(path string, opts []ParseOptions) *lineParser {
+	var opt ParseOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	return &lineParser{
+		envFile: &EnvFile{
+			Path:      path,
+			Variables: make([]Variable, 0),
+			Comments:  make([]Comment, 0),
+		},
+		path:           path,
+		keepWhitespace: opt.KeepWhitespace,
+		skipLikes:      opt.SkipLikes,
+		varPositions:   make(map[string]int),
+		firstLines:     make(map[string]int),
+		heredocs:       make(map[string]bool),
+	}
+}
+
+// orphanWarning reports a pending annotation line that no variable took.
+func (p *lineParser) orphanWarning(message string) {
+	if p.pendingAnnotation != "" {
+		p.envFile.Warnings = append(p.envFile.Warnings, ParseWarning{
+			Code:       WarnOrphanAnnotation,
+			LineNumber: p.pendingLine,
+			Message:    message,
+		})
+		p.pendingAnnotation = ""
+	}
+}
+
+// parseLine parses the next line of the file.
+func (p *lineParser) parseLine(line string) {
+	p.lineNumber++
+	envFile := p.envFile
+
+	// Collect a heredoc body up to its terminator line, kept verbatim
+	if h := p.heredoc; h != nil {
+		h.lines = append(h.lines, line)
+		if strings.TrimSpace(line) != h.terminator {
+			return
 		}
+		p.heredoc = nil
+		h.variable.Value = strings.Join(h.lines[1:len(h.lines)-1], "\n")
+		envFile.Lines[len(envFile.Lines)-1].Text = strings.Join(h.lines, "\n")
+		p.addVariable(h.variable, h.annotation, true)
+		return
+	}
 
-		if name == "" {
-			continue
+	// Handle krakenv config lines
+	if config.IsConfigLine(line) {
+		p.orphanWarning(notAboveVariable)
+		p.configLines = append(p.configLines, line)
+		envFile.Lines = append(envFile.Lines, Line{Kind: LineConfig, Text: line})
+		return
+	}
+
+	// Handle annotations on their own line
+	if IsStandaloneAnnotation(line) {
+		p.orphanWarning(notAboveVariable)
+		p.pendingAnnotation = strings.TrimSpace(line)
+		p.pendingLine = p.lineNumber
+		envFile.Lines = append(envFile.Lines, Line{Kind: LineAnnotation, Text: line})
+		return
+	}
+
+	// Handle standalone comments, including commented-out annotated examples
+	if IsComment(line) {
+		p.orphanWarning(notAboveVariable)
+		envFile.Lines = append(envFile.Lines, Line{Kind: LineComment, Text: line})
+		text := ExtractCommentText(line)
+		if text != "" {
+			envFile.Comments = append(envFile.Comments, Comment{
+				Text:       text,
+				LineNumber: p.lineNumber,
+			})
 		}
+		return
+	}
 
-		// Create variable
-		variable := Variable{
-			Name:       name,
-			Value:      strings.TrimSpace(value), // FR-040: trim whitespace
-			LineNumber: lineNumber,
-			IsSet:      value != "" || strings.Contains(line, "="),
+	// Handle empty lines
+	if IsEmptyLine(line) {
+		p.orphanWarning(notAboveVariable)
+		envFile.Lines = append(envFile.Lines, Line{Kind: LineBlank})
+		return
+	}
+
+	// Parse variable line; finish trims the value unless whitespace is kept
+	name, value, annotationStr, comment, err := tokenizeLine(line, false)
+	if err != nil || name == "" {
+		// Invalid variable name or unrecognized line - skip with warning
+		p.orphanWarning(notAboveVariable)
+		envFile.Lines = append(envFile.Lines, Line{Kind: LineUnparsed, Text: line})
+		envFile.Warnings = append(envFile.Warnings, unparsedLineWarning(line, p.lineNumber, err))
+		return
+	}
+	envFile.Lines = append(envFile.Lines, Line{Kind: LineVariable, Text: line, Name: name})
+
+	variable := Variable{
+		Name:          name,
+		Value:         value,
+		InlineComment: comment,
+		LineNumber:    p.lineNumber,
+		IsSet:         value != "" || strings.Contains(line, "="),
+	}
+
+	// An inline annotation takes precedence over one on the line above
+	if p.pendingAnnotation != "" {
+		if annotationStr == "" {
+			annotationStr = p.pendingAnnotation
+			variable.AnnotationAbove = true
+			p.pendingAnnotation = ""
+		} else {
+			p.orphanWarning(fmt.Sprintf("annotation line ignored, %s has an inline annotation", name))
 		}
+	}
 
-		// Parse annotation if present
-		if annotationStr != "" {
-			ann, err := ParseAnnotation(annotationStr)
-			if err != nil {
-				// Invalid annotation syntax - treat as no annotation
-				// TODO: add warning
-			} else {
-				variable.Annotation = ann
-			}
+	// A heredoc opener defers the variable until its body has been read; a
+	// quoted "<<EOF" is a plain value
+	_, raw, _ := strings.Cut(line, "=")
+	if m := heredocOpener.FindStringSubmatch(strings.TrimSpace(value)); m != nil && strings.HasPrefix(strings.TrimSpace(raw), "<<") {
+		p.heredoc = &pendingHeredoc{
+			variable:   variable,
+			annotation: annotationStr,
+			terminator: m[1],
+			lines:      []string{line},
 		}
+		return
+	}
 
-		// Handle duplicates: last wins, but track position
-		if existingIdx, exists := varPositions[name]; exists {
-			// Replace existing variable
-			envFile.Variables[existingIdx] = variable
+	p.addVariable(variable, annotationStr, false)
+}
+
+// addVariable parses the variable's annotation and records it, replacing an
+// earlier definition of the same name.
+func (p *lineParser) addVariable(variable Variable, annotationStr string, heredoc bool) {
+	envFile := p.envFile
+	name := variable.Name
+	variable.References = ExtractReferences(variable.Value)
+	p.heredocs[name] = heredoc
+
+	// Parse annotation if present
+	if annotationStr != "" {
+		ann, warnings, err := parseAnnotation(annotationStr)
+		if err != nil {
+			// Invalid annotation syntax - treat as no annotation
+			warnings = []ParseWarning{{
+				Code:    WarnInvalidAnnotation,
+				Message: fmt.Sprintf("annotation ignored: %v", err),
+			}}
 		} else {
-			varPositions[name] = len(envFile.Variables)
-			envFile.Variables = append(envFile.Variables, variable)
+			ann.BaseDir = filepath.Dir(p.path)
+			variable.Annotation = ann
+		}
+		for _, w := range warnings {
+			w.Variable = name
+			w.LineNumber = variable.LineNumber
+			envFile.Warnings = append(envFile.Warnings, w)
 		}
 	}
 
+	// Handle duplicates: last wins, but track position
+	if existingIdx, exists := p.varPositions[name]; exists {
+		// Replace existing variable
+		envFile.Variables[existingIdx] = variable
+		envFile.Duplicates = append(envFile.Duplicates, DuplicateInfo{
+			Name:          name,
+			FirstLine:     p.firstLines[name],
+			DuplicateLine: variable.LineNumber,
+		})
+	} else {
+		p.varPositions[name] = len(envFile.Variables)
+		p.firstLines[name] = variable.LineNumber
+		envFile.Variables = append(envFile.Variables, variable)
+	}
+}
+
+// finish checks for an unterminated heredoc, applies the config block and
+// trims values, and returns the parsed file.
+func (p *lineParser) finish() (*EnvFile, error) {
+	envFile := p.envFile
+
+	if h := p.heredoc; h != nil {
+		return nil, fmt.Errorf("%w: %s on line %d has no %s terminator",
+			ErrUnterminatedHeredoc, h.variable.Name, h.variable.LineNumber, h.terminator)
+	}
+
+	p.orphanWarning(notAboveVariable)
+
 	// Parse config block
-	if len(configLines) > 0 {
-		cfg := config.ParseConfig(configLines)
+	if len(p.configLines) > 0 {
+		cfg := config.ParseConfig(p.configLines)
 		envFile.Config = &KrakenvConfig{
-			Environments: cfg.Environments,
-			Strict:       cfg.Strict,
-			DistPath:     cfg.DistPath,
+			Environments:   cfg.Environments,
+			Strict:         cfg.Strict,
+			DistPath:       cfg.DistPath,
+			Ignore:         cfg.Ignore,
+			KeepWhitespace: cfg.KeepWhitespace,
+			Defaults:       cfg.Defaults,
+		}
+	}
+
+	if !p.skipLikes {
+		if err := ResolveLikes(envFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// FR-040: trim whitespace, unless the options or a trim=false line keep it
+	if !p.keepWhitespace && (envFile.Config == nil || !envFile.Config.KeepWhitespace) {
+		for i, v := range envFile.Variables {
+			if !p.heredocs[v.Name] {
+				envFile.Variables[i].Value = strings.TrimSpace(v.Value)
+			}
 		}
 	}
 
+	for _, w := range envFile.Warnings {
+		log.Debug("parse warning", "path", envFile.Path, "line", w.LineNumber, "code", w.Code, "message", w.Message)
+	}
+	log.Debug("parsed env file", "path", envFile.Path, "variables", len(envFile.Variables), "warnings", len(envFile.Warnings))
+
 	return envFile, nil
 }
 
+// unparsedLineWarning describes why a non-blank, non-comment line was skipped.
+func unparsedLineWarning(line string, lineNumber int, err error) ParseWarning {
+	if errors.Is(err, ErrInvalidVariableName) {
+		name, _, _ := strings.Cut(strings.TrimSpace(line), "=")
+		name = strings.TrimSpace(name)
+		return ParseWarning{
+			Code:       WarnInvalidName,
+			Variable:   name,
+			LineNumber: lineNumber,
+			Message:    "invalid variable name (use A-Z, 0-9 and _, starting with a letter), line ignored",
+		}
+	}
+	return ParseWarning{
+		Code:       WarnUnparsedLine,
+		LineNumber: lineNumber,
+		Message:    "line is not NAME=value, ignored",
+	}
+}
+
 // FormatVariable formats a variable as a line for an .env file.
+// Values containing newlines are written in heredoc form.
 func FormatVariable(v Variable, includeAnnotation bool) string {
-	line := v.Name + "=" + v.Value
+	multiline := strings.Contains(v.Value, "\n")
+
+	var terminator string
+	line := v.Name + "=" + QuoteValue(v.Value)
+	if multiline {
+		terminator = heredocTerminator(v.Value)
+		line = v.Name + "=<<" + terminator
+	}
+
+	// The comment goes before the annotation, which runs to the end of the line
+	if v.InlineComment != "" {
+		line += " # " + v.InlineComment
+	}
+
 	if includeAnnotation && v.Annotation != nil {
 		line += " " + FormatAnnotation(v.Annotation)
 	}
+
+	if multiline {
+		line += "\n" + v.Value + "\n" + terminator
+	}
 	return line
 }
 
+// FormatVariableAbove formats a variable like FormatVariable, with its
+// annotation on the line above instead of after the value.
+func FormatVariableAbove(v Variable) string {
+	if v.Annotation == nil {
+		return FormatVariable(v, false)
+	}
+	return FormatAnnotation(v.Annotation) + "\n" + FormatVariable(v, false)
+}
+
+// heredocTerminator returns a terminator that does not appear as a line of value.
+func heredocTerminator(value string) string {
+	bodyLines := make(map[string]bool)
+	for _, l := range strings.Split(value, "\n") {
+		bodyLines[strings.TrimSpace(l)] = true
+	}
+
+	terminator := "EOF"
+	for i := 1; bodyLines[terminator]; i++ {
+		terminator = fmt.Sprintf("EOF%d", i)
+	}
+	return terminator
+}
+
+// splitConstraints splits the type and constraints part of an annotation on
+// the semicolons that are not escaped with a backslash.
+func splitConstraints(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ';' && (i == 0 || s[i-1] != '\\') {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// A description is free text, so the annotation separators in it are escaped
+// with a backslash when it is written as a desc constraint.
+var (
+	descriptionEscaper   = strings.NewReplacer(";", `\;`, "|", `\|`)
+	descriptionUnescaper = strings.NewReplacer(`\;`, ";", `\|`, "|")
+)
+
 // FormatAnnotation formats an Annotation back to string format.
 func FormatAnnotation(a *Annotation) string {
 	var parts []string
 
-	// Add type
-	parts = append(parts, a.Type.String())
+	// Add type, or the like reference it comes from
+	constraints := a.Constraints
+	switch {
+	case a.Like == "":
+		parts = append(parts, a.Type.String())
+	case a.inheritType:
+		parts = append(parts, "like:"+a.Like)
+	default:
+		parts = append(parts, a.Type.String(), "like:"+a.Like)
+	}
+	// Inherited constraints are left to the reference
+	if a.Like != "" && a.ownConstraints != nil {
+		constraints = a.ownConstraints
+	}
 
 	// Add constraints
-	for _, c := range a.Constraints {
+	for _, c := range constraints {
 		parts = append(parts, c.Name+":"+c.Value)
 	}
+	if a.Description != "" {
+		parts = append(parts, "desc:"+descriptionEscaper.Replace(a.Description))
+	}
+	envs := make([]string, 0, len(a.EnvDefaults))
+	for env := range a.EnvDefaults {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	for _, env := range envs {
+		parts = append(parts, "default@"+env+":"+a.EnvDefaults[env])
+	}
 
 	// Add modifiers
 	if a.IsOptional {
@@ -257,6 +660,9 @@ func FormatAnnotation(a *Annotation) string {
 	if a.IsSecret {
 		parts = append(parts, "secret")
 	}
+	if a.NoCase {
+		parts = append(parts, "nocase")
+	}
 
 	return "#prompt:" + a.PromptText + "|" + strings.Join(parts, ";")
 }
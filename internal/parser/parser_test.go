@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -16,6 +19,7 @@ func TestParseAnnotation(t *testing.T) {
 		wantType       VariableType
 		wantOptional   bool
 		wantSecret     bool
+		wantDesc       string
 		wantConstraint map[string]string
 		wantErr        bool
 	}{
@@ -69,6 +73,15 @@ func TestParseAnnotation(t *testing.T) {
 				"minlen": "32",
 			},
 		},
+		{
+			name:       "url with schemes",
+			input:      "#prompt:API?|url;schemes:https",
+			wantPrompt: "API?",
+			wantType:   TypeURL,
+			wantConstraint: map[string]string{
+				"schemes": "https",
+			},
+		},
 		{
 			name:       "object with format",
 			input:      "#prompt:Config?|object;format:json",
@@ -97,6 +110,25 @@ func TestParseAnnotation(t *testing.T) {
 				"pattern": "^[a-z]+@[a-z]+\\.[a-z]+$",
 			},
 		},
+		{
+			name:       "enum with nocase",
+			input:      "#prompt:Level?|enum;options:debug,info;nocase",
+			wantPrompt: "Level?",
+			wantType:   TypeEnum,
+			wantConstraint: map[string]string{
+				"options": "debug,info",
+			},
+		},
+		{
+			name:       "int with description",
+			input:      "#prompt:Port?|int;min:1;desc:TCP port the server binds to",
+			wantPrompt: "Port?",
+			wantType:   TypeInt,
+			wantDesc:   "TCP port the server binds to",
+			wantConstraint: map[string]string{
+				"min": "1",
+			},
+		},
 		{
 			name:    "invalid format",
 			input:   "#prompt:Value?",
@@ -123,6 +155,7 @@ func TestParseAnnotation(t *testing.T) {
 			assert.Equal(t, tt.wantType, ann.Type)
 			assert.Equal(t, tt.wantOptional, ann.IsOptional)
 			assert.Equal(t, tt.wantSecret, ann.IsSecret)
+			assert.Equal(t, tt.wantDesc, ann.Description)
 
 			for name, value := range tt.wantConstraint {
 				assert.Equal(t, value, ann.GetConstraint(name), "constraint %s", name)
@@ -204,6 +237,18 @@ func TestParseEnvFile_CommentsOnly(t *testing.T) {
 	assert.Len(t, envFile.Comments, 2)
 }
 
+func TestParseEnvFile_CommentedAnnotatedExample(t *testing.T) {
+	input := `#   PORT=3000 #prompt:Server port?|int
+# DEBUG= #prompt:Debug?|boolean
+`
+	envFile, err := ParseEnvFileContent(input, "example.env")
+	require.NoError(t, err)
+	assert.Empty(t, envFile.Variables)
+	assert.Empty(t, envFile.Warnings)
+	assert.Len(t, envFile.Comments, 2)
+	assert.Equal(t, LineComment, envFile.Lines[0].Kind)
+}
+
 func TestParseEnvFile_LineNumbers(t *testing.T) {
 	input := `# Comment on line 1
 DB_HOST=localhost
@@ -222,6 +267,22 @@ DB_PORT=5432
 	assert.Equal(t, 4, dbPort.LineNumber)
 }
 
+func TestParseEnvFile_LineStructure(t *testing.T) {
+	input := `#krakenv:strict=true
+
+# Section
+DB_HOST=localhost`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Line{
+		{Kind: LineConfig, Text: "#krakenv:strict=true"},
+		{Kind: LineBlank},
+		{Kind: LineComment, Text: "# Section"},
+		{Kind: LineVariable, Text: "DB_HOST=localhost", Name: "DB_HOST"},
+	}, envFile.Lines)
+}
+
 func TestParseEnvFile_PreservesOrder(t *testing.T) {
 	input := `VAR_C=3
 VAR_A=1
@@ -283,6 +344,147 @@ func TestParseAnnotation_EmptyEnumOptions(t *testing.T) {
 	assert.Equal(t, TypeString, ann.Type)
 }
 
+func TestParseAnnotation_EnvDefaults(t *testing.T) {
+	ann, err := ParseAnnotation("#prompt:Level?|enum;options:debug,info,warn;default@production:warn;default@local:debug")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"production": "warn", "local": "debug"}, ann.EnvDefaults)
+	assert.Empty(t, ann.GetConstraint("default@production"))
+
+	value, ok := ann.EnvDefault("production")
+	assert.True(t, ok)
+	assert.Equal(t, "warn", value)
+	_, ok = ann.EnvDefault("staging")
+	assert.False(t, ok)
+	_, ok = ann.EnvDefault("")
+	assert.False(t, ok)
+
+	assert.Equal(t, "#prompt:Level?|enum;options:debug,info,warn;default@local:debug;default@production:warn",
+		FormatAnnotation(ann))
+}
+
+func TestParseEnvFile_Like(t *testing.T) {
+	input := `API_PORT= #prompt:API port?|like:DB_PORT;max:9000
+DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535
+ADMIN_PORT= #prompt:Admin port?|like:API_PORT;optional
+LEVEL=info #prompt:Level?|enum;options:debug,info
+LOG_LEVEL= #prompt:Log level?|enum;like:LEVEL
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	api := envFile.GetVariable("API_PORT").Annotation
+	assert.Equal(t, TypeInt, api.Type)
+	assert.Equal(t, "DB_PORT", api.Like)
+	assert.Equal(t, []Constraint{{Name: "min", Value: "1"}, {Name: "max", Value: "9000"}}, api.Constraints)
+
+	// Chained through API_PORT, with its own modifiers
+	admin := envFile.GetVariable("ADMIN_PORT").Annotation
+	assert.Equal(t, TypeInt, admin.Type)
+	assert.Equal(t, "9000", admin.GetConstraint("max"))
+	assert.True(t, admin.IsOptional)
+
+	// Enum options come from the base
+	logLevel := envFile.GetVariable("LOG_LEVEL").Annotation
+	assert.Equal(t, TypeEnum, logLevel.Type)
+	assert.Equal(t, "debug,info", logLevel.GetConstraint("options"))
+
+	// The base is not changed
+	assert.Equal(t, "65535", envFile.GetVariable("DB_PORT").Annotation.GetConstraint("max"))
+}
+
+func TestFormatAnnotation_LikeRoundTrip(t *testing.T) {
+	input := `API_PORT= #prompt:API port?|like:DB_PORT;max:9000
+DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535
+ADMIN_PORT= #prompt:Admin port?|like:API_PORT;optional
+LEVEL=info #prompt:Level?|enum;options:debug,info
+LOG_LEVEL= #prompt:Log level?|enum;like:LEVEL
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"API_PORT", "#prompt:API port?|like:DB_PORT;max:9000"},
+		{"ADMIN_PORT", "#prompt:Admin port?|like:API_PORT;optional"},
+		{"LOG_LEVEL", "#prompt:Log level?|enum;like:LEVEL"},
+		{"DB_PORT", "#prompt:Database port?|int;min:1;max:65535"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatAnnotation(envFile.GetVariable(tt.name).Annotation))
+		})
+	}
+
+	// Writing and parsing again keeps the references and what they resolve to
+	reparsed, err := ParseEnvFileContent(FormatEnvFile(envFile), "test.env")
+	require.NoError(t, err)
+	for _, v := range envFile.Variables {
+		again := reparsed.GetVariable(v.Name).Annotation
+		assert.Equal(t, v.Annotation.Like, again.Like, v.Name)
+		assert.Equal(t, v.Annotation.Type, again.Type, v.Name)
+		assert.Equal(t, v.Annotation.Constraints, again.Constraints, v.Name)
+	}
+}
+
+func TestResolveLikes_AcrossFiles(t *testing.T) {
+	base, err := ParseEnvFileContent("DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535\n", "base.env.dist",
+		ParseOptions{SkipLikes: true})
+	require.NoError(t, err)
+	service, err := ParseEnvFileContent("API_PORT= #prompt:API port?|like:DB_PORT;max:9000\n", "service.env.dist",
+		ParseOptions{SkipLikes: true})
+	require.NoError(t, err)
+
+	merged := MergeEnvFiles(base, service)
+	require.NoError(t, ResolveLikes(merged))
+
+	api := merged.GetVariable("API_PORT").Annotation
+	assert.Equal(t, TypeInt, api.Type)
+	assert.Equal(t, []Constraint{{Name: "min", Value: "1"}, {Name: "max", Value: "9000"}}, api.Constraints)
+
+	// On its own, the service file has nothing to resolve the reference with
+	_, err = ParseEnvFileContent("API_PORT= #prompt:API port?|like:DB_PORT\n", "service.env.dist")
+	assert.ErrorIs(t, err, ErrUndefinedLike)
+}
+
+func TestResolveLikes_Context(t *testing.T) {
+	base, err := ParseEnvFileContent("DB_PORT=5432 #prompt:Database port?|int;min:1;max:65535\n", "base.env.dist",
+		ParseOptions{SkipLikes: true})
+	require.NoError(t, err)
+	service, err := ParseEnvFileContent(
+		"API_PORT= #prompt:API port?|like:DB_PORT;max:9000\nDB_PORT= #prompt:Local port?|int;max:80\nWEB_PORT= #prompt:Web?|like:DB_PORT\n",
+		"service.env.dist", ParseOptions{SkipLikes: true})
+	require.NoError(t, err)
+
+	require.NoError(t, ResolveLikes(service, base))
+
+	// The file's own DB_PORT takes precedence over the context's
+	api := service.GetVariable("API_PORT").Annotation
+	assert.Equal(t, TypeInt, api.Type)
+	assert.Equal(t, []Constraint{{Name: "max", Value: "9000"}}, api.Constraints)
+	assert.Equal(t, []Constraint{{Name: "max", Value: "80"}}, service.GetVariable("WEB_PORT").Annotation.Constraints)
+
+	other, err := ParseEnvFileContent("CACHE_PORT= #prompt:Cache?|like:DB_PORT\n", "cache.env.dist",
+		ParseOptions{SkipLikes: true})
+	require.NoError(t, err)
+	require.NoError(t, ResolveLikes(other, base))
+	assert.Equal(t, "65535", other.GetVariable("CACHE_PORT").Annotation.GetConstraint("max"))
+}
+
+func TestParseEnvFile_LikeErrors(t *testing.T) {
+	_, err := ParseEnvFileContent("A= #prompt:A?|like:MISSING\n", "test.env")
+	assert.ErrorIs(t, err, ErrUndefinedLike)
+
+	_, err = ParseEnvFileContent("A= #prompt:A?|like:PLAIN\nPLAIN=x\n", "test.env")
+	assert.ErrorIs(t, err, ErrUndefinedLike)
+
+	_, err = ParseEnvFileContent("A= #prompt:A?|like:B\nB= #prompt:B?|like:A\n", "test.env")
+	assert.ErrorIs(t, err, ErrLikeCycle)
+	assert.Contains(t, err.Error(), "A -> B -> A")
+}
+
 func TestParseEnvFile_WhitespaceValue(t *testing.T) {
 	// Per FR-040: Whitespace-only values should be treated as empty
 	input := `VAR_A=   
@@ -300,6 +502,92 @@ VAR_B=  hello
 	assert.Equal(t, "hello", varB.Value) // Trimmed
 }
 
+func TestParseEnvFile_KeepWhitespace(t *testing.T) {
+	input := `PROMPT_PREFIX="> "` + "\n" +
+		`PADDED='  both  '` + "\n" +
+		"BARE=value  \n" +
+		`ANNOTATED=" x " #prompt:X?|string` + "\n" +
+		"SEPARATED=value   #prompt:S?|string\n" +
+		"COMMENTED=value \t# note\n" +
+		"CRLF= value \r\n"
+	want := map[string]string{
+		"PROMPT_PREFIX": "> ",
+		"PADDED":        "  both  ",
+		"BARE":          "value  ",
+		"ANNOTATED":     " x ",
+		"SEPARATED":     "value",
+		"COMMENTED":     "value",
+		"CRLF":          " value ",
+	}
+
+	t.Run("config directive", func(t *testing.T) {
+		envFile, err := ParseEnvFileContent("#krakenv:trim=false\n"+input, "test.env")
+		require.NoError(t, err)
+
+		require.NotNil(t, envFile.Config)
+		assert.True(t, envFile.Config.KeepWhitespace)
+		for name, value := range want {
+			assert.Equal(t, value, envFile.GetVariable(name).Value, name)
+		}
+	})
+
+	t.Run("parse option", func(t *testing.T) {
+		envFile, err := ParseEnvFileContent(input, "test.env", ParseOptions{KeepWhitespace: true})
+		require.NoError(t, err)
+
+		for name, value := range want {
+			assert.Equal(t, value, envFile.GetVariable(name).Value, name)
+		}
+		assert.NotNil(t, envFile.GetVariable("ANNOTATED").Annotation)
+	})
+
+	t.Run("default trims", func(t *testing.T) {
+		envFile, err := ParseEnvFileContent(input, "test.env")
+		require.NoError(t, err)
+
+		assert.Equal(t, ">", envFile.GetVariable("PROMPT_PREFIX").Value)
+		assert.Equal(t, "both", envFile.GetVariable("PADDED").Value)
+	})
+
+	t.Run("directive after the values", func(t *testing.T) {
+		envFile, err := ParseEnvFileStream(strings.NewReader(input+"#krakenv:trim=false\n"), "test.env")
+		require.NoError(t, err)
+
+		for name, value := range want {
+			assert.Equal(t, value, envFile.GetVariable(name).Value, name)
+		}
+	})
+}
+
+func TestParseEnvFileStream(t *testing.T) {
+	input := "#krakenv:environments=local\n" +
+		"# Certificate for the API\n" +
+		"#prompt:Cert?|string;encoding:heredoc\n" +
+		"TLS_CERT=<<EOF\n" +
+		"  -----BEGIN-----  \n" +
+		"EOF\n" +
+		"PORT= 8080  #prompt:Port?|int\n" +
+		"PORT=9090\n"
+
+	streamed, err := ParseEnvFileStream(strings.NewReader(input), "test.env")
+	require.NoError(t, err)
+
+	assert.Equal(t, "  -----BEGIN-----  ", streamed.GetVariable("TLS_CERT").Value)
+	assert.True(t, streamed.GetVariable("TLS_CERT").AnnotationAbove)
+	assert.Equal(t, 4, streamed.GetVariable("TLS_CERT").LineNumber)
+	assert.Equal(t, "9090", streamed.GetVariable("PORT").Value)
+	require.Len(t, streamed.Duplicates, 1)
+	assert.Equal(t, 8, streamed.Duplicates[0].DuplicateLine)
+
+	// Same result as parsing the whole content, less the trailing blank line
+	whole, err := ParseEnvFileContent(strings.TrimSuffix(input, "\n"), "test.env")
+	require.NoError(t, err)
+	assert.Equal(t, whole, streamed)
+
+	_, err = ParseEnvFileStream(strings.NewReader("KEY=<<EOF\nbody\n"), "test.env")
+	assert.ErrorIs(t, err, ErrUnterminatedHeredoc)
+}
+
 func TestParseEnvFile_DuplicateVariables(t *testing.T) {
 	// Duplicate variables: last definition wins
 	input := `DB_HOST=first
@@ -312,6 +600,11 @@ DB_HOST=second
 	dbHost := envFile.GetVariable("DB_HOST")
 	require.NotNil(t, dbHost)
 	assert.Equal(t, "second", dbHost.Value)
+
+	// The duplicate is recorded with both line numbers
+	assert.Equal(t, []DuplicateInfo{
+		{Name: "DB_HOST", FirstLine: 1, DuplicateLine: 2},
+	}, envFile.Duplicates)
 }
 
 func TestAnnotation_EncodingConstraint(t *testing.T) {
@@ -341,6 +634,239 @@ func TestAnnotation_EncodingConstraint(t *testing.T) {
 	}
 }
 
+func TestParseEnvFile_Heredoc(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantValue string
+	}{
+		{
+			name: "two-line body",
+			input: `CERT=<<EOF #prompt:Certificate?|string;encoding:heredoc
+-----BEGIN CERTIFICATE-----
+  MIIBszCCAVmgAwIBAgIU
+EOF
+AFTER=1
+`,
+			wantValue: "-----BEGIN CERTIFICATE-----\n  MIIBszCCAVmgAwIBAgIU",
+		},
+		{
+			name: "empty body",
+			input: `CERT=<<END
+END
+AFTER=1
+`,
+			wantValue: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envFile, err := ParseEnvFileContent(tt.input, "test.env")
+			require.NoError(t, err)
+			require.Len(t, envFile.Variables, 2)
+
+			cert := envFile.GetVariable("CERT")
+			require.NotNil(t, cert)
+			assert.Equal(t, tt.wantValue, cert.Value)
+			assert.Equal(t, 1, cert.LineNumber)
+
+			after := envFile.GetVariable("AFTER")
+			require.NotNil(t, after)
+			assert.Equal(t, "1", after.Value)
+		})
+	}
+}
+
+func TestParseEnvFile_UnterminatedHeredoc(t *testing.T) {
+	_, err := ParseEnvFileContent("CERT=<<EOF\nline\n", "test.env")
+	assert.ErrorIs(t, err, ErrUnterminatedHeredoc)
+}
+
+func TestFormatVariable_HeredocRoundTrip(t *testing.T) {
+	ann, err := ParseAnnotation("#prompt:Script?|string;encoding:heredoc")
+	require.NoError(t, err)
+
+	v := Variable{Name: "SCRIPT", Value: "echo one\nEOF\necho two", Annotation: ann}
+	formatted := FormatVariable(v, true)
+	assert.Equal(t, "SCRIPT=<<EOF1 #prompt:Script?|string;encoding:heredoc\necho one\nEOF\necho two\nEOF1", formatted)
+
+	envFile, err := ParseEnvFileContent(formatted+"\n", "test.env")
+	require.NoError(t, err)
+	parsed := envFile.GetVariable("SCRIPT")
+	require.NotNil(t, parsed)
+	assert.Equal(t, v.Value, parsed.Value)
+}
+
+func TestFormatAnnotation_DescriptionRoundTrip(t *testing.T) {
+	input := "#prompt:Port?|int;min:1;desc:TCP port the server binds to;optional"
+	ann, err := ParseAnnotation(input)
+	require.NoError(t, err)
+
+	formatted := FormatAnnotation(ann)
+	assert.Equal(t, input, formatted)
+
+	reparsed, err := ParseAnnotation(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, ann, reparsed)
+}
+
+func TestFormatAnnotation_DescriptionSeparators(t *testing.T) {
+	ann := &Annotation{
+		PromptText:  "Mode?",
+		Type:        TypeEnum,
+		Constraints: []Constraint{{Name: "options", Value: "a,b"}},
+		Description: "a; the default | b: faster",
+		IsOptional:  true,
+	}
+
+	formatted := FormatAnnotation(ann)
+	assert.Equal(t, `#prompt:Mode?|enum;options:a,b;desc:a\; the default \| b: faster;optional`, formatted)
+
+	reparsed, warnings, err := parseAnnotation(formatted)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, ann.Description, reparsed.Description)
+	assert.Equal(t, "a,b", reparsed.GetConstraint("options"))
+	assert.True(t, reparsed.IsOptional)
+}
+
+func TestParseEnvFile_Warnings(t *testing.T) {
+	input := `GOOD=1 #prompt:Good?|int;min:0
+BROKEN= #prompt:Missing separator
+TYPO= #prompt:Value?|int;mn:1;optinal
+`
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	require.Len(t, envFile.Warnings, 3)
+	assert.Equal(t, ParseWarning{Code: WarnInvalidAnnotation, Variable: "BROKEN", LineNumber: 2,
+		Message: "annotation ignored: invalid annotation syntax: missing | separator"}, envFile.Warnings[0])
+	assert.Equal(t, WarnUnknownConstraint, envFile.Warnings[1].Code)
+	assert.Equal(t, 3, envFile.Warnings[1].LineNumber)
+	assert.Equal(t, WarnUnknownModifier, envFile.Warnings[2].Code)
+
+	// The lenient parse still keeps the variables
+	assert.True(t, envFile.HasVariable("BROKEN"))
+	assert.Nil(t, envFile.GetVariable("BROKEN").Annotation)
+	assert.Equal(t, TypeInt, envFile.GetVariable("TYPO").Annotation.Type)
+}
+
+func TestParseEnvFile_UnparsedLineWarnings(t *testing.T) {
+	input := "GOOD=1\nlower_case=2\njust some text\n"
+	envFile, err := ParseEnvFileContent(input, "test.env")
+	require.NoError(t, err)
+
+	require.Len(t, envFile.Warnings, 2)
+	assert.Equal(t, WarnInvalidName, envFile.Warnings[0].Code)
+	assert.Equal(t, "lower_case", envFile.Warnings[0].Variable)
+	assert.Equal(t, 2, envFile.Warnings[0].LineNumber)
+	assert.Equal(t, WarnUnparsedLine, envFile.Warnings[1].Code)
+	assert.Equal(t, "line 3: line is not NAME=value, ignored", envFile.Warnings[1].String())
+}
+
+func TestParseEnvFile_AnnotationAbove(t *testing.T) {
+	content := `#prompt:Port?|int;min:1
+PORT=5432
+#prompt:Host?|string
+HOST=localhost #prompt:Inline host?|hostname
+#prompt:Orphan?|string
+
+NAME=app
+`
+	envFile, err := ParseEnvFileContent(content, ".env.dist")
+	require.NoError(t, err)
+
+	port := envFile.GetVariable("PORT")
+	require.NotNil(t, port)
+	require.NotNil(t, port.Annotation)
+	assert.Equal(t, "Port?", port.Annotation.PromptText)
+	assert.Equal(t, TypeInt, port.Annotation.Type)
+	assert.Equal(t, "1", port.Annotation.GetConstraint("min"))
+	assert.True(t, port.AnnotationAbove)
+	assert.Equal(t, 2, port.LineNumber)
+
+	// The inline annotation wins over the line above
+	host := envFile.GetVariable("HOST")
+	require.NotNil(t, host.Annotation)
+	assert.Equal(t, "Inline host?", host.Annotation.PromptText)
+	assert.False(t, host.AnnotationAbove)
+
+	assert.Nil(t, envFile.GetVariable("NAME").Annotation)
+	assert.Empty(t, envFile.Comments)
+	assert.Equal(t, LineAnnotation, envFile.Lines[0].Kind)
+
+	require.Len(t, envFile.Warnings, 2)
+	assert.Equal(t, WarnOrphanAnnotation, envFile.Warnings[0].Code)
+	assert.Equal(t, 3, envFile.Warnings[0].LineNumber)
+	assert.Equal(t, WarnOrphanAnnotation, envFile.Warnings[1].Code)
+	assert.Equal(t, 5, envFile.Warnings[1].LineNumber)
+}
+
+func TestFormatVariableAbove(t *testing.T) {
+	v := Variable{
+		Name:       "PORT",
+		Value:      "5432",
+		Annotation: &Annotation{PromptText: "Port?", Type: TypeInt},
+	}
+	assert.Equal(t, "#prompt:Port?|int\nPORT=5432", FormatVariableAbove(v))
+
+	v.Annotation = nil
+	assert.Equal(t, "PORT=5432", FormatVariableAbove(v))
+}
+
+func TestParseEnvFileStrict(t *testing.T) {
+	dir := t.TempDir()
+
+	clean := filepath.Join(dir, "clean.env")
+	require.NoError(t, os.WriteFile(clean, []byte("A=1 #prompt:A?|int\n"), 0644))
+	envFile, err := ParseEnvFileStrict(clean)
+	require.NoError(t, err)
+	assert.True(t, envFile.HasVariable("A"))
+
+	noisy := filepath.Join(dir, "noisy.env")
+	require.NoError(t, os.WriteFile(noisy, []byte("A=1 #prompt:A?|int;mn:1\nbad=2\n"), 0644))
+	envFile, err = ParseEnvFileStrict(noisy)
+	require.NotNil(t, envFile)
+
+	var warnErr *ParseWarningsError
+	require.ErrorAs(t, err, &warnErr)
+	assert.Len(t, warnErr.Warnings, 2)
+	assert.Contains(t, err.Error(), "(and 1 more warning(s))")
+
+	// The lenient parser reports the same warnings without failing
+	lenient, err := ParseEnvFile(noisy)
+	require.NoError(t, err)
+	assert.Equal(t, warnErr.Warnings, lenient.Warnings)
+}
+
+// BenchmarkParseEnvFile_Large compares memory use of streaming a large file
+// with reading it whole before parsing. Run with -benchmem.
+func BenchmarkParseEnvFile_Large(b *testing.B) {
+	var builder strings.Builder
+	builder.WriteString("#krakenv:environments=local,prod\n\n")
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&builder, "VAR_%d=value_%d #prompt:Question %d?|string\n", i, i, i)
+	}
+	path := filepath.Join(b.TempDir(), ".env.large")
+	require.NoError(b, os.WriteFile(path, []byte(builder.String()), 0644))
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ParseEnvFile(path)
+		}
+	})
+
+	b.Run("whole", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			content, _ := os.ReadFile(path)
+			_, _ = ParseEnvFileContent(string(content), path)
+		}
+	})
+}
+
 func BenchmarkParseEnvFile(b *testing.B) {
 	// Create a large file for benchmarking
 	var builder strings.Builder
@@ -1,6 +1,8 @@
 // Package parser provides functionality for parsing .env files and annotations.
 package parser
 
+import "fmt"
+
 // VariableType represents the type of a variable value.
 type VariableType int
 
@@ -17,6 +19,24 @@ const (
 	TypeEnum
 	// TypeObject represents a structured object type (JSON/YAML).
 	TypeObject
+	// TypeURL represents an absolute URL with a host.
+	TypeURL
+	// TypeEmail represents a single email address.
+	TypeEmail
+	// TypeDuration represents a Go duration such as 30s or 5m.
+	TypeDuration
+	// TypeIP represents an IPv4 or IPv6 address.
+	TypeIP
+	// TypeCIDR represents an IP network in CIDR notation.
+	TypeCIDR
+	// TypeSemver represents a semantic version such as 1.2.3.
+	TypeSemver
+	// TypePath represents a filesystem path to a file or directory.
+	TypePath
+	// TypeHostname represents an RFC 1123 hostname such as smtp.example.com.
+	TypeHostname
+	// TypeDate represents a calendar date parsed with a time layout.
+	TypeDate
 )
 
 // String returns the string representation of a VariableType.
@@ -34,6 +54,24 @@ func (t VariableType) String() string {
 		return "enum"
 	case TypeObject:
 		return "object"
+	case TypeURL:
+		return "url"
+	case TypeEmail:
+		return "email"
+	case TypeDuration:
+		return "duration"
+	case TypeIP:
+		return "ip"
+	case TypeCIDR:
+		return "cidr"
+	case TypeSemver:
+		return "semver"
+	case TypePath:
+		return "path"
+	case TypeHostname:
+		return "hostname"
+	case TypeDate:
+		return "date"
 	default:
 		return "unknown"
 	}
@@ -54,6 +92,24 @@ func ParseVariableType(s string) VariableType {
 		return TypeEnum
 	case "object":
 		return TypeObject
+	case "url":
+		return TypeURL
+	case "email":
+		return TypeEmail
+	case "duration":
+		return TypeDuration
+	case "ip":
+		return TypeIP
+	case "cidr":
+		return TypeCIDR
+	case "semver":
+		return TypeSemver
+	case "path":
+		return TypePath
+	case "hostname":
+		return TypeHostname
+	case "date":
+		return TypeDate
 	default:
 		return TypeString // Default to string if unknown
 	}
@@ -61,17 +117,25 @@ func ParseVariableType(s string) VariableType {
 
 // Constraint represents a validation constraint attached to an annotation.
 type Constraint struct {
-	Name  string // "min", "max", "minlen", "maxlen", "pattern", "options", "format", "encoding"
+	Name  string // "min", "max", "minlen", "maxlen", "pattern", "options", "format", "encoding", "schemes", "allowName", "error", "lenmode", "envdefault", "schema", "exclusiveMin", "exclusiveMax", "desc", "step", "version", "vprefix", "requires", "conflicts", "exists", "kind", "abs", "allowip", "pattern-desc", "layout", "after", "before", "entropy", "output", "like"
 	Value string // Raw string value; parsed per constraint type
 }
 
 // Annotation represents metadata extracted from an inline comment on a variable line.
 type Annotation struct {
-	PromptText  string       // The question to ask the user
-	Type        VariableType // The type of variable
-	Constraints []Constraint // Validation constraints
-	IsOptional  bool         // Whether the variable is optional
-	IsSecret    bool         // Whether to hide input/output
+	PromptText  string            // The question to ask the user
+	Description string            // Longer explanation of the variable, from the desc constraint
+	Type        VariableType      // The type of variable
+	Constraints []Constraint      // Validation constraints
+	IsOptional  bool              // Whether the variable is optional
+	IsSecret    bool              // Whether to hide input/output
+	NoCase      bool              // Whether enum options match case-insensitively
+	BaseDir     string            // Directory of the source file, for resolving relative paths
+	EnvDefaults map[string]string // Per-environment defaults from default@<env> constraints
+	Like        string            // Variable whose type and constraints this annotation extends
+
+	inheritType    bool         // The type is taken from the Like variable
+	ownConstraints []Constraint // With Like, the constraints written on this annotation itself
 }
 
 // GetConstraint returns the constraint value for a given name, or empty string if not found.
@@ -84,6 +148,16 @@ func (a *Annotation) GetConstraint(name string) string {
 	return ""
 }
 
+// EnvDefault returns the default for the named environment from a
+// default@<env> constraint, and whether there is one.
+func (a *Annotation) EnvDefault(env string) (string, bool) {
+	if env == "" {
+		return "", false
+	}
+	value, ok := a.EnvDefaults[env]
+	return value, ok
+}
+
 // HasConstraint checks if the annotation has a specific constraint.
 func (a *Annotation) HasConstraint(name string) bool {
 	for _, c := range a.Constraints {
@@ -96,11 +170,14 @@ func (a *Annotation) HasConstraint(name string) bool {
 
 // Variable represents a single environment variable with optional annotation.
 type Variable struct {
-	Name       string      // Variable name (e.g., "DB_HOST")
-	Value      string      // Variable value (may be empty string)
-	Annotation *Annotation // nil if no annotation present
-	LineNumber int         // 1-indexed line number in source file
-	IsSet      bool        // true if value was explicitly set (vs undefined)
+	Name            string      // Variable name (e.g., "DB_HOST")
+	Value           string      // Variable value (may be empty string)
+	Annotation      *Annotation // nil if no annotation present
+	LineNumber      int         // 1-indexed line number in source file
+	IsSet           bool        // true if value was explicitly set (vs undefined)
+	References      []string    // Names referenced as ${VAR} in the value
+	AnnotationAbove bool        // Annotation was written on its own line above the variable
+	InlineComment   string      // Trailing "# note" after the value, without the '#'
 }
 
 // Comment represents a standalone comment line (not attached to a variable).
@@ -109,12 +186,91 @@ type Comment struct {
 	LineNumber int    // 1-indexed line number in source file
 }
 
+// LineKind identifies what a source line contained.
+type LineKind int
+
+const (
+	// LineBlank is an empty or whitespace-only line.
+	LineBlank LineKind = iota
+	// LineComment is a standalone comment line.
+	LineComment
+	// LineConfig is a #krakenv: configuration line.
+	LineConfig
+	// LineVariable is a variable definition.
+	LineVariable
+	// LineUnparsed is a line the parser ignored, such as an invalid variable name.
+	LineUnparsed
+	// LineAnnotation is a #prompt: annotation on its own line, applied to the
+	// variable on the next line.
+	LineAnnotation
+)
+
+// Line records the structure of a single source line so writers can
+// reproduce the original layout.
+type Line struct {
+	Kind LineKind // What the line contained
+	Text string   // Raw text; for heredoc variables, including body and terminator
+	Name string   // Variable name (variable lines)
+}
+
+// DuplicateInfo records a variable defined more than once in a file.
+type DuplicateInfo struct {
+	Name          string // Variable name
+	FirstLine     int    // Line of the first definition
+	DuplicateLine int    // Line of the later definition that overrides it
+}
+
+// Parse warning codes.
+const (
+	WarnInvalidAnnotation = "invalid-annotation" // Annotation could not be parsed and was dropped
+	WarnUnknownConstraint = "unknown-constraint" // Constraint name not recognized and ignored
+	WarnUnknownModifier   = "unknown-modifier"   // Modifier not recognized and ignored
+	WarnInvalidName       = "invalid-name"       // Variable line skipped because of its name
+	WarnUnparsedLine      = "unparsed-line"      // Line is not a comment, config or NAME=value
+	WarnOrphanAnnotation  = "orphan-annotation"  // Annotation line not followed by a variable it can apply to
+)
+
+// ParseWarning describes input the parser ignored instead of failing on.
+type ParseWarning struct {
+	Code       string // One of the Warn* codes
+	Variable   string // Variable the warning refers to (may be empty)
+	LineNumber int    // Line number in source file (1-indexed)
+	Message    string // User-friendly description
+}
+
+// String formats the warning as "line N: NAME: message".
+func (w ParseWarning) String() string {
+	if w.Variable == "" {
+		return fmt.Sprintf("line %d: %s", w.LineNumber, w.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", w.LineNumber, w.Variable, w.Message)
+}
+
+// ParseWarningsError is returned by ParseEnvFileStrict when the file parsed
+// but had warnings.
+type ParseWarningsError struct {
+	Path     string
+	Warnings []ParseWarning
+}
+
+// Error implements the error interface.
+func (e *ParseWarningsError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Path, e.Warnings[0])
+	if n := len(e.Warnings) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more warning(s))", n)
+	}
+	return msg
+}
+
 // EnvFile represents a parsed .env or .env.dist file.
 type EnvFile struct {
-	Path      string         // File path
-	Variables []Variable     // Variables in order of appearance
-	Config    *KrakenvConfig // Krakenv configuration (nil if not a distributable)
-	Comments  []Comment      // Standalone comments
+	Path       string          // File path
+	Variables  []Variable      // Variables in order of appearance
+	Config     *KrakenvConfig  // Krakenv configuration (nil if not a distributable)
+	Comments   []Comment       // Standalone comments
+	Lines      []Line          // Original line structure, in order
+	Duplicates []DuplicateInfo // Variables defined more than once (last definition wins)
+	Warnings   []ParseWarning  // Problems the parser skipped over, in line order
 }
 
 // GetVariable returns a variable by name, or nil if not found.
@@ -134,9 +290,15 @@ func (f *EnvFile) HasVariable(name string) bool {
 
 // KrakenvConfig represents project-level krakenv configuration extracted from distributable.
 type KrakenvConfig struct {
-	Environments []string // e.g., ["local", "testing", "production"]
-	Strict       bool     // If true, unannotated variables are errors
-	DistPath     string   // Override default .env.dist path
+	Environments   []string // e.g., ["local", "testing", "production"]
+	Strict         bool     // If true, unannotated variables are errors
+	DistPath       string   // Override default .env.dist path
+	Ignore         []string // Glob patterns of variables expected in environments but not in the dist
+	KeepWhitespace bool     // Keep whitespace around values instead of trimming it (trim=false)
+
+	// Defaults maps variables of a generated target to the dist default
+	// their value was written from (defaults=).
+	Defaults map[string]string
 }
 
 // DefaultKrakenvConfig returns a KrakenvConfig with default values.
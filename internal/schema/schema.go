@@ -0,0 +1,223 @@
+// Package schema builds JSON Schema documents describing a distributable's variables.
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/validator"
+)
+
+// Output formats supported by Build.
+const (
+	FormatJSONSchema = "json-schema"
+	FormatOpenAPI    = "openapi"
+)
+
+// DraftURI identifies the JSON Schema dialect of generated documents.
+const DraftURI = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches Go durations as accepted by time.ParseDuration.
+const durationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h))+)$`
+
+// IsValidFormat reports whether format is a supported output format.
+func IsValidFormat(format string) bool {
+	return format == FormatJSONSchema || format == FormatOpenAPI
+}
+
+// Build returns a document describing the variables of a distributable, as a
+// JSON Schema or as an OpenAPI 3.1 document with the schema under
+// components.schemas. The title is used for the schema and API names.
+func Build(f *parser.EnvFile, title, format string) map[string]any {
+	s := Object(f)
+	s["title"] = title
+
+	if format == FormatOpenAPI {
+		return map[string]any{
+			"openapi": "3.1.0",
+			"info":    map[string]any{"title": title, "version": "1.0.0"},
+			"paths":   map[string]any{},
+			"components": map[string]any{
+				"schemas": map[string]any{"Environment": s},
+			},
+		}
+	}
+
+	s["$schema"] = DraftURI
+	return s
+}
+
+// Object returns an object schema with one property per variable. Annotated
+// variables that are not optional are required.
+func Object(f *parser.EnvFile) map[string]any {
+	properties := make(map[string]any, len(f.Variables))
+	required := make([]string, 0, len(f.Variables))
+
+	for _, v := range f.Variables {
+		properties[v.Name] = Property(v)
+		if v.Annotation != nil && !v.Annotation.IsOptional {
+			required = append(required, v.Name)
+		}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// Property returns the schema for a single variable.
+func Property(v parser.Variable) map[string]any {
+	ann := v.Annotation
+	if ann == nil {
+		p := map[string]any{"type": "string"}
+		if v.Value != "" {
+			p["default"] = v.Value
+		}
+		return p
+	}
+
+	p := typeSchema(ann)
+	if ann.PromptText != "" {
+		p["title"] = ann.PromptText
+	}
+	if ann.Description != "" {
+		p["description"] = ann.Description
+	}
+	if ann.IsSecret {
+		p["writeOnly"] = true
+	}
+	if v.Value != "" && !ann.IsSecret {
+		p["default"] = defaultValue(v.Value, ann)
+	}
+	return p
+}
+
+// typeSchema maps an annotation's type and constraints to JSON Schema keywords.
+func typeSchema(ann *parser.Annotation) map[string]any {
+	p := make(map[string]any)
+
+	switch ann.Type {
+	case parser.TypeInt:
+		p["type"] = "integer"
+		setNumber(p, "minimum", ann.GetConstraint("min"))
+		setNumber(p, "maximum", ann.GetConstraint("max"))
+		setNumber(p, "exclusiveMinimum", ann.GetConstraint("exclusiveMin"))
+		setNumber(p, "exclusiveMaximum", ann.GetConstraint("exclusiveMax"))
+		setNumber(p, "multipleOf", ann.GetConstraint("step"))
+	case parser.TypeNumeric:
+		p["type"] = "number"
+		setNumber(p, "minimum", ann.GetConstraint("min"))
+		setNumber(p, "maximum", ann.GetConstraint("max"))
+		setNumber(p, "exclusiveMinimum", ann.GetConstraint("exclusiveMin"))
+		setNumber(p, "exclusiveMaximum", ann.GetConstraint("exclusiveMax"))
+	case parser.TypeBoolean:
+		p["type"] = "boolean"
+	case parser.TypeEnum:
+		p["type"] = "string"
+		var options []string
+		for _, o := range strings.Split(ann.GetConstraint("options"), ",") {
+			options = append(options, strings.TrimSpace(o))
+		}
+		p["enum"] = options
+	case parser.TypeObject:
+		// Structured values are accepted as any object or array
+		p["type"] = []string{"object", "array"}
+	case parser.TypeURL:
+		p["type"] = "string"
+		p["format"] = "uri"
+	case parser.TypeEmail:
+		p["type"] = "string"
+		p["format"] = "email"
+	case parser.TypeDuration:
+		// JSON Schema's duration format is ISO 8601, so Go durations use a pattern
+		p["type"] = "string"
+		p["pattern"] = durationPattern
+	case parser.TypeIP:
+		p["type"] = "string"
+		switch ann.GetConstraint("version") {
+		case "4":
+			p["format"] = "ipv4"
+		case "6":
+			p["format"] = "ipv6"
+		default:
+			p["anyOf"] = []any{
+				map[string]any{"format": "ipv4"},
+				map[string]any{"format": "ipv6"},
+			}
+		}
+	case parser.TypeCIDR:
+		p["type"] = "string"
+		p["pattern"] = `^[0-9A-Fa-f.:]+/\d{1,3}$`
+	case parser.TypeSemver:
+		p["type"] = "string"
+		p["pattern"] = validator.SemverPattern
+		if ann.GetConstraint("vprefix") == "true" {
+			p["pattern"] = "^v?" + strings.TrimPrefix(validator.SemverPattern, "^")
+		}
+	case parser.TypeHostname:
+		p["type"] = "string"
+		if ann.GetConstraint("allowip") == "true" {
+			p["anyOf"] = []any{
+				map[string]any{"format": "hostname"},
+				map[string]any{"format": "ipv4"},
+				map[string]any{"format": "ipv6"},
+			}
+		} else {
+			p["format"] = "hostname"
+		}
+	case parser.TypeDate:
+		// Only the default layout matches JSON Schema's full-date format
+		p["type"] = "string"
+		if validator.DateLayout(ann) == validator.DefaultDateLayout {
+			p["format"] = "date"
+		}
+	default:
+		p["type"] = "string"
+		setNumber(p, "minLength", ann.GetConstraint("minlen"))
+		setNumber(p, "maxLength", ann.GetConstraint("maxlen"))
+		if pattern := ann.GetConstraint("pattern"); pattern != "" {
+			p["pattern"] = pattern
+		}
+	}
+
+	return p
+}
+
+// setNumber sets key to the numeric value of s, if it parses.
+func setNumber(p map[string]any, key, s string) {
+	if s == "" {
+		return
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		p[key] = n
+		return
+	}
+	if f, err := validator.ParseNumber(s); err == nil {
+		p[key] = f
+	}
+}
+
+// defaultValue converts a distributable default to the JSON type of its schema.
+func defaultValue(value string, ann *parser.Annotation) any {
+	switch ann.Type {
+	case parser.TypeInt:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case parser.TypeNumeric:
+		if f, err := validator.ParseNumber(value); err == nil {
+			return f
+		}
+	case parser.TypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "1", "on":
+			return true
+		case "false", "no", "0", "off":
+			return false
+		}
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+func TestBuild(t *testing.T) {
+	input := `PORT=8080 #prompt:Port?|int;min:1;max:65535;desc:TCP port the server binds to
+LOG_LEVEL=info #prompt:Level?|enum;options:debug,info,warn
+API_URL= #prompt:API?|url
+TIMEOUT=30s #prompt:Timeout?|duration;optional
+DEBUG=no #prompt:Debug?|boolean
+TOKEN=abc #prompt:Token?|string;minlen:3;pattern:^[a-z]+$;secret
+EXTRA=value
+`
+	envFile, err := parser.ParseEnvFileContent(input, ".env.dist")
+	require.NoError(t, err)
+
+	doc := Build(envFile, ".env.dist", FormatJSONSchema)
+	assert.Equal(t, DraftURI, doc["$schema"])
+	assert.Equal(t, "object", doc["type"])
+	assert.Equal(t, []string{"PORT", "LOG_LEVEL", "API_URL", "DEBUG", "TOKEN"}, doc["required"])
+
+	props := doc["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":        "integer",
+		"minimum":     int64(1),
+		"maximum":     int64(65535),
+		"default":     int64(8080),
+		"title":       "Port?",
+		"description": "TCP port the server binds to",
+	}, props["PORT"])
+	assert.Equal(t, []string{"debug", "info", "warn"}, props["LOG_LEVEL"].(map[string]any)["enum"])
+	assert.Equal(t, "uri", props["API_URL"].(map[string]any)["format"])
+	assert.Equal(t, false, props["DEBUG"].(map[string]any)["default"])
+	assert.Equal(t, map[string]any{"type": "string", "default": "value"}, props["EXTRA"])
+
+	token := props["TOKEN"].(map[string]any)
+	assert.Equal(t, int64(3), token["minLength"])
+	assert.Equal(t, "^[a-z]+$", token["pattern"])
+	assert.Equal(t, true, token["writeOnly"])
+	assert.NotContains(t, token, "default", "secret defaults are not published")
+
+	timeout := props["TIMEOUT"].(map[string]any)
+	assert.Regexp(t, regexp.MustCompile(timeout["pattern"].(string)), "1h30m")
+
+	_, err = json.Marshal(doc)
+	assert.NoError(t, err)
+}
+
+func TestBuild_OpenAPI(t *testing.T) {
+	envFile, err := parser.ParseEnvFileContent("PORT=80 #prompt:Port?|int\n", ".env.dist")
+	require.NoError(t, err)
+
+	doc := Build(envFile, "service", FormatOpenAPI)
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	env := schemas["Environment"].(map[string]any)
+	assert.Equal(t, "service", env["title"])
+	assert.NotContains(t, env, "$schema")
+}
@@ -0,0 +1,53 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Progress bar sizing.
+const (
+	MinProgressBarWidth = 10 // Narrowest bar worth drawing
+	MaxProgressBarWidth = 40 // Bars never grow wider than this
+)
+
+// Progress bar styles.
+var (
+	// ProgressFilledStyle for the completed part of a progress bar.
+	ProgressFilledStyle = lipgloss.NewStyle().
+				Foreground(ColorPrimary)
+
+	// ProgressEmptyStyle for the remaining part of a progress bar.
+	ProgressEmptyStyle = lipgloss.NewStyle().
+				Foreground(ColorMuted)
+)
+
+// RenderProgressBar renders a bar width cells wide with current out of total
+// filled. It returns an empty string if width is below MinProgressBarWidth or
+// total is not positive.
+func RenderProgressBar(current, total, width int) string {
+	if width < MinProgressBarWidth || total <= 0 {
+		return ""
+	}
+
+	current = max(0, min(current, total))
+	filled := current * width / total
+
+	return ProgressFilledStyle.Render(strings.Repeat("█", filled)) +
+		ProgressEmptyStyle.Render(strings.Repeat("░", width-filled))
+}
+
+// RenderProgress renders a text label followed by a progress bar sized to fit
+// termWidth. When the terminal is too narrow (or its width is not known yet)
+// only the label is returned.
+func RenderProgress(label string, current, total, termWidth int) string {
+	text := MutedStyle.Render(label)
+
+	width := min(termWidth-lipgloss.Width(label)-1, MaxProgressBarWidth)
+	bar := RenderProgressBar(current, total, width)
+	if bar == "" {
+		return text
+	}
+	return bar + " " + text
+}
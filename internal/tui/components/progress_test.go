@@ -0,0 +1,53 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    int
+		total      int
+		width      int
+		wantFilled int
+	}{
+		{"start", 0, 10, 20, 0},
+		{"partial", 3, 10, 20, 6},
+		{"rounds down", 1, 3, 10, 3},
+		{"complete", 10, 10, 20, 20},
+		{"over total", 12, 10, 20, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bar := RenderProgressBar(tt.current, tt.total, tt.width)
+			assert.Equal(t, tt.width, lipgloss.Width(bar))
+			assert.Equal(t, tt.wantFilled, strings.Count(bar, "█"))
+		})
+	}
+}
+
+func TestRenderProgressBar_TooNarrow(t *testing.T) {
+	assert.Empty(t, RenderProgressBar(1, 10, MinProgressBarWidth-1))
+	assert.Empty(t, RenderProgressBar(1, 0, 20))
+}
+
+func TestRenderProgress(t *testing.T) {
+	label := "Variable 3 of 10"
+	text := MutedStyle.Render(label)
+
+	// Unknown or narrow terminals get the text form only
+	assert.Equal(t, text, RenderProgress(label, 3, 10, 0))
+	assert.Equal(t, text, RenderProgress(label, 3, 10, 20))
+
+	wide := RenderProgress(label, 3, 10, 200)
+	assert.Equal(t, MaxProgressBarWidth+1+len(label), lipgloss.Width(wide))
+
+	fitted := RenderProgress(label, 3, 10, 40)
+	assert.Equal(t, 40, lipgloss.Width(fitted))
+}
@@ -7,12 +7,17 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// SelectModel provides a selectable list of options.
+// SelectModel provides a selectable list of options. Typing filters the
+// list to options containing the typed text; arrow keys move over the
+// filtered list, Backspace edits the filter and Esc clears it. Until a filter
+// is typed, j and k move the cursor as well.
 type SelectModel struct {
-	Options  []string
-	Cursor   int
-	Selected int
-	focused  bool
+	Options         []string
+	Cursor          int // Index into Options of the highlighted option
+	Selected        int
+	CaseInsensitive bool // Select matches options ignoring case
+	focused         bool
+	filter          string
 }
 
 // NewSelectModel creates a new select model with options.
@@ -38,27 +43,94 @@ func (m SelectModel) Update(msg tea.Msg) (SelectModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
+		key := msg.String()
+		navigating := m.filter == "" && (key == "j" || key == "k")
+		if msg.Type == tea.KeyRunes && !navigating {
+			m.setFilter(m.filter + string(msg.Runes))
+			return m, nil
+		}
+
+		visible := m.visible()
+		pos := indexOf(visible, m.Cursor)
+		switch key {
 		case "up", "k":
-			if m.Cursor > 0 {
-				m.Cursor--
+			if pos > 0 {
+				m.Cursor = visible[pos-1]
 			}
 		case "down", "j":
-			if m.Cursor < len(m.Options)-1 {
-				m.Cursor++
+			if pos >= 0 && pos < len(visible)-1 {
+				m.Cursor = visible[pos+1]
 			}
 		case "enter", " ":
-			m.Selected = m.Cursor
+			m.SelectCursor()
 		case "home":
-			m.Cursor = 0
+			if len(visible) > 0 {
+				m.Cursor = visible[0]
+			}
 		case "end":
-			m.Cursor = len(m.Options) - 1
+			if len(visible) > 0 {
+				m.Cursor = visible[len(visible)-1]
+			}
+		case "backspace":
+			if r := []rune(m.filter); len(r) > 0 {
+				m.setFilter(string(r[:len(r)-1]))
+			}
+		case "esc":
+			m.setFilter("")
 		}
 	}
 
 	return m, nil
 }
 
+// Filter returns the text typed to filter the options.
+func (m SelectModel) Filter() string {
+	return m.filter
+}
+
+// SelectCursor selects the highlighted option. Returns false if no option
+// is visible, such as when the filter matches nothing.
+func (m *SelectModel) SelectCursor() bool {
+	if indexOf(m.visible(), m.Cursor) < 0 {
+		return false
+	}
+	m.Selected = m.Cursor
+	return true
+}
+
+// setFilter changes the filter and moves the cursor to the first match if
+// the highlighted option is filtered out.
+func (m *SelectModel) setFilter(filter string) {
+	m.filter = filter
+	visible := m.visible()
+	if len(visible) > 0 && indexOf(visible, m.Cursor) < 0 {
+		m.Cursor = visible[0]
+	}
+}
+
+// visible returns the indexes of the options matching the filter, a
+// case-insensitive substring.
+func (m SelectModel) visible() []int {
+	needle := strings.ToLower(m.filter)
+	indexes := make([]int, 0, len(m.Options))
+	for i, opt := range m.Options {
+		if strings.Contains(strings.ToLower(opt), needle) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// indexOf returns the position of v in s, or -1.
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
 // View implements tea.Model.
 func (m SelectModel) View() string {
 	if len(m.Options) == 0 {
@@ -71,7 +143,18 @@ func (m SelectModel) View() string {
 	selectedStyle := lipgloss.NewStyle().Foreground(ColorSuccess).Bold(true)
 	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
 
-	for i, opt := range m.Options {
+	visible := m.visible()
+	if m.filter != "" {
+		b.WriteString(MutedStyle.Render("Filter: " + m.filter))
+		if len(visible) == 0 {
+			b.WriteString("\n" + MutedStyle.Render("No matching options (Esc to clear)"))
+			return b.String()
+		}
+		b.WriteString("\n")
+	}
+
+	for n, i := range visible {
+		opt := m.Options[i]
 		cursor := "  "
 		style := normalStyle
 
@@ -86,7 +169,7 @@ func (m SelectModel) View() string {
 
 		b.WriteString(cursor)
 		b.WriteString(style.Render(opt))
-		if i < len(m.Options)-1 {
+		if n < len(visible)-1 {
 			b.WriteString("\n")
 		}
 	}
@@ -127,20 +210,23 @@ func (m *SelectModel) SetOptions(options []string) {
 	m.Options = options
 	m.Cursor = 0
 	m.Selected = -1
+	m.filter = ""
 }
 
-// Reset clears the selection.
+// Reset clears the selection and the filter.
 func (m *SelectModel) Reset() {
 	m.Cursor = 0
 	m.Selected = -1
+	m.filter = ""
 }
 
-// Select programmatically selects an option by value.
+// Select programmatically selects an option by value, clearing the filter.
 func (m *SelectModel) Select(value string) bool {
 	for i, opt := range m.Options {
-		if opt == value {
+		if opt == value || (m.CaseInsensitive && strings.EqualFold(opt, value)) {
 			m.Selected = i
 			m.Cursor = i
+			m.filter = ""
 			return true
 		}
 	}
@@ -0,0 +1,71 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func typeKeys(m SelectModel, keys ...tea.KeyMsg) SelectModel {
+	for _, k := range keys {
+		m, _ = m.Update(k)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestSelectModel_Filter(t *testing.T) {
+	options := []string{"us-east-1", "us-west-2", "eu-west-1", "ap-south-1"}
+
+	t.Run("typing filters and moves the cursor to the first match", func(t *testing.T) {
+		m := typeKeys(NewSelectModel(options), runes("w"), runes("est"))
+
+		assert.Equal(t, "west", m.Filter())
+		assert.Equal(t, []int{1, 2}, m.visible())
+		assert.Equal(t, 1, m.Cursor)
+		assert.NotContains(t, m.View(), "us-east-1")
+		assert.Contains(t, m.View(), "eu-west-1")
+	})
+
+	t.Run("arrows move over the filtered list", func(t *testing.T) {
+		m := typeKeys(NewSelectModel(options), runes("west"),
+			tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+		assert.Equal(t, 2, m.Cursor)
+
+		m = typeKeys(m, tea.KeyMsg{Type: tea.KeyUp})
+		assert.Equal(t, 1, m.Cursor)
+	})
+
+	t.Run("j and k move until a filter is typed", func(t *testing.T) {
+		m := typeKeys(NewSelectModel(options), runes("j"), runes("j"), runes("k"))
+		assert.Empty(t, m.Filter())
+		assert.Equal(t, 1, m.Cursor)
+
+		m = typeKeys(NewSelectModel(options), runes("s"), runes("k"))
+		assert.Equal(t, "sk", m.Filter())
+	})
+
+	t.Run("enter selects the highlighted match", func(t *testing.T) {
+		m := typeKeys(NewSelectModel(options), runes("EU"), tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Equal(t, "eu-west-1", m.Value())
+	})
+
+	t.Run("backspace edits and esc clears the filter", func(t *testing.T) {
+		m := typeKeys(NewSelectModel(options), runes("apx"))
+		assert.Empty(t, m.visible())
+		assert.False(t, m.SelectCursor())
+
+		m = typeKeys(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		assert.Equal(t, "ap", m.Filter())
+		assert.Equal(t, 3, m.Cursor)
+
+		m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+		assert.Empty(t, m.Filter())
+		assert.Len(t, m.visible(), len(options))
+		assert.Equal(t, 3, m.Cursor)
+	})
+}
@@ -3,8 +3,8 @@ package sync
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,6 +12,7 @@ import (
 
 	"github.com/theburrowhub/krakenv/internal/inspector"
 	"github.com/theburrowhub/krakenv/internal/parser"
+	"github.com/theburrowhub/krakenv/internal/tui/components"
 	"github.com/theburrowhub/krakenv/internal/validator"
 )
 
@@ -222,82 +223,41 @@ func New(result *inspector.InspectionResult, distFile, targetFile *parser.EnvFil
 	}
 }
 
-// inferType attempts to infer the variable type from its value.
-func inferType(value string) parser.VariableType {
-	value = strings.TrimSpace(value)
-
-	// Empty value - default to string
-	if value == "" {
-		return parser.TypeString
-	}
-
-	// Boolean check
-	lower := strings.ToLower(value)
-	if lower == "true" || lower == "false" ||
-		lower == "yes" || lower == "no" ||
-		lower == "on" || lower == "off" ||
-		value == "1" || value == "0" {
-		return parser.TypeBoolean
-	}
-
-	// Integer check
-	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
-		return parser.TypeInt
-	}
-
-	// Numeric (float) check
-	if _, err := strconv.ParseFloat(value, 64); err == nil {
-		return parser.TypeNumeric
-	}
-
-	// JSON object check
-	if (strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")) ||
-		(strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]")) {
-		return parser.TypeObject
-	}
-
-	// Default to string
-	return parser.TypeString
+// typeOptions lists the variable types offered in the add-to-dist type menu, in display order.
+var typeOptions = []parser.VariableType{
+	parser.TypeString,
+	parser.TypeInt,
+	parser.TypeNumeric,
+	parser.TypeBoolean,
+	parser.TypeEnum,
+	parser.TypeObject,
+	parser.TypeURL,
+	parser.TypeEmail,
+	parser.TypeDuration,
+	parser.TypeIP,
+	parser.TypeCIDR,
+	parser.TypeSemver,
+	parser.TypePath,
+	parser.TypeHostname,
+	parser.TypeDate,
 }
 
 // typeToIndex converts a VariableType to menu index.
 func typeToIndex(t parser.VariableType) int {
-	switch t {
-	case parser.TypeString:
-		return 0
-	case parser.TypeInt:
-		return 1
-	case parser.TypeNumeric:
-		return 2
-	case parser.TypeBoolean:
-		return 3
-	case parser.TypeEnum:
-		return 4
-	case parser.TypeObject:
-		return 5
-	default:
-		return 0
+	for i, opt := range typeOptions {
+		if opt == t {
+			return i
+		}
 	}
+	return 0
 }
 
 // indexToType converts menu index to VariableType.
 func indexToType(i int) parser.VariableType {
-	switch i {
-	case 0:
-		return parser.TypeString
-	case 1:
-		return parser.TypeInt
-	case 2:
-		return parser.TypeNumeric
-	case 3:
-		return parser.TypeBoolean
-	case 4:
-		return parser.TypeEnum
-	case 5:
-		return parser.TypeObject
-	default:
+	if i < 0 || i >= len(typeOptions) {
 		return parser.TypeString
 	}
+	return typeOptions[i]
 }
 
 // Init initializes the model.
@@ -347,7 +307,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.state == StateExtra && m.menuChoice < 2 {
 				m.menuChoice++
 			}
-			if m.state == StateAddToDist && m.addToDistStep == StepType && m.selectedType < 5 {
+			if m.state == StateAddToDist && m.addToDistStep == StepType && m.selectedType < len(typeOptions)-1 {
 				m.selectedType++
 			}
 
@@ -548,7 +508,7 @@ func (m Model) handleExtraEnter() (tea.Model, tea.Cmd) {
 		m.state = StateAddToDist
 		m.addToDistStep = StepType
 		m.addToDistVar = v
-		m.inferredType = inferType(v.Value)
+		m.inferredType = validator.InferType(v.Value)
 		m.selectedType = typeToIndex(m.inferredType)
 		m.promptText = ""
 		m.isOptional = false
@@ -784,7 +744,16 @@ func (m Model) renderProgress() string {
 		return ""
 	}
 
-	return progressStyle.Render(fmt.Sprintf("%s %d/%d", label, current, total))
+	text := progressStyle.Render(fmt.Sprintf("%s %d/%d", label, current, total))
+
+	// Fit the bar inside the input block, which is 72 cells wide at most and
+	// loses 8 cells to padding and border on narrow terminals
+	width := min(m.width-8, 72) - lipgloss.Width(text) - 1
+	bar := components.RenderProgressBar(current-1, total, min(width, components.MaxProgressBarWidth))
+	if bar == "" {
+		return text
+	}
+	return text + " " + bar
 }
 
 func (m Model) renderMissing() string {
@@ -912,7 +881,11 @@ func (m Model) renderExtra() string {
 	optContent.WriteString("\n\n")
 
 	optContent.WriteString(promptStyle.Render(v.Name))
-	optContent.WriteString(hintStyle.Render(fmt.Sprintf(" = %q", v.Value)))
+	value := v.Value
+	if validator.LooksSecret(v.Name) {
+		value = maskValue(value)
+	}
+	optContent.WriteString(hintStyle.Render(fmt.Sprintf(" = %q", value)))
 	optContent.WriteString("\n\n")
 
 	optContent.WriteString(hintStyle.Render("What would you like to do?"))
@@ -963,8 +936,14 @@ func (m Model) renderAddToDist() string {
 	// Content block
 	var content strings.Builder
 
+	// The real value stays in the resolution; only the screen is masked
+	value := v.Value
+	if m.isSecret || validator.LooksSecret(v.Name) {
+		value = maskValue(value)
+	}
+
 	content.WriteString(promptStyle.Render(v.Name))
-	content.WriteString(hintStyle.Render(fmt.Sprintf(" = %q", v.Value)))
+	content.WriteString(hintStyle.Render(fmt.Sprintf(" = %q", value)))
 	content.WriteString("\n")
 	content.WriteString(hintStyle.Render(fmt.Sprintf("Inferred type: %s", m.inferredType.String())))
 	content.WriteString("\n\n")
@@ -974,8 +953,8 @@ func (m Model) renderAddToDist() string {
 		content.WriteString(promptStyle.Render("Select variable type:"))
 		content.WriteString("\n\n")
 
-		types := []string{"string", "int", "numeric", "boolean", "enum", "object"}
-		for i, t := range types {
+		for i, opt := range typeOptions {
+			t := opt.String()
 			isSelected := i == m.selectedType
 			isInferred := i == typeToIndex(m.inferredType)
 
@@ -1029,7 +1008,7 @@ func (m Model) renderAddToDist() string {
 
 		// Build preview
 		preview := fmt.Sprintf("%s=%s #prompt:%s|%s",
-			v.Name, v.Value, m.promptText, indexToType(m.selectedType).String())
+			v.Name, value, m.promptText, indexToType(m.selectedType).String())
 		if m.isOptional {
 			preview += ";optional"
 		}
@@ -1179,3 +1158,8 @@ func (m Model) IsAborted() bool {
 func (m Model) IsDone() bool {
 	return m.state == StateDone
 }
+
+// maskValue hides a secret value on screen, keeping only its length.
+func maskValue(value string) string {
+	return strings.Repeat("•", utf8.RuneCountInString(value))
+}
@@ -0,0 +1,47 @@
+package sync
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theburrowhub/krakenv/internal/inspector"
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// typeValue types value into the model and presses enter.
+func typeValue(t *testing.T, m Model, value string) Model {
+	t.Helper()
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(value)})
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return next.(Model)
+}
+
+func TestModel_InvalidValues(t *testing.T) {
+	distFile, err := parser.ParseEnvFileContent("PORT=8080 #prompt:Port?|int;max:65535\nHOST=localhost #prompt:Host?|string\n", ".env.dist")
+	require.NoError(t, err)
+	targetFile, err := parser.ParseEnvFileContent("PORT=abc\n", ".env.local")
+	require.NoError(t, err)
+
+	// Only the invalid values, as validate --fix runs it
+	result := inspector.Inspect(distFile, targetFile)
+	result.MissingInEnv = nil
+	m := New(result, distFile, targetFile)
+	assert.Equal(t, StateInvalid, m.state)
+
+	// Each entry is validated before it is accepted
+	m = typeValue(t, m, "70000")
+	assert.Error(t, m.err)
+	assert.Equal(t, StateInvalid, m.state)
+	assert.Empty(t, m.GetResolutions())
+
+	m.textInput.Reset()
+	m = typeValue(t, m, "8080")
+	assert.NoError(t, m.err)
+	assert.Equal(t, StateConfirm, m.state)
+	assert.Equal(t, []Resolution{
+		{Variable: parser.Variable{Name: "PORT"}, Action: ActionAdd, NewValue: "8080"},
+	}, m.GetResolutions())
+}
@@ -42,6 +42,9 @@ type Model struct {
 	selectModel components.SelectModel
 	useSelect   bool // True if current variable is enum
 
+	// prefillDefaults pre-fills inputs with the variable's default value
+	prefillDefaults bool
+
 	// Display
 	Width  int
 	Height int
@@ -59,14 +62,25 @@ func New(variables []parser.Variable) Model {
 	ti.CharLimit = 256
 	ti.Width = 50
 
-	return Model{
-		Variables:    variables,
-		Values:       make(map[string]string),
-		State:        StatePrompting,
-		textInput:    ti,
-		selectModel:  components.NewSelectModel(nil),
-		CurrentIndex: 0,
+	m := Model{
+		Variables:       variables,
+		Values:          make(map[string]string),
+		State:           StatePrompting,
+		textInput:       ti,
+		selectModel:     components.NewSelectModel(nil),
+		CurrentIndex:    0,
+		prefillDefaults: true,
 	}
+	m.setupCurrentInput()
+	return m
+}
+
+// WithPrefill sets whether inputs are pre-filled with default values.
+// When disabled, defaults are only shown as a hint and can be applied with Tab.
+func (m Model) WithPrefill(enabled bool) Model {
+	m.prefillDefaults = enabled
+	m.setupCurrentInput()
+	return m
 }
 
 // CurrentVariable returns the current variable being prompted.
@@ -101,17 +115,22 @@ func (m *Model) setupCurrentInput() {
 			options[i] = strings.TrimSpace(options[i])
 		}
 		m.selectModel.SetOptions(options)
+		m.selectModel.CaseInsensitive = v.Annotation.NoCase
 		m.useSelect = true
 
-		// Pre-select default if available
-		if v.Value != "" {
+		// Pre-select the value entered earlier, or the default
+		if value, ok := m.Values[v.Name]; ok {
+			m.selectModel.Select(value)
+		} else if v.Value != "" && m.prefillDefaults {
 			m.selectModel.Select(v.Value)
 		}
 	} else {
 		m.useSelect = false
 
-		// Set default value
-		if v.Value != "" {
+		// Restore the value entered earlier, or set the default
+		if value, ok := m.Values[v.Name]; ok {
+			m.textInput.SetValue(value)
+		} else if v.Value != "" && m.prefillDefaults {
 			m.textInput.SetValue(v.Value)
 		}
 
@@ -160,11 +179,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			return m.submitInput()
 
+		case "ctrl+p":
+			return m.previousVariable()
+
+		case "left":
+			// Go back when the cursor is already at the start of the input
+			if m.useSelect || m.textInput.Position() == 0 {
+				return m.previousVariable()
+			}
+
 		case "tab":
 			// Auto-complete with default if available
 			v := m.CurrentVariable()
-			if v != nil && v.Value != "" && m.textInput.Value() == "" {
-				m.textInput.SetValue(v.Value)
+			if v != nil && v.Value != "" {
+				if m.useSelect {
+					m.selectModel.Select(v.Value)
+				} else if m.textInput.Value() == "" {
+					m.textInput.SetValue(v.Value)
+				}
 			}
 		}
 	}
@@ -212,6 +244,10 @@ func (m Model) submitInput() (tea.Model, tea.Cmd) {
 	// Get value
 	var value string
 	if m.useSelect {
+		// Enter picks the highlighted option when filtering or nothing is selected yet
+		if m.selectModel.Filter() != "" || !m.selectModel.HasSelection() {
+			m.selectModel.SelectCursor()
+		}
 		if !m.selectModel.HasSelection() {
 			// Must select an option
 			return m, nil
@@ -250,6 +286,20 @@ func (m Model) nextVariable() (tea.Model, tea.Cmd) {
 	return m, textinput.Blink
 }
 
+// previousVariable moves back to the previous variable, restoring the value
+// entered for it. Collected values are kept, so re-submitting replaces them.
+func (m Model) previousVariable() (tea.Model, tea.Cmd) {
+	if m.CurrentIndex == 0 {
+		return m, nil
+	}
+
+	m.CurrentIndex--
+	m.Error = nil
+	m.State = StatePrompting
+	m.setupCurrentInput()
+	return m, textinput.Blink
+}
+
 // View implements tea.Model.
 func (m Model) View() string {
 	if m.showExitPrompt {
@@ -264,7 +314,7 @@ func (m Model) View() string {
 
 	// Progress
 	progress := fmt.Sprintf("Variable %d of %d", m.CurrentIndex+1, len(m.Variables))
-	b.WriteString(components.MutedStyle.Render(progress))
+	b.WriteString(components.RenderProgress(progress, m.CurrentIndex, len(m.Variables), m.Width))
 	b.WriteString("\n\n")
 
 	v := m.CurrentVariable()
@@ -283,6 +333,12 @@ func (m Model) View() string {
 		b.WriteString(prompt)
 		b.WriteString("\n")
 
+		// Description
+		if v.Annotation.Description != "" {
+			b.WriteString(components.MutedStyle.Render(v.Annotation.Description))
+			b.WriteString("\n")
+		}
+
 		// Type constraint
 		constraint := formatConstraint(v.Annotation)
 		if constraint != "" {
@@ -292,7 +348,7 @@ func (m Model) View() string {
 	}
 
 	// Default value hint
-	if v.Value != "" && !m.useSelect {
+	if v.Value != "" && (!m.useSelect || !m.prefillDefaults) {
 		b.WriteString(components.RenderDefault(v.Value))
 		b.WriteString("\n")
 	}
@@ -316,6 +372,12 @@ func (m Model) View() string {
 	// Help
 	b.WriteString("\n\n")
 	help := "Enter: submit • Tab: use default • Ctrl+C: exit"
+	if m.useSelect {
+		help += " • Type to filter • Esc: clear filter"
+	}
+	if m.CurrentIndex > 0 {
+		help += " • Ctrl+P: back"
+	}
 	if v.Annotation != nil && v.Annotation.IsOptional {
 		help += " • Ctrl+D: skip"
 	}
@@ -362,28 +424,69 @@ func formatConstraint(ann *parser.Annotation) string {
 	parts := []string{ann.Type.String()}
 
 	switch ann.Type {
-	case parser.TypeInt, parser.TypeNumeric:
+	case parser.TypeInt, parser.TypeNumeric, parser.TypeDuration, parser.TypeSemver:
 		min := ann.GetConstraint("min")
 		max := ann.GetConstraint("max")
-		if min != "" && max != "" {
+		if validator.HasExclusiveBounds(ann) {
+			parts = append(parts, validator.FormatRange(ann))
+		} else if min != "" && max != "" {
 			parts = append(parts, fmt.Sprintf("%s-%s", min, max))
 		} else if min != "" {
 			parts = append(parts, fmt.Sprintf(">=%s", min))
 		} else if max != "" {
 			parts = append(parts, fmt.Sprintf("<=%s", max))
 		}
+		if step := ann.GetConstraint("step"); step != "" {
+			parts = append(parts, "step:"+step)
+		}
 	case parser.TypeString:
 		minlen := ann.GetConstraint("minlen")
 		maxlen := ann.GetConstraint("maxlen")
 		if minlen != "" || maxlen != "" {
 			parts = append(parts, fmt.Sprintf("len:%s-%s", minlen, maxlen))
 		}
-		if pattern := ann.GetConstraint("pattern"); pattern != "" {
+		if desc := ann.GetConstraint("pattern-desc"); desc != "" {
+			parts = append(parts, desc)
+		} else if pattern := ann.GetConstraint("pattern"); pattern != "" {
 			parts = append(parts, "pattern")
 		}
+		if entropy := ann.GetConstraint("entropy"); entropy != "" {
+			parts = append(parts, entropy+" bits")
+		}
 	case parser.TypeEnum:
 		options := ann.GetConstraint("options")
 		parts = append(parts, strings.ReplaceAll(options, ",", "|"))
+	case parser.TypeIP, parser.TypeCIDR:
+		if version := ann.GetConstraint("version"); version != "" {
+			parts = append(parts, "v"+strings.ReplaceAll(version, ",", "/v"))
+		}
+	case parser.TypePath:
+		if ann.GetConstraint("abs") == "true" {
+			parts = append(parts, "absolute")
+		}
+		if ann.GetConstraint("exists") == "true" {
+			kind := ann.GetConstraint("kind")
+			if kind == "" {
+				kind = "path"
+			}
+			parts = append(parts, "existing "+kind)
+		}
+	case parser.TypeHostname:
+		if ann.GetConstraint("allowip") == "true" {
+			parts = append(parts, "or IP")
+		}
+	case parser.TypeDate:
+		parts = append(parts, validator.DateLayout(ann))
+		if after := ann.GetConstraint("after"); after != "" {
+			parts = append(parts, "after "+after)
+		}
+		if before := ann.GetConstraint("before"); before != "" {
+			parts = append(parts, "before "+before)
+		}
+	}
+
+	if encoding := ann.GetConstraint("encoding"); encoding != "" {
+		parts = append(parts, "encoding:"+encoding)
 	}
 
 	return strings.Join(parts, ": ")
@@ -1,7 +1,10 @@
 // Package validator provides functionality for validating environment variable values.
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ErrorType represents the type of validation error.
 type ErrorType int
@@ -55,8 +58,17 @@ func (e *ValidationError) Error() string {
 // Format returns a formatted error message with all four required components.
 // Per FR-033: Problem, Location, Suggestion, Example.
 func (e *ValidationError) Format() string {
+	return e.format("✗")
+}
+
+// FormatWarning formats the error like Format, marked as a warning.
+func (e *ValidationError) FormatWarning() string {
+	return e.format("⚠")
+}
+
+func (e *ValidationError) format(marker string) string {
 	result := fmt.Sprintf("  Line %d: %s\n", e.LineNumber, e.Variable)
-	result += fmt.Sprintf("    ✗ %s\n", e.Message)
+	result += fmt.Sprintf("    %s %s\n", marker, e.Message)
 	if e.Suggestion != "" {
 		result += fmt.Sprintf("    → Fix: %s\n", e.Suggestion)
 	}
@@ -68,8 +80,11 @@ func (e *ValidationError) Format() string {
 
 // ValidationResult holds the results of validating an environment file.
 type ValidationResult struct {
-	Errors []ValidationError // All validation errors
-	Valid  bool              // True if no errors
+	Errors    []ValidationError // Validation errors, up to MaxErrors
+	Warnings  []ValidationError // Problems that do not fail validation
+	Valid     bool              // True if no errors
+	MaxErrors int               // Errors kept at most; 0 keeps all
+	Omitted   int               // Errors counted but not kept because of MaxErrors
 }
 
 // NewValidationResult creates a new empty ValidationResult.
@@ -80,31 +95,169 @@ func NewValidationResult() *ValidationResult {
 	}
 }
 
-// AddError adds an error to the result and marks it as invalid.
+// AddError adds an error to the result and marks it as invalid. Past
+// MaxErrors the error is only counted.
 func (r *ValidationResult) AddError(err ValidationError) {
-	r.Errors = append(r.Errors, err)
 	r.Valid = false
+	if r.MaxErrors > 0 && len(r.Errors) >= r.MaxErrors {
+		r.Omitted++
+		return
+	}
+	r.Errors = append(r.Errors, err)
+}
+
+// AddWarning adds a warning to the result without marking it as invalid.
+func (r *ValidationResult) AddWarning(err ValidationError) {
+	r.Warnings = append(r.Warnings, err)
+}
+
+// Limit returns a copy of the result keeping at most max errors (0 keeps
+// all); the rest are counted in Omitted.
+func (r *ValidationResult) Limit(max int) *ValidationResult {
+	limited := &ValidationResult{
+		Errors:    make([]ValidationError, 0, len(r.Errors)),
+		Warnings:  r.Warnings,
+		Valid:     r.Valid,
+		MaxErrors: max,
+		Omitted:   r.Omitted,
+	}
+	for _, err := range r.Errors {
+		limited.AddError(err)
+	}
+	return limited
 }
 
-// ErrorCount returns the number of errors.
+// ErrorCount returns the number of errors, including omitted ones.
 func (r *ValidationResult) ErrorCount() int {
-	return len(r.Errors)
+	return len(r.Errors) + r.Omitted
 }
 
 // FormatErrors returns a formatted string of all errors.
 func (r *ValidationResult) FormatErrors(filePath string) string {
+	var result string
 	if r.Valid {
-		return fmt.Sprintf("✓ VALIDATION PASSED: %s\n", filePath)
+		result = fmt.Sprintf("✓ VALIDATION PASSED: %s\n", filePath)
+	} else {
+		result = fmt.Sprintf("✗ VALIDATION FAILED: %s\n\n", filePath)
+		for _, err := range r.Errors {
+			result += err.Format() + "\n"
+		}
+		if r.Omitted > 0 {
+			result += fmt.Sprintf("  ... and %d more\n\n", r.Omitted)
+		}
+		result += fmt.Sprintf("Found %d error(s)\n", r.ErrorCount())
 	}
 
-	result := fmt.Sprintf("✗ VALIDATION FAILED: %s\n\n", filePath)
-	for _, err := range r.Errors {
-		result += err.Format() + "\n"
+	if len(r.Warnings) > 0 {
+		result += "\n"
+		for _, w := range r.Warnings {
+			result += w.FormatWarning() + "\n"
+		}
+		result += fmt.Sprintf("Found %d warning(s)\n", len(r.Warnings))
 	}
-	result += fmt.Sprintf("Found %d error(s)\n", len(r.Errors))
 	return result
 }
 
+// JSONError is a validation error in machine-readable output.
+type JSONError struct {
+	File       string `json:"file"`
+	Variable   string `json:"variable"`
+	Line       int    `json:"line"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Example    string `json:"example,omitempty"`
+}
+
+// JSONResult is the machine-readable summary of a validated file.
+type JSONResult struct {
+	File       string      `json:"file"`
+	Valid      bool        `json:"valid"`
+	ErrorCount int         `json:"errorCount"`
+	Omitted    int         `json:"omitted,omitempty"`
+	Errors     []JSONError `json:"errors"`
+	Warnings   []JSONError `json:"warnings"`
+}
+
+// JSONErrors returns the errors of a validated file in machine-readable form.
+func (r *ValidationResult) JSONErrors(filePath string) []JSONError {
+	return jsonErrors(r.Errors, filePath)
+}
+
+// JSONResult returns the result of a validated file in machine-readable form.
+func (r *ValidationResult) JSONResult(filePath string) JSONResult {
+	return JSONResult{
+		File:       filePath,
+		Valid:      r.Valid,
+		ErrorCount: r.ErrorCount(),
+		Omitted:    r.Omitted,
+		Errors:     jsonErrors(r.Errors, filePath),
+		Warnings:   jsonErrors(r.Warnings, filePath),
+	}
+}
+
+func jsonErrors(list []ValidationError, filePath string) []JSONError {
+	errs := make([]JSONError, 0, len(list))
+	for _, err := range list {
+		errs = append(errs, JSONError{
+			File:       filePath,
+			Variable:   err.Variable,
+			Line:       err.LineNumber,
+			Type:       err.Type.String(),
+			Message:    err.Message,
+			Suggestion: err.Suggestion,
+			Example:    err.Example,
+		})
+	}
+	return errs
+}
+
+// FormatGitHub returns errors and warnings as GitHub Actions workflow
+// commands, which show up as annotations on the file.
+func (r *ValidationResult) FormatGitHub(filePath string) string {
+	var b strings.Builder
+	for _, err := range r.Errors {
+		b.WriteString(err.formatGitHub("error", filePath))
+	}
+	if r.Omitted > 0 {
+		fmt.Fprintf(&b, "::error file=%s::... and %d more error(s) not listed\n", escapeGitHubProperty(filePath), r.Omitted)
+	}
+	for _, w := range r.Warnings {
+		b.WriteString(w.formatGitHub("warning", filePath))
+	}
+	return b.String()
+}
+
+// formatGitHub formats the error as a single workflow command line. Errors
+// without a line number, such as missing variables, annotate the whole file.
+func (e *ValidationError) formatGitHub(command, filePath string) string {
+	props := "file=" + escapeGitHubProperty(filePath)
+	if e.LineNumber > 0 {
+		props += fmt.Sprintf(",line=%d", e.LineNumber)
+	}
+	props += ",title=" + escapeGitHubProperty(e.Variable)
+
+	msg := e.Variable + ": " + e.Message
+	if e.Suggestion != "" {
+		msg += "\n" + e.Suggestion
+	}
+	return fmt.Sprintf("::%s %s::%s\n", command, props, escapeGitHubData(msg))
+}
+
+// escapeGitHubData escapes a workflow command message.
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property value.
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
 // NewMissingRequiredError creates a ValidationError for a missing required variable.
 func NewMissingRequiredError(variable string, lineNumber int, prompt string) ValidationError {
 	return ValidationError{
@@ -153,6 +306,18 @@ func NewAnnotationSyntaxError(variable string, lineNumber int, message string) V
 	}
 }
 
+// NewParseWarning creates a ValidationError for a line the parser skipped or
+// partly ignored.
+func NewParseWarning(variable string, lineNumber int, message string) ValidationError {
+	return ValidationError{
+		Variable:   variable,
+		LineNumber: lineNumber,
+		Message:    message,
+		Suggestion: "Fix or remove the line",
+		Type:       ErrorAnnotationSyntax,
+	}
+}
+
 // NewDuplicateVariableError creates a ValidationError for duplicate variable.
 func NewDuplicateVariableError(variable string, lineNumber, firstLine int) ValidationError {
 	return ValidationError{
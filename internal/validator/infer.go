@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// DefaultSecretPattern matches variable names that usually hold credentials.
+const DefaultSecretPattern = `(SECRET|TOKEN|PASSWORD|PASSWD|CREDENTIAL|PRIVATE_KEY|API_KEY)`
+
+var defaultSecretRegex = regexp.MustCompile(DefaultSecretPattern)
+
+// LooksSecret guesses from its name whether an unannotated variable holds a
+// credential, such as DB_PASSWORD or GITHUB_TOKEN.
+func LooksSecret(name string) bool {
+	return defaultSecretRegex.MatchString(strings.ToUpper(name))
+}
+
+// InferType guesses the type of an unannotated variable from its value.
+// Values that match no narrower type are strings.
+func InferType(value string) parser.VariableType {
+	value = strings.TrimSpace(value)
+
+	// Empty value - default to string
+	if value == "" {
+		return parser.TypeString
+	}
+
+	// URL check
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return parser.TypeURL
+	}
+
+	// Boolean check
+	lower := strings.ToLower(value)
+	if lower == "true" || lower == "false" ||
+		lower == "yes" || lower == "no" ||
+		lower == "on" || lower == "off" ||
+		value == "1" || value == "0" {
+		return parser.TypeBoolean
+	}
+
+	// Integer check
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return parser.TypeInt
+	}
+
+	// Numeric (float) check
+	if _, err := ParseNumber(value); err == nil {
+		return parser.TypeNumeric
+	}
+
+	// IP address check (dotted-quad or colon-hex)
+	if net.ParseIP(value) != nil {
+		return parser.TypeIP
+	}
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return parser.TypeCIDR
+	}
+
+	// Date check: only ISO 8601 calendar dates, the layout dates default to
+	if _, err := time.Parse(DefaultDateLayout, value); err == nil {
+		return parser.TypeDate
+	}
+
+	// Path check: absolute or explicitly relative paths
+	if filepath.IsAbs(value) || strings.HasPrefix(value, "./") ||
+		strings.HasPrefix(value, "../") || strings.HasPrefix(value, "~/") {
+		return parser.TypePath
+	}
+
+	// Duration check (after numbers, so bare integers stay int)
+	if _, err := time.ParseDuration(value); err == nil {
+		return parser.TypeDuration
+	}
+
+	// JSON object check
+	if (strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")) ||
+		(strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]")) {
+		return parser.TypeObject
+	}
+
+	// Default to string
+	return parser.TypeString
+}
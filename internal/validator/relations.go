@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// ValidateRelations checks constraints that relate variables to each other,
+// using the annotations of distFile and the values of targetFile:
+//   - requires: if the variable is set, each named variable must be set too
+//   - conflicts: if the variable is set, none of the named variables may be
+//     set
+//
+// A variable counts as set when it has a non-empty value in targetFile.
+func ValidateRelations(distFile, targetFile *parser.EnvFile) []ValidationError {
+	var errs []ValidationError
+	reported := make(map[[2]string]bool) // Conflicting pairs, reported once
+
+	for _, distVar := range distFile.Variables {
+		if distVar.Annotation == nil {
+			continue
+		}
+		targetVar := targetFile.GetVariable(distVar.Name)
+		if targetVar == nil || targetVar.Value == "" {
+			continue
+		}
+
+		for _, dep := range constraintNames(distVar.Annotation, "requires") {
+			if isSet(targetFile, dep) {
+				continue
+			}
+			errs = append(errs, ValidationError{
+				Variable:   distVar.Name,
+				LineNumber: targetVar.LineNumber,
+				Message:    fmt.Sprintf("%s requires %s to be set", distVar.Name, dep),
+				Suggestion: fmt.Sprintf("Set %s, or clear %s", dep, distVar.Name),
+				Type:       ErrorConstraintViolation,
+			})
+		}
+
+		for _, other := range constraintNames(distVar.Annotation, "conflicts") {
+			if !isSet(targetFile, other) || reported[[2]string{other, distVar.Name}] {
+				continue
+			}
+			reported[[2]string{distVar.Name, other}] = true
+
+			otherVar := targetFile.GetVariable(other)
+			errs = append(errs, ValidationError{
+				Variable:   distVar.Name,
+				LineNumber: targetVar.LineNumber,
+				Message: fmt.Sprintf("%s (line %d) conflicts with %s (line %d); only one may be set",
+					distVar.Name, targetVar.LineNumber, other, otherVar.LineNumber),
+				Suggestion: fmt.Sprintf("Clear %s or %s", distVar.Name, other),
+				Type:       ErrorConstraintViolation,
+			})
+		}
+	}
+
+	return errs
+}
+
+// constraintNames splits a comma-separated list of variable names.
+func constraintNames(ann *parser.Annotation, constraint string) []string {
+	var names []string
+	for _, name := range strings.Split(ann.GetConstraint(constraint), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isSet reports whether name has a non-empty value in f.
+func isSet(f *parser.EnvFile, name string) bool {
+	v := f.GetVariable(name)
+	return v != nil && v.Value != ""
+}
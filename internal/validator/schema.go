@@ -0,0 +1,433 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// Only a subset of JSON Schema is supported: the validation keywords below,
+// with items as a single schema (not a tuple) and numeric keywords as numbers
+// (draft 6 and later). Schemas using any other keyword, such as $ref, oneOf,
+// not, format or patternProperties, are rejected rather than half-checked.
+var schemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true,
+	"properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"allOf": true, "anyOf": true,
+}
+
+// schemaAnnotations are keywords that describe a schema without constraining
+// documents, so they are accepted and ignored.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true,
+	"title": true, "description": true, "default": true, "examples": true,
+}
+
+// validateSchema validates a decoded JSON or YAML document against the schema
+// file named by the annotation's schema constraint.
+func validateSchema(doc interface{}, ann *parser.Annotation) error {
+	path := ann.GetConstraint("schema")
+	if !filepath.IsAbs(path) && ann.BaseDir != "" {
+		path = filepath.Join(ann.BaseDir, path)
+	}
+
+	schema, err := loadSchema(path)
+	if err != nil {
+		return err
+	}
+
+	if err := checkSchema(doc, schema, ""); err != nil {
+		return fmt.Errorf("value does not match schema %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// schemaCache holds loaded schema files by path, so a schema is read once
+// per change rather than for every value. Safe for concurrent use.
+var schemaCache sync.Map // string -> cachedSchema
+
+// cachedSchema is a loadSchema result for one version of a schema file.
+type cachedSchema struct {
+	modTime time.Time
+	size    int64
+	schema  map[string]interface{}
+	err     error
+}
+
+// loadSchema reads, decodes and checks a JSON Schema file, reusing the
+// earlier result while the file is unchanged.
+func loadSchema(path string) (map[string]interface{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %v", err)
+	}
+	if cached, ok := schemaCache.Load(path); ok {
+		c := cached.(cachedSchema)
+		if c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+			return c.schema, c.err
+		}
+	}
+
+	schema, err := readSchema(path)
+	schemaCache.Store(path, cachedSchema{modTime: info.ModTime(), size: info.Size(), schema: schema, err: err})
+	return schema, err
+}
+
+// readSchema reads and decodes a JSON Schema file, rejecting keywords that
+// are not supported.
+func readSchema(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", path, err)
+	}
+	if err := checkKeywords(schema, "#"); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", path, err)
+	}
+	return schema, nil
+}
+
+// checkKeywords reports the first keyword of schema or its subschemas that
+// is not supported. path is the JSON pointer of schema within the file.
+func checkKeywords(schema map[string]interface{}, path string) error {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if schemaAnnotations[key] {
+			continue
+		}
+		if !schemaKeywords[key] {
+			return fmt.Errorf("unsupported keyword %q at %s", key, path)
+		}
+
+		var subs map[string]interface{}
+		switch value := schema[key].(type) {
+		case map[string]interface{}:
+			switch key {
+			case "properties":
+				subs = value
+			case "items", "additionalProperties":
+				subs = map[string]interface{}{"": value}
+			}
+		case []interface{}:
+			switch key {
+			case "allOf", "anyOf":
+				subs = make(map[string]interface{}, len(value))
+				for i, sub := range value {
+					subs[fmt.Sprint(i)] = sub
+				}
+			case "items":
+				return fmt.Errorf("unsupported tuple items at %s", path)
+			}
+		}
+
+		for name, sub := range subs {
+			s, ok := sub.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			subPath := path + "/" + key
+			if name != "" {
+				subPath += "/" + name
+			}
+			if err := checkKeywords(s, subPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonDocument converts a decoded YAML document to the values encoding/json
+// produces, so it can be checked like a JSON one.
+func jsonDocument(doc interface{}) (interface{}, error) {
+	data, err := json.Marshal(stringKeys(doc))
+	if err != nil {
+		return nil, err
+	}
+	var js interface{}
+	if err := json.Unmarshal(data, &js); err != nil {
+		return nil, err
+	}
+	return js, nil
+}
+
+// stringKeys returns doc with every YAML mapping keyed by strings.
+func stringKeys(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = stringKeys(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[fmt.Sprint(k)] = stringKeys(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = stringKeys(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// schemaError is a schema violation at a location in the document.
+type schemaError struct {
+	path    string
+	message string
+}
+
+func (e *schemaError) Error() string {
+	path := e.path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("at %s: %s", path, e.message)
+}
+
+func violation(path, format string, args ...interface{}) error {
+	return &schemaError{path: path, message: fmt.Sprintf(format, args...)}
+}
+
+// checkSchema validates doc against schema. path is the JSON pointer of doc.
+func checkSchema(doc interface{}, schema map[string]interface{}, path string) error {
+	if t, ok := schema["type"]; ok {
+		if err := checkType(doc, t, path); err != nil {
+			return err
+		}
+	}
+
+	if options, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range options {
+			if reflect.DeepEqual(doc, option) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return violation(path, "value is not one of the allowed values")
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(doc, c) {
+		return violation(path, "value must be %v", c)
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if err := checkObject(v, schema, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := checkArray(v, schema, path); err != nil {
+			return err
+		}
+	case string:
+		if err := checkString(v, schema, path); err != nil {
+			return err
+		}
+	case float64:
+		if err := checkNumber(v, schema, path); err != nil {
+			return err
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if s, ok := sub.(map[string]interface{}); ok {
+				if err := checkSchema(doc, s, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if s, ok := sub.(map[string]interface{}); ok && checkSchema(doc, s, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return violation(path, "value does not match any of the allowed schemas")
+		}
+	}
+
+	return nil
+}
+
+func checkType(doc interface{}, t interface{}, path string) error {
+	var types []string
+	switch tv := t.(type) {
+	case string:
+		types = []string{tv}
+	case []interface{}:
+		for _, item := range tv {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	actual := jsonType(doc)
+	for _, want := range types {
+		if want == actual || (want == "number" && actual == "integer") {
+			return nil
+		}
+	}
+	return violation(path, "expected %s, got %s", strings.Join(types, " or "), actual)
+}
+
+// jsonType returns the JSON Schema type name of a decoded value.
+func jsonType(doc interface{}) string {
+	switch v := doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+func checkObject(obj map[string]interface{}, schema map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				return violation(path, "missing required property %q", name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Check keys in a stable order so errors are deterministic
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + key
+		if sub, ok := properties[key].(map[string]interface{}); ok {
+			if err := checkSchema(obj[key], sub, childPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return violation(path, "unexpected property %q", key)
+			}
+		case map[string]interface{}:
+			if err := checkSchema(obj[key], additional, childPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkArray(arr []interface{}, schema map[string]interface{}, path string) error {
+	if min, ok := schema["minItems"].(float64); ok && float64(len(arr)) < min {
+		return violation(path, "expected at least %v items, got %d", min, len(arr))
+	}
+	if max, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > max {
+		return violation(path, "expected at most %v items, got %d", max, len(arr))
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			if err := checkSchema(item, items, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkString(s string, schema map[string]interface{}, path string) error {
+	length := utf8.RuneCountInString(s)
+	if min, ok := schema["minLength"].(float64); ok && float64(length) < min {
+		return violation(path, "length %d is below minimum %v", length, min)
+	}
+	if max, ok := schema["maxLength"].(float64); ok && float64(length) > max {
+		return violation(path, "length %d exceeds maximum %v", length, max)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return violation(path, "invalid pattern in schema: %v", err)
+		}
+		if !re.MatchString(s) {
+			return violation(path, "value %q does not match pattern %s", s, pattern)
+		}
+	}
+
+	return nil
+}
+
+func checkNumber(n float64, schema map[string]interface{}, path string) error {
+	if min, ok := schema["minimum"].(float64); ok && n < min {
+		return violation(path, "value %v is below minimum %v", n, min)
+	}
+	if max, ok := schema["maximum"].(float64); ok && n > max {
+		return violation(path, "value %v exceeds maximum %v", n, max)
+	}
+	if min, ok := schema["exclusiveMinimum"].(float64); ok && n <= min {
+		return violation(path, "value %v must be greater than %v", n, min)
+	}
+	if max, ok := schema["exclusiveMaximum"].(float64); ok && n >= max {
+		return violation(path, "value %v must be less than %v", n, max)
+	}
+	return nil
+}
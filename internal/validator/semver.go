@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/theburrowhub/krakenv/internal/parser"
+)
+
+// SemverPattern is a regular expression matching a Semantic Versioning 2.0.0
+// version without prefix.
+const SemverPattern = `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*)(?:\.(?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*))*))?` +
+	`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`
+
+var semverPattern = regexp.MustCompile(SemverPattern)
+
+// semver is a parsed semantic version. Build metadata is not kept since it
+// does not affect precedence.
+type semver struct {
+	major, minor, patch uint64
+	prerelease          []string
+}
+
+// parseSemver parses a version such as 1.2.3, 1.2.3-rc.1 or 1.2.3+build.
+func parseSemver(s string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(s)
+	if m == nil {
+		return semver{}, false
+	}
+
+	var v semver
+	var err error
+	if v.major, err = strconv.ParseUint(m[1], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if v.minor, err = strconv.ParseUint(m[2], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if v.patch, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v, true
+}
+
+// compare returns -1, 0 or 1 following semver precedence rules.
+func (v semver) compare(o semver) int {
+	for _, pair := range [][2]uint64{{v.major, o.major}, {v.minor, o.minor}, {v.patch, o.patch}} {
+		if pair[0] != pair[1] {
+			return cmpUint(pair[0], pair[1])
+		}
+	}
+
+	// A version without pre-release has higher precedence
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrereleaseID(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmpUint(uint64(len(v.prerelease)), uint64(len(o.prerelease)))
+}
+
+// comparePrereleaseID compares identifiers numerically when both are numbers,
+// otherwise lexically; numeric identifiers sort first.
+func comparePrereleaseID(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmpUint(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func cmpUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func validateSemver(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for semver")
+	}
+
+	version := value
+	if ann.GetConstraint("vprefix") == "true" {
+		version = strings.TrimPrefix(value, "v")
+	}
+
+	v, ok := parseSemver(version)
+	if !ok {
+		return fmt.Errorf("expected semantic version like 1.2.3, got %q", value)
+	}
+
+	// Check min constraint
+	if minStr := ann.GetConstraint("min"); minStr != "" {
+		min, ok := parseSemver(strings.TrimPrefix(minStr, "v"))
+		if ok && v.compare(min) < 0 {
+			return fmt.Errorf("version %s is below minimum %s", value, minStr)
+		}
+	}
+
+	// Check max constraint
+	if maxStr := ann.GetConstraint("max"); maxStr != "" {
+		max, ok := parseSemver(strings.TrimPrefix(maxStr, "v"))
+		if ok && v.compare(max) > 0 {
+			return fmt.Errorf("version %s exceeds maximum %s", value, maxStr)
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,23 @@
 package validator
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 
@@ -36,21 +48,124 @@ func ValidateValue(value string, ann *parser.Annotation) error {
 		return nil
 	}
 
+	var err error
 	switch ann.Type {
 	case parser.TypeInt:
-		return validateInt(value, ann)
+		err = validateInt(value, ann)
 	case parser.TypeNumeric:
-		return validateNumeric(value, ann)
+		err = validateNumeric(value, ann)
 	case parser.TypeString:
-		return validateString(value, ann)
+		err = validateString(value, ann)
 	case parser.TypeEnum:
-		return validateEnum(value, ann)
+		err = validateEnum(value, ann)
 	case parser.TypeBoolean:
-		return validateBoolean(value)
+		err = validateBoolean(value)
 	case parser.TypeObject:
-		return validateObject(value, ann)
+		err = validateObject(value, ann)
+	case parser.TypeURL:
+		err = validateURL(value, ann)
+	case parser.TypeEmail:
+		err = validateEmail(value, ann)
+	case parser.TypeDuration:
+		err = validateDuration(value, ann)
+	case parser.TypeIP:
+		err = validateIP(value, ann)
+	case parser.TypeCIDR:
+		err = validateCIDR(value, ann)
+	case parser.TypeSemver:
+		err = validateSemver(value, ann)
+	case parser.TypePath:
+		err = validatePath(value, ann)
+	case parser.TypeHostname:
+		err = validateHostname(value, ann)
+	case parser.TypeDate:
+		err = validateDate(value, ann)
+	}
+	if err == nil {
+		err = validateEncoding(value, ann)
+	}
+	if err != nil {
+		if msg := ann.GetConstraint("error"); msg != "" {
+			return &customMessageError{message: msg, err: err}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// customMessageError replaces a validation error's message with the text of
+// the annotation's error constraint, keeping the original for classification.
+type customMessageError struct {
+	message string
+	err     error
+}
+
+func (e *customMessageError) Error() string { return e.message }
+
+func (e *customMessageError) Unwrap() error { return e.err }
+
+// encodingError reports a value that does not decode with its declared encoding.
+type encodingError struct {
+	encoding string
+	err      error
+}
+
+func (e *encodingError) Error() string {
+	return fmt.Sprintf("invalid %s encoding: %v", e.encoding, e.err)
+}
+
+func (e *encodingError) Unwrap() error { return e.err }
+
+// validateEncoding checks the value against the annotation's encoding constraint, if any.
+func validateEncoding(value string, ann *parser.Annotation) error {
+	_, err := Decode(value, ann)
+	return err
+}
+
+// Decode returns the raw bytes of a value according to the annotation's
+// encoding constraint (base64, hex or url). Values without an encoding, or
+// with one that needs no decoding such as heredoc, are returned as-is.
+func Decode(value string, ann *parser.Annotation) ([]byte, error) {
+	encoding := ""
+	if ann != nil {
+		encoding = ann.GetConstraint("encoding")
+	}
+
+	switch encoding {
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, &encodingError{encoding: "base64", err: err}
+		}
+		return data, nil
+	case "hex":
+		data, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, &encodingError{encoding: "hex", err: err}
+		}
+		return data, nil
+	case "url":
+		// PathUnescape rejects any '%' not followed by two hex digits
+		decoded, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, &encodingError{encoding: "URL", err: err}
+		}
+		return []byte(decoded), nil
 	default:
-		return nil
+		return []byte(value), nil
+	}
+}
+
+// encodingSuggestion returns how to fix a value that failed to decode.
+func encodingSuggestion(encoding string) string {
+	switch encoding {
+	case "base64":
+		return "Re-encode the value as standard base64 (e.g. base64 -w0 < file)"
+	case "hex":
+		return "Re-encode the value as hex with an even number of digits (e.g. xxd -p < file)"
+	default:
+		return "Re-encode the value with percent-encoding"
 	}
 }
 
@@ -80,15 +195,50 @@ func validateInt(value string, ann *parser.Annotation) error {
 		}
 	}
 
+	// Check exclusive bounds
+	if minStr := ann.GetConstraint("exclusiveMin"); minStr != "" {
+		min, err := strconv.ParseInt(minStr, 10, 64)
+		if err == nil && n <= min {
+			return fmt.Errorf("value %d must be greater than %d", n, min)
+		}
+	}
+	if maxStr := ann.GetConstraint("exclusiveMax"); maxStr != "" {
+		max, err := strconv.ParseInt(maxStr, 10, 64)
+		if err == nil && n >= max {
+			return fmt.Errorf("value %d must be less than %d", n, max)
+		}
+	}
+
+	// Check step constraint
+	if stepStr := ann.GetConstraint("step"); stepStr != "" {
+		step, err := strconv.ParseInt(stepStr, 10, 64)
+		if err == nil && step > 0 && n%step != 0 {
+			return fmt.Errorf("value %d must be a multiple of %d", n, step)
+		}
+	}
+
 	return nil
 }
 
+// ParseNumber parses a numeric value. Unlike strconv.ParseFloat it rejects
+// NaN and infinities, which are not numbers in any output format.
+func ParseNumber(value string) (float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return n, nil
+}
+
 func validateNumeric(value string, ann *parser.Annotation) error {
 	if value == "" {
 		return fmt.Errorf("value is required")
 	}
 
-	n, err := strconv.ParseFloat(value, 64)
+	n, err := ParseNumber(value)
 	if err != nil {
 		return fmt.Errorf("expected numeric, got %q", value)
 	}
@@ -109,40 +259,163 @@ func validateNumeric(value string, ann *parser.Annotation) error {
 		}
 	}
 
+	// Check exclusive bounds
+	if minStr := ann.GetConstraint("exclusiveMin"); minStr != "" {
+		min, err := strconv.ParseFloat(minStr, 64)
+		if err == nil && n <= min {
+			return fmt.Errorf("value %v must be greater than %v", n, min)
+		}
+	}
+	if maxStr := ann.GetConstraint("exclusiveMax"); maxStr != "" {
+		max, err := strconv.ParseFloat(maxStr, 64)
+		if err == nil && n >= max {
+			return fmt.Errorf("value %v must be less than %v", n, max)
+		}
+	}
+
 	return nil
 }
 
 func validateString(value string, ann *parser.Annotation) error {
+	length := valueLength(value, ann)
+
 	// Check minlen constraint
 	if minlenStr := ann.GetConstraint("minlen"); minlenStr != "" {
 		minlen, err := strconv.Atoi(minlenStr)
-		if err == nil && len(value) < minlen {
-			return fmt.Errorf("length %d is below minimum %d", len(value), minlen)
+		if err == nil && length < minlen {
+			return fmt.Errorf("length %d is below minimum %d", length, minlen)
 		}
 	}
 
 	// Check maxlen constraint
 	if maxlenStr := ann.GetConstraint("maxlen"); maxlenStr != "" {
 		maxlen, err := strconv.Atoi(maxlenStr)
-		if err == nil && len(value) > maxlen {
-			return fmt.Errorf("length %d exceeds maximum %d", len(value), maxlen)
+		if err == nil && length > maxlen {
+			return fmt.Errorf("length %d exceeds maximum %d", length, maxlen)
 		}
 	}
 
 	// Check pattern constraint
 	if pattern := ann.GetConstraint("pattern"); pattern != "" {
-		re, err := regexp.Compile(pattern)
+		re, err := compilePattern(pattern)
 		if err != nil {
 			return fmt.Errorf("invalid pattern: %v", err)
 		}
 		if !re.MatchString(value) {
+			if desc := ann.GetConstraint("pattern-desc"); desc != "" {
+				return fmt.Errorf("value %q does not match: %s", value, desc)
+			}
 			return fmt.Errorf("value %q does not match pattern %s", value, pattern)
 		}
 	}
 
+	// Check entropy constraint
+	if ann.HasConstraint("entropy") {
+		return validateEntropy(value, ann)
+	}
+
 	return nil
 }
 
+// validateEntropy checks that a value's estimated strength reaches the
+// entropy constraint, in bits.
+func validateEntropy(value string, ann *parser.Annotation) error {
+	need, err := strconv.Atoi(ann.GetConstraint("entropy"))
+	if err != nil || need < 0 {
+		return fmt.Errorf("invalid entropy constraint %q: expected a whole number of bits", ann.GetConstraint("entropy"))
+	}
+	bits := estimateEntropy(value)
+	if bits < float64(need) {
+		label := "value"
+		if ann.IsSecret {
+			label = "secret"
+		}
+		return fmt.Errorf("%s too weak: ~%d bits, need %d", label, int(bits), need)
+	}
+	return nil
+}
+
+// estimateEntropy estimates the strength of a value in bits as its length
+// times log2 of the size of the character classes it uses: lowercase,
+// uppercase, digits, ASCII symbols and anything else. A value made of few
+// different characters counts as drawn from at most the square of that
+// number, so "aaaa..." scores nothing. It assumes random characters, so it
+// still overestimates words and longer repeated patterns.
+func estimateEntropy(value string) float64 {
+	var lower, upper, digit, symbol, other bool
+	distinct := make(map[rune]bool)
+	for _, r := range value {
+		distinct[r] = true
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r >= ' ' && r <= '~':
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	if lower {
+		pool += 26
+	}
+	if upper {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if symbol {
+		pool += 33
+	}
+	if other {
+		pool += 128
+	}
+	pool = min(pool, len(distinct)*len(distinct))
+	if pool < 2 {
+		return 0
+	}
+	return float64(utf8.RuneCountInString(value)) * math.Log2(float64(pool))
+}
+
+// patternCache holds the result of compiling each pattern constraint, keyed
+// by pattern, so large files do not recompile the same pattern for every
+// value. Invalid patterns are cached too. Safe for concurrent use.
+var patternCache sync.Map // string -> compiledPattern
+
+// compiledPattern is a cached regexp.Compile result.
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// compilePattern compiles a pattern constraint, reusing earlier compilations.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		c := cached.(compiledPattern)
+		return c.re, c.err
+	}
+
+	re, err := regexp.Compile(pattern)
+	cached, _ := patternCache.LoadOrStore(pattern, compiledPattern{re: re, err: err})
+	c := cached.(compiledPattern)
+	return c.re, c.err
+}
+
+// valueLength returns the length used by minlen/maxlen: Unicode runes by
+// default, or bytes when the lenmode constraint is "bytes".
+func valueLength(value string, ann *parser.Annotation) int {
+	if ann.GetConstraint("lenmode") == "bytes" {
+		return len(value)
+	}
+	return utf8.RuneCountInString(value)
+}
+
 func validateEnum(value string, ann *parser.Annotation) error {
 	if value == "" {
 		return fmt.Errorf("value is required for enum")
@@ -153,16 +426,97 @@ func validateEnum(value string, ann *parser.Annotation) error {
 		return fmt.Errorf("enum has no options defined")
 	}
 
-	options := strings.Split(optionsStr, ",")
-	for _, opt := range options {
-		if strings.TrimSpace(opt) == value {
-			return nil
-		}
+	if _, ok := matchEnumOption(value, ann); ok {
+		return nil
 	}
 
 	return fmt.Errorf("value %q not in allowed options: %s", value, optionsStr)
 }
 
+// matchEnumOption returns the option matching value, ignoring case when the
+// annotation has the nocase modifier.
+func matchEnumOption(value string, ann *parser.Annotation) (string, bool) {
+	for _, opt := range strings.Split(ann.GetConstraint("options"), ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == value || (ann.NoCase && strings.EqualFold(opt, value)) {
+			return opt, true
+		}
+	}
+	return "", false
+}
+
+// NormalizeValue returns value in its canonical form for the annotation:
+// nocase enum values are replaced by the matching option as written in the
+// distributable. Other values are returned unchanged.
+func NormalizeValue(value string, ann *parser.Annotation) string {
+	if ann == nil || ann.Type != parser.TypeEnum || !ann.NoCase {
+		return value
+	}
+	if opt, ok := matchEnumOption(value, ann); ok {
+		return opt
+	}
+	return value
+}
+
+// trueBooleans lists the accepted boolean values that mean true (lowercase).
+var trueBooleans = map[string]bool{
+	"true": true,
+	"yes":  true,
+	"1":    true,
+	"on":   true,
+}
+
+// CanonicalBoolean returns "true" or "false" for any accepted boolean value.
+// Reports false if value is not a valid boolean.
+func CanonicalBoolean(value string) (string, bool) {
+	lower := strings.ToLower(value)
+	if !validBooleans[lower] {
+		return "", false
+	}
+	return strconv.FormatBool(trueBooleans[lower]), true
+}
+
+// Boolean output styles for the output constraint.
+const (
+	BooleanOutputTrueFalse = "truefalse" // true/false, the default
+	BooleanOutputYesNo     = "yesno"     // yes/no
+	BooleanOutputOneZero   = "10"        // 1/0
+)
+
+// IsValidBooleanOutput reports whether style is a known boolean output style.
+func IsValidBooleanOutput(style string) bool {
+	switch style {
+	case BooleanOutputTrueFalse, BooleanOutputYesNo, BooleanOutputOneZero:
+		return true
+	}
+	return false
+}
+
+// FormatBoolean writes any accepted boolean value in the given output style;
+// an empty or unknown style gives true/false. Reports false if value is not
+// a valid boolean.
+func FormatBoolean(value, style string) (string, bool) {
+	canonical, ok := CanonicalBoolean(value)
+	if !ok {
+		return "", false
+	}
+
+	isTrue := canonical == "true"
+	switch style {
+	case BooleanOutputYesNo:
+		if isTrue {
+			return "yes", true
+		}
+		return "no", true
+	case BooleanOutputOneZero:
+		if isTrue {
+			return "1", true
+		}
+		return "0", true
+	}
+	return canonical, true
+}
+
 func validateBoolean(value string) error {
 	if value == "" {
 		return fmt.Errorf("value is required for boolean")
@@ -191,11 +545,21 @@ func validateObject(value string, ann *parser.Annotation) error {
 		if err := json.Unmarshal([]byte(value), &js); err != nil {
 			return fmt.Errorf("invalid JSON: %v", err)
 		}
+		if ann.HasConstraint("schema") {
+			return validateSchema(js, ann)
+		}
 	case "yaml":
 		var yml interface{}
 		if err := yaml.Unmarshal([]byte(value), &yml); err != nil {
 			return fmt.Errorf("invalid YAML: %v", err)
 		}
+		if ann.HasConstraint("schema") {
+			js, err := jsonDocument(yml)
+			if err != nil {
+				return fmt.Errorf("invalid YAML: %v", err)
+			}
+			return validateSchema(js, ann)
+		}
 	default:
 		return fmt.Errorf("unknown object format: %s", format)
 	}
@@ -203,6 +567,278 @@ func validateObject(value string, ann *parser.Annotation) error {
 	return nil
 }
 
+func validateURL(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for url")
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %v", value, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: must include a scheme and host", value)
+	}
+
+	// Check schemes constraint
+	if schemesStr := ann.GetConstraint("schemes"); schemesStr != "" {
+		for _, scheme := range strings.Split(schemesStr, ",") {
+			if strings.EqualFold(strings.TrimSpace(scheme), u.Scheme) {
+				return nil
+			}
+		}
+		return fmt.Errorf("URL scheme %q not in allowed schemes: %s", u.Scheme, schemesStr)
+	}
+
+	return nil
+}
+
+func validateDuration(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for duration")
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("expected duration like 30s or 5m, got %q", value)
+	}
+
+	// Check min constraint
+	if minStr := ann.GetConstraint("min"); minStr != "" {
+		min, err := time.ParseDuration(minStr)
+		if err == nil && d < min {
+			return fmt.Errorf("duration %s is below minimum %s", d, min)
+		}
+	}
+
+	// Check max constraint
+	if maxStr := ann.GetConstraint("max"); maxStr != "" {
+		max, err := time.ParseDuration(maxStr)
+		if err == nil && d > max {
+			return fmt.Errorf("duration %s exceeds maximum %s", d, max)
+		}
+	}
+
+	return nil
+}
+
+func validateIP(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for ip")
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", value)
+	}
+
+	return checkIPVersion(ip, ann)
+}
+
+func validateCIDR(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for cidr")
+	}
+
+	ip, _, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q, expected address/prefix like 10.0.0.0/8", value)
+	}
+
+	return checkIPVersion(ip, ann)
+}
+
+// hostnameLabelPattern matches a single RFC 1123 hostname label.
+var hostnameLabelPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?$`)
+
+// validateHostname checks an RFC 1123 hostname: dot-separated labels of
+// letters, digits and hyphens, at most 63 characters each and 253 in total,
+// not starting or ending with a hyphen. A single trailing dot is allowed.
+// IP addresses are rejected unless allowip:true.
+func validateHostname(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for hostname")
+	}
+
+	if net.ParseIP(value) != nil {
+		if ann.GetConstraint("allowip") == "true" {
+			return nil
+		}
+		return fmt.Errorf("expected a hostname, got IP address %q", value)
+	}
+
+	name := strings.TrimSuffix(value, ".")
+	if len(name) > 253 {
+		return fmt.Errorf("hostname %q is %d characters long, maximum is 253", value, len(name))
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return fmt.Errorf("invalid hostname %q: empty label", value)
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("invalid hostname %q: label %q is longer than 63 characters", value, label)
+		}
+		if !hostnameLabelPattern.MatchString(label) {
+			return fmt.Errorf("invalid hostname %q: label %q must contain only letters, digits and hyphens, and not start or end with a hyphen", value, label)
+		}
+	}
+
+	return nil
+}
+
+// DefaultDateLayout is the time layout used for dates without a layout
+// constraint: an ISO 8601 calendar date such as 2024-01-31.
+const DefaultDateLayout = "2006-01-02"
+
+// DateLayout returns the Go time layout dates are parsed with.
+func DateLayout(ann *parser.Annotation) string {
+	if layout := ann.GetConstraint("layout"); layout != "" {
+		return layout
+	}
+	return DefaultDateLayout
+}
+
+// validateDate parses a date with the annotation's layout. The after and
+// before bounds are parsed with the same layout and are exclusive; a bound
+// that does not parse is an error in the annotation.
+func validateDate(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for date")
+	}
+
+	layout := DateLayout(ann)
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return fmt.Errorf("expected a date in layout %s, got %q", layout, value)
+	}
+
+	if afterStr := ann.GetConstraint("after"); afterStr != "" {
+		after, err := time.Parse(layout, afterStr)
+		if err != nil {
+			return fmt.Errorf("invalid after bound %q: expected a date in layout %s", afterStr, layout)
+		}
+		if !t.After(after) {
+			return fmt.Errorf("date %s must be after %s", value, afterStr)
+		}
+	}
+
+	if beforeStr := ann.GetConstraint("before"); beforeStr != "" {
+		before, err := time.Parse(layout, beforeStr)
+		if err != nil {
+			return fmt.Errorf("invalid before bound %q: expected a date in layout %s", beforeStr, layout)
+		}
+		if !t.Before(before) {
+			return fmt.Errorf("date %s must be before %s", value, beforeStr)
+		}
+	}
+
+	return nil
+}
+
+// validatePath checks a filesystem path. A leading ~ is the home directory
+// and relative paths are resolved against the distributable's directory.
+// The filesystem is only consulted with exists:true, since whether a path
+// exists depends on the machine; the kind constraint (file or dir) is
+// enforced as part of that check.
+func validatePath(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for path")
+	}
+	if strings.ContainsRune(value, 0) {
+		return fmt.Errorf("invalid path %q: contains a NUL byte", value)
+	}
+
+	path, err := expandHome(value)
+	if err != nil {
+		return fmt.Errorf("cannot expand path %q: %v", value, err)
+	}
+
+	if ann.GetConstraint("abs") == "true" && !filepath.IsAbs(path) {
+		return fmt.Errorf("path %q is not absolute", value)
+	}
+
+	if ann.GetConstraint("exists") != "true" {
+		return nil
+	}
+
+	if !filepath.IsAbs(path) && ann.BaseDir != "" {
+		path = filepath.Join(ann.BaseDir, path)
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("path %q does not exist", value)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access path %q: %v", value, err)
+	}
+
+	switch ann.GetConstraint("kind") {
+	case "file":
+		if info.IsDir() {
+			return fmt.Errorf("path %q is a directory, expected a file", value)
+		}
+	case "dir":
+		if !info.IsDir() {
+			return fmt.Errorf("path %q is not a directory", value)
+		}
+	}
+
+	return nil
+}
+
+// expandHome replaces a leading "~" or "~/" with the user's home directory.
+// "~user" forms are left alone.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// checkIPVersion enforces the version constraint: "4", "6" or "4,6".
+// Without the constraint both versions are accepted.
+func checkIPVersion(ip net.IP, ann *parser.Annotation) error {
+	version := ann.GetConstraint("version")
+	if version == "" {
+		return nil
+	}
+
+	got := "6"
+	if ip.To4() != nil {
+		got = "4"
+	}
+
+	for _, v := range strings.Split(version, ",") {
+		if strings.TrimSpace(v) == got {
+			return nil
+		}
+	}
+	return fmt.Errorf("IPv%s address not allowed, expected IPv%s", got, strings.ReplaceAll(version, ",", " or IPv"))
+}
+
+func validateEmail(value string, ann *parser.Annotation) error {
+	if value == "" {
+		return fmt.Errorf("value is required for email")
+	}
+
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q", value)
+	}
+
+	// Only a bare address is accepted unless display names are allowed
+	if addr.Address != value && ann.GetConstraint("allowName") != "true" {
+		return fmt.Errorf("invalid email address %q: expected a bare address like %s", value, addr.Address)
+	}
+
+	return nil
+}
+
 // ValidateVariable validates a single variable and returns a ValidationError if invalid.
 func ValidateVariable(v *parser.Variable) *ValidationError {
 	if v.Annotation == nil {
@@ -215,7 +851,7 @@ func ValidateVariable(v *parser.Variable) *ValidationError {
 			Variable:   v.Name,
 			LineNumber: v.LineNumber,
 			Message:    err.Error(),
-			Suggestion: GetSuggestion(v.Annotation),
+			Suggestion: GetErrorSuggestion(err, v.Annotation),
 			Example:    GetExample(v.Annotation),
 			Type:       getErrorType(err),
 		}
@@ -234,29 +870,62 @@ func ValidateEnvFile(envFile *parser.EnvFile) *ValidationResult {
 		}
 	}
 
+	for _, err := range ValidateRelations(envFile, envFile) {
+		result.AddError(err)
+	}
+
 	return result
 }
 
+// GetErrorSuggestion returns how to fix a ValidateValue error: re-encoding
+// for encoding errors, GetSuggestion otherwise.
+func GetErrorSuggestion(err error, ann *parser.Annotation) string {
+	var encErr *encodingError
+	if errors.As(err, &encErr) {
+		return encodingSuggestion(encErr.encoding)
+	}
+	return GetSuggestion(ann)
+}
+
 // GetSuggestion generates a helpful suggestion based on the annotation.
 func GetSuggestion(ann *parser.Annotation) string {
 	switch ann.Type {
 	case parser.TypeInt:
+		what := "an integer"
+		if step := ann.GetConstraint("step"); step != "" {
+			what = "a multiple of " + step
+		}
+		if HasExclusiveBounds(ann) {
+			return fmt.Sprintf("Enter %s in %s", what, FormatRange(ann))
+		}
 		if min := ann.GetConstraint("min"); min != "" {
 			if max := ann.GetConstraint("max"); max != "" {
-				return fmt.Sprintf("Enter an integer between %s and %s", min, max)
+				return fmt.Sprintf("Enter %s between %s and %s", what, min, max)
 			}
-			return fmt.Sprintf("Enter an integer >= %s", min)
+			return fmt.Sprintf("Enter %s >= %s", what, min)
 		}
 		if max := ann.GetConstraint("max"); max != "" {
-			return fmt.Sprintf("Enter an integer <= %s", max)
+			return fmt.Sprintf("Enter %s <= %s", what, max)
+		}
+		if what != "an integer" {
+			return "Enter " + what
 		}
 		return "Enter a valid integer"
 	case parser.TypeNumeric:
+		if HasExclusiveBounds(ann) {
+			return fmt.Sprintf("Enter a number in %s", FormatRange(ann))
+		}
 		return "Enter a valid number"
 	case parser.TypeString:
+		if desc := ann.GetConstraint("pattern-desc"); desc != "" {
+			return fmt.Sprintf("Enter a value that matches: %s", desc)
+		}
 		if pattern := ann.GetConstraint("pattern"); pattern != "" {
 			return fmt.Sprintf("Enter a value matching pattern: %s", pattern)
 		}
+		if entropy := ann.GetConstraint("entropy"); entropy != "" {
+			return fmt.Sprintf("Enter a random value of at least %s bits, such as a long mix of letters, digits and symbols", entropy)
+		}
 		return "Enter a valid string"
 	case parser.TypeEnum:
 		return fmt.Sprintf("Choose one of: %s", ann.GetConstraint("options"))
@@ -264,20 +933,200 @@ func GetSuggestion(ann *parser.Annotation) string {
 		return "Enter true/false, yes/no, 1/0, or on/off"
 	case parser.TypeObject:
 		return fmt.Sprintf("Enter valid %s", ann.GetConstraint("format"))
+	case parser.TypeURL:
+		if schemes := ann.GetConstraint("schemes"); schemes != "" {
+			return fmt.Sprintf("Enter a URL with scheme %s and a host", strings.ReplaceAll(schemes, ",", " or "))
+		}
+		return "Enter a full URL including scheme and host"
+	case parser.TypeEmail:
+		return "Enter a valid email address"
+	case parser.TypeDuration:
+		return "Enter a duration like 30s, 5m, 1h"
+	case parser.TypeIP:
+		return fmt.Sprintf("Enter %s address", ipVersionLabel(ann))
+	case parser.TypeCIDR:
+		return fmt.Sprintf("Enter %s network like %s", ipVersionLabel(ann), GetExample(ann))
+	case parser.TypeSemver:
+		min, max := ann.GetConstraint("min"), ann.GetConstraint("max")
+		switch {
+		case min != "" && max != "":
+			return fmt.Sprintf("Enter a semantic version between %s and %s", min, max)
+		case min != "":
+			return fmt.Sprintf("Enter a semantic version >= %s", min)
+		case max != "":
+			return fmt.Sprintf("Enter a semantic version <= %s", max)
+		}
+		return "Enter a semantic version like 1.2.3"
+	case parser.TypePath:
+		return "Enter " + pathLabel(ann)
+	case parser.TypeHostname:
+		if ann.GetConstraint("allowip") == "true" {
+			return "Enter a hostname or IP address, without scheme or port"
+		}
+		return "Enter a hostname like smtp.example.com, without scheme or port"
+	case parser.TypeDate:
+		after, before := ann.GetConstraint("after"), ann.GetConstraint("before")
+		switch {
+		case after != "" && before != "":
+			return fmt.Sprintf("Enter a date between %s and %s, like %s", after, before, GetExample(ann))
+		case after != "":
+			return fmt.Sprintf("Enter a date after %s, like %s", after, GetExample(ann))
+		case before != "":
+			return fmt.Sprintf("Enter a date before %s, like %s", before, GetExample(ann))
+		}
+		return fmt.Sprintf("Enter a date like %s", GetExample(ann))
 	default:
 		return "Enter a valid value"
 	}
 }
 
+// pathLabel describes the paths allowed by the annotation.
+func pathLabel(ann *parser.Annotation) string {
+	label := "a path"
+	if ann.GetConstraint("abs") == "true" {
+		label = "an absolute path"
+	}
+	if ann.GetConstraint("exists") == "true" {
+		switch ann.GetConstraint("kind") {
+		case "file":
+			label += " to an existing file"
+		case "dir":
+			label += " to an existing directory"
+		default:
+			label += " that exists"
+		}
+	}
+	return label
+}
+
+// ipVersionLabel describes the IP versions allowed by the annotation.
+func ipVersionLabel(ann *parser.Annotation) string {
+	switch ann.GetConstraint("version") {
+	case "4":
+		return "an IPv4"
+	case "6":
+		return "an IPv6"
+	default:
+		return "an IPv4 or IPv6"
+	}
+}
+
+// HasExclusiveBounds reports whether the annotation uses exclusiveMin or exclusiveMax.
+func HasExclusiveBounds(ann *parser.Annotation) bool {
+	return ann.HasConstraint("exclusiveMin") || ann.HasConstraint("exclusiveMax")
+}
+
+// FormatRange renders the annotation's numeric bounds in interval notation,
+// e.g. "[1, 65535]", "(0, 1)" or "(0, ∞)".
+func FormatRange(ann *parser.Annotation) string {
+	lower := "(-∞"
+	if min := ann.GetConstraint("exclusiveMin"); min != "" {
+		lower = "(" + min
+	} else if min := ann.GetConstraint("min"); min != "" {
+		lower = "[" + min
+	}
+
+	upper := "∞)"
+	if max := ann.GetConstraint("exclusiveMax"); max != "" {
+		upper = max + ")"
+	} else if max := ann.GetConstraint("max"); max != "" {
+		upper = max + "]"
+	}
+
+	return lower + ", " + upper
+}
+
+// exclusiveNumericExample picks a number inside exclusive bounds: the midpoint
+// when both ends are known, otherwise one step inside the known end.
+func exclusiveNumericExample(ann *parser.Annotation) string {
+	lower, lowerErr := strconv.ParseFloat(firstConstraint(ann, "exclusiveMin", "min"), 64)
+	upper, upperErr := strconv.ParseFloat(firstConstraint(ann, "exclusiveMax", "max"), 64)
+
+	switch {
+	case lowerErr == nil && upperErr == nil:
+		return strconv.FormatFloat((lower+upper)/2, 'g', -1, 64)
+	case lowerErr == nil:
+		return strconv.FormatFloat(lower+1, 'g', -1, 64)
+	case upperErr == nil:
+		return strconv.FormatFloat(upper-1, 'g', -1, 64)
+	default:
+		return "3.14"
+	}
+}
+
+// firstConstraint returns the value of the first constraint present in names.
+func firstConstraint(ann *parser.Annotation, names ...string) string {
+	for _, name := range names {
+		if v := ann.GetConstraint(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// intExample returns the smallest integer allowed by the lower bound and
+// step constraints, or 42 when neither is set, brought down to the upper
+// bound when it is above it.
+func intExample(ann *parser.Annotation) string {
+	n, hasLower := int64(0), false
+	if min, err := strconv.ParseInt(ann.GetConstraint("min"), 10, 64); err == nil {
+		n, hasLower = min, true
+	} else if min, err := strconv.ParseInt(ann.GetConstraint("exclusiveMin"), 10, 64); err == nil {
+		n, hasLower = min+1, true
+	}
+
+	upper, hasUpper := int64(0), false
+	if max, err := strconv.ParseInt(ann.GetConstraint("max"), 10, 64); err == nil {
+		upper, hasUpper = max, true
+	} else if max, err := strconv.ParseInt(ann.GetConstraint("exclusiveMax"), 10, 64); err == nil {
+		upper, hasUpper = max-1, true
+	}
+
+	step, err := strconv.ParseInt(ann.GetConstraint("step"), 10, 64)
+	hasStep := err == nil && step > 0
+	if !hasStep {
+		step = 1
+	}
+
+	switch {
+	case hasLower:
+		// Round up to a multiple of step
+		if r := n % step; r != 0 {
+			if n > 0 {
+				n += step - r
+			} else {
+				n -= r
+			}
+		}
+	case hasStep:
+		n = step
+	default:
+		n = 42
+	}
+
+	if hasUpper && n > upper {
+		// Round down to a multiple of step
+		n = upper
+		if r := n % step; r != 0 {
+			if n > 0 {
+				n -= r
+			} else {
+				n -= step + r
+			}
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
+
 // GetExample generates an example value based on the annotation.
 func GetExample(ann *parser.Annotation) string {
 	switch ann.Type {
 	case parser.TypeInt:
-		if min := ann.GetConstraint("min"); min != "" {
-			return min
-		}
-		return "42"
+		return intExample(ann)
 	case parser.TypeNumeric:
+		if HasExclusiveBounds(ann) {
+			return exclusiveNumericExample(ann)
+		}
 		return "3.14"
 	case parser.TypeString:
 		return "example_value"
@@ -297,6 +1146,47 @@ func GetExample(ann *parser.Annotation) string {
 			return "key: value"
 		}
 		return `{"key": "value"}`
+	case parser.TypeURL:
+		scheme := "https"
+		if schemes := ann.GetConstraint("schemes"); schemes != "" {
+			scheme = strings.TrimSpace(strings.Split(schemes, ",")[0])
+		}
+		return scheme + "://example.com"
+	case parser.TypeEmail:
+		return "user@example.com"
+	case parser.TypeDuration:
+		if min := ann.GetConstraint("min"); min != "" {
+			return min
+		}
+		return "30s"
+	case parser.TypeIP:
+		if ann.GetConstraint("version") == "6" {
+			return "::1"
+		}
+		return "192.168.1.10"
+	case parser.TypeCIDR:
+		if ann.GetConstraint("version") == "6" {
+			return "fd00::/8"
+		}
+		return "10.0.0.0/8"
+	case parser.TypeSemver:
+		return "1.2.3"
+	case parser.TypePath:
+		if ann.GetConstraint("kind") == "dir" {
+			return "/var/lib/app"
+		}
+		return "/etc/app/config.yaml"
+	case parser.TypeHostname:
+		return "smtp.example.com"
+	case parser.TypeDate:
+		layout := DateLayout(ann)
+		if after, err := time.Parse(layout, ann.GetConstraint("after")); err == nil {
+			return after.AddDate(0, 0, 1).Format(layout)
+		}
+		if before, err := time.Parse(layout, ann.GetConstraint("before")); err == nil {
+			return before.AddDate(0, 0, -1).Format(layout)
+		}
+		return time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Format(layout)
 	default:
 		return ""
 	}
@@ -304,6 +1194,12 @@ func GetExample(ann *parser.Annotation) string {
 
 // getErrorType determines the error type from the validation error message.
 func getErrorType(err error) ErrorType {
+	// Classify by the underlying message, not a custom one
+	var custom *customMessageError
+	if errors.As(err, &custom) {
+		err = custom.err
+	}
+
 	msg := err.Error()
 	if strings.Contains(msg, "required") {
 		return ErrorMissingRequired
@@ -1,7 +1,15 @@
 package validator
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -71,6 +79,10 @@ func TestValidateNumeric(t *testing.T) {
 		{"above max", "1.1", "0", "1", true},
 		{"not a number", "abc", "", "", true},
 		{"empty string", "", "", "", true},
+		{"NaN", "NaN", "", "", true},
+		{"infinity", "Inf", "", "", true},
+		{"negative infinity", "-infinity", "", "", true},
+		{"overflow", "1e400", "", "", true},
 	}
 
 	for _, tt := range tests {
@@ -113,6 +125,10 @@ func TestValidateString(t *testing.T) {
 		{"invalid pattern", "ABC123", "", "", "^[a-z0-9]+$", true},
 		{"email pattern", "test@example.com", "", "", "^[^@]+@[^@]+\\.[^@]+$", false},
 		{"invalid email", "not-an-email", "", "", "^[^@]+@[^@]+\\.[^@]+$", true},
+		{"multibyte within maxlen", "Zoë Ångström", "", "12", "", false},
+		{"multibyte above maxlen", "Zoë Ångström", "", "11", "", true},
+		{"multibyte meets minlen", "日本語", "3", "", "", false},
+		{"multibyte below minlen", "日本語", "4", "", "", true},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +154,41 @@ func TestValidateString(t *testing.T) {
 	}
 }
 
+func TestValidateString_LenMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		maxlen  string
+		lenmode string
+		wantErr bool
+	}{
+		{"runes by default", "café", "4", "", false},
+		{"explicit runes", "café", "4", "runes", false},
+		{"bytes counts multibyte", "café", "4", "bytes", true},
+		{"bytes within limit", "café", "5", "bytes", false},
+		{"bytes ascii", "cafe", "4", "bytes", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type:        parser.TypeString,
+				Constraints: []parser.Constraint{{Name: "maxlen", Value: tt.maxlen}},
+			}
+			if tt.lenmode != "" {
+				ann.Constraints = append(ann.Constraints, parser.Constraint{Name: "lenmode", Value: tt.lenmode})
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateEnum(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -174,6 +225,32 @@ func TestValidateEnum(t *testing.T) {
 	}
 }
 
+func TestFormatBoolean(t *testing.T) {
+	tests := []struct {
+		value  string
+		style  string
+		want   string
+		wantOK bool
+	}{
+		{"on", "", "true", true},
+		{"NO", BooleanOutputTrueFalse, "false", true},
+		{"1", BooleanOutputYesNo, "yes", true},
+		{"off", BooleanOutputYesNo, "no", true},
+		{"yes", BooleanOutputOneZero, "1", true},
+		{"false", BooleanOutputOneZero, "0", true},
+		{"true", "bogus", "true", true},
+		{"maybe", BooleanOutputOneZero, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+"/"+tt.style, func(t *testing.T) {
+			got, ok := FormatBoolean(tt.value, tt.style)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestValidateBoolean(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -381,3 +458,1238 @@ func TestValidationResult_FormatErrors(t *testing.T) {
 	assert.Contains(t, output, "Enter a valid port")
 	assert.Contains(t, output, "8080")
 }
+
+func TestValidationResult_MaxErrors(t *testing.T) {
+	result := NewValidationResult()
+	result.MaxErrors = 2
+	for _, name := range []string{"A", "B", "C", "D"} {
+		result.AddError(ValidationError{Variable: name, LineNumber: 1, Message: "invalid"})
+	}
+
+	assert.False(t, result.Valid)
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, 2, result.Omitted)
+	assert.Equal(t, 4, result.ErrorCount())
+
+	output := result.FormatErrors("test.env")
+	assert.Contains(t, output, "Line 1: B")
+	assert.NotContains(t, output, "Line 1: C")
+	assert.Contains(t, output, "... and 2 more")
+	assert.Contains(t, output, "Found 4 error(s)")
+
+	unlimited := NewValidationResult()
+	for _, name := range []string{"A", "B", "C"} {
+		unlimited.AddError(ValidationError{Variable: name, Message: "invalid"})
+	}
+	assert.Len(t, unlimited.Errors, 3)
+	assert.NotContains(t, unlimited.FormatErrors("test.env"), "more")
+}
+
+func TestValidationResult_Limit(t *testing.T) {
+	full := NewValidationResult()
+	for _, name := range []string{"A", "B", "C", "D"} {
+		full.AddError(ValidationError{Variable: name, LineNumber: 1, Message: "invalid"})
+	}
+	full.AddWarning(NewDuplicateVariableError("A", 3, 1))
+
+	limited := full.Limit(1)
+	assert.Len(t, full.Errors, 4, "the full result is kept")
+	assert.Len(t, limited.Errors, 1)
+	assert.Equal(t, 3, limited.Omitted)
+	assert.Equal(t, 4, limited.ErrorCount())
+	assert.False(t, limited.Valid)
+	assert.Len(t, limited.Warnings, 1)
+
+	assert.Contains(t, limited.FormatErrors("test.env"), "... and 3 more")
+	github := limited.FormatGitHub("test.env")
+	assert.Contains(t, github, "::error file=test.env::... and 3 more error(s) not listed\n")
+	assert.Contains(t, github, "title=A::A: invalid")
+	assert.NotContains(t, github, "title=B")
+	assert.Equal(t, 3, limited.JSONResult("test.env").Omitted)
+	assert.NotContains(t, full.FormatGitHub("test.env"), "more")
+
+	assert.Len(t, full.Limit(0).Errors, 4)
+}
+
+func TestValidateEncodingURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"plain value", "hello", false},
+		{"encoded space", "hello%20world", false},
+		{"lowercase hex", "a%2fb", false},
+		{"multiple escapes", "%7B%22a%22%3A1%7D", false},
+		{"empty", "", false},
+		{"stray percent", "100%", true},
+		{"single hex digit", "abc%2", true},
+		{"non-hex digits", "abc%zz", true},
+		{"percent before text", "50% off", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type: parser.TypeString,
+				Constraints: []parser.Constraint{
+					{Name: "encoding", Value: "url"},
+				},
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		schemes string
+		wantErr bool
+	}{
+		{"valid https", "https://api.example.com", "", false},
+		{"valid with path and port", "http://localhost:8080/v1", "", false},
+		{"missing scheme", "example.com", "", true},
+		{"missing host", "https://", "", true},
+		{"path only", "/api/v1", "", true},
+		{"empty", "", "", true},
+		{"allowed scheme", "https://example.com", "https", false},
+		{"disallowed scheme", "http://example.com", "https", true},
+		{"one of several schemes", "wss://example.com", "https,wss", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeURL}
+			if tt.schemes != "" {
+				ann.Constraints = append(ann.Constraints, parser.Constraint{Name: "schemes", Value: tt.schemes})
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		allowName bool
+		wantErr   bool
+	}{
+		{"valid address", "user@example.com", false, false},
+		{"valid with plus", "user+tag@mail.example.com", false, false},
+		{"missing at", "user.example.com", false, true},
+		{"missing domain", "user@", false, true},
+		{"empty", "", false, true},
+		{"list rejected", "a@example.com, b@example.com", false, true},
+		{"display name rejected", "User <user@example.com>", false, true},
+		{"angle brackets rejected", "<user@example.com>", false, true},
+		{"display name allowed", "User <user@example.com>", true, false},
+		{"list rejected with allowName", "a@example.com, b@example.com", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeEmail}
+			if tt.allowName {
+				ann.Constraints = append(ann.Constraints, parser.Constraint{Name: "allowName", Value: "true"})
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateValue_CustomErrorMessage(t *testing.T) {
+	ann, err := parser.ParseAnnotation("#prompt:Port?|int;max:65535;error:Port must be a valid TCP port")
+	require.NoError(t, err)
+
+	err = ValidateValue("70000", ann)
+	require.Error(t, err)
+	assert.Equal(t, "Port must be a valid TCP port", err.Error())
+
+	assert.NoError(t, ValidateValue("8080", ann))
+}
+
+func TestValidateVariable_CustomErrorMessage(t *testing.T) {
+	ann, err := parser.ParseAnnotation("#prompt:Port?|int;max:65535;error:Port must be a valid TCP port")
+	require.NoError(t, err)
+
+	v := &parser.Variable{Name: "PORT", Value: "70000", LineNumber: 3, Annotation: ann}
+	verr := ValidateVariable(v)
+	require.NotNil(t, verr)
+	assert.Equal(t, "Port must be a valid TCP port", verr.Message)
+	assert.Equal(t, GetSuggestion(ann), verr.Suggestion)
+	assert.Equal(t, GetExample(ann), verr.Example)
+
+	// Without a custom error the generic message is kept
+	plain, err := parser.ParseAnnotation("#prompt:Port?|int;max:65535")
+	require.NoError(t, err)
+	v.Annotation = plain
+	verr = ValidateVariable(v)
+	require.NotNil(t, verr)
+	assert.Contains(t, verr.Message, "exceeds maximum")
+}
+
+func TestValidateObject_Schema(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{
+  "type": "object",
+  "required": ["enabled"],
+  "properties": {
+    "enabled": {"type": "boolean"},
+    "rollout": {"type": "number", "minimum": 0, "maximum": 100},
+    "groups": {"type": "array", "items": {"type": "string"}}
+  },
+  "additionalProperties": false
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "flags.schema.json"), []byte(schema), 0644))
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{"valid document", `{"enabled": true, "rollout": 50, "groups": ["beta"]}`, ""},
+		{"missing required", `{"rollout": 50}`, `at /: missing required property "enabled"`},
+		{"wrong type", `{"enabled": "yes"}`, "at /enabled: expected boolean, got string"},
+		{"out of range", `{"enabled": true, "rollout": 150}`, "at /rollout: value 150 exceeds maximum 100"},
+		{"bad array item", `{"enabled": true, "groups": ["a", 2]}`, "at /groups/1: expected string, got integer"},
+		{"unexpected property", `{"enabled": true, "extra": 1}`, `unexpected property "extra"`},
+		{"invalid JSON", `{"enabled":`, "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type: parser.TypeObject,
+				Constraints: []parser.Constraint{
+					{Name: "format", Value: "json"},
+					{Name: "schema", Value: "./flags.schema.json"},
+				},
+				BaseDir: dir,
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateObject_SchemaYAML(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{"type": "object", "required": ["replicas"], "properties": {"replicas": {"type": "integer", "minimum": 1}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deploy.schema.json"), []byte(schema), 0644))
+	ann := &parser.Annotation{
+		Type: parser.TypeObject,
+		Constraints: []parser.Constraint{
+			{Name: "format", Value: "yaml"},
+			{Name: "schema", Value: "deploy.schema.json"},
+		},
+		BaseDir: dir,
+	}
+
+	assert.NoError(t, ValidateValue("replicas: 3", ann))
+	err := ValidateValue("replicas: 0", ann)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at /replicas: value 0 is below minimum 1")
+	assert.ErrorContains(t, ValidateValue("name: web", ann), `missing required property "replicas"`)
+}
+
+func TestValidateObject_SchemaUnsupportedKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"ref", `{"$ref": "#/definitions/flags"}`, `unsupported keyword "$ref" at #`},
+		{"nested", `{"properties": {"mode": {"oneOf": [{"const": "a"}]}}}`, `unsupported keyword "oneOf" at #/properties/mode`},
+		{"in allOf", `{"allOf": [{"type": "object"}, {"not": {}}]}`, `unsupported keyword "not" at #/allOf/1`},
+		{"tuple items", `{"items": [{"type": "string"}]}`, "unsupported tuple items at #"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "s.json"), []byte(tt.schema), 0644))
+			ann := &parser.Annotation{
+				Type:        parser.TypeObject,
+				Constraints: []parser.Constraint{{Name: "schema", Value: "s.json"}},
+				BaseDir:     dir,
+			}
+
+			err := ValidateValue(`{}`, ann)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateObject_SchemaAnnotationsAllowed(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Flags", "type": "object",
+  "properties": {"on": {"type": "boolean", "description": "Enabled", "default": false}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "s.json"), []byte(schema), 0644))
+	ann := &parser.Annotation{
+		Type:        parser.TypeObject,
+		Constraints: []parser.Constraint{{Name: "schema", Value: "s.json"}},
+		BaseDir:     dir,
+	}
+
+	assert.NoError(t, ValidateValue(`{"on": true}`, ann))
+}
+
+func TestValidateObject_SchemaReloadedWhenChanged(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type": "object"}`), 0644))
+	ann := &parser.Annotation{
+		Type:        parser.TypeObject,
+		Constraints: []parser.Constraint{{Name: "schema", Value: "s.json"}},
+		BaseDir:     dir,
+	}
+	assert.Error(t, ValidateValue(`[]`, ann))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"type": ["object", "array"]}`), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.NoError(t, ValidateValue(`[]`, ann))
+}
+
+func TestValidateObject_SchemaNotFound(t *testing.T) {
+	ann := &parser.Annotation{
+		Type:        parser.TypeObject,
+		Constraints: []parser.Constraint{{Name: "schema", Value: "missing.schema.json"}},
+		BaseDir:     t.TempDir(),
+	}
+
+	err := ValidateValue(`{}`, ann)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load schema")
+}
+
+func TestValidateExclusiveBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     parser.VariableType
+		value   string
+		wantErr string
+	}{
+		{"numeric inside", parser.TypeNumeric, "0.5", ""},
+		{"numeric at lower", parser.TypeNumeric, "0", "value 0 must be greater than 0"},
+		{"numeric at upper", parser.TypeNumeric, "1", "value 1 must be less than 1"},
+		{"int at lower", parser.TypeInt, "0", "value 0 must be greater than 0"},
+		{"int at upper", parser.TypeInt, "1", "value 1 must be less than 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type: tt.typ,
+				Constraints: []parser.Constraint{
+					{Name: "exclusiveMin", Value: "0"},
+					{Name: "exclusiveMax", Value: "1"},
+				},
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateInt_Step(t *testing.T) {
+	ann := &parser.Annotation{
+		Type: parser.TypeInt,
+		Constraints: []parser.Constraint{
+			{Name: "min", Value: "4"},
+			{Name: "max", Value: "64"},
+			{Name: "step", Value: "4"},
+		},
+	}
+
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"8", ""},
+		{"64", ""},
+		{"6", "value 6 must be a multiple of 4"},
+		{"0", "value 0 is below minimum 4"},
+		{"68", "value 68 exceeds maximum 64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	assert.Equal(t, "Enter a multiple of 4 between 4 and 64", GetSuggestion(ann))
+	assert.Equal(t, "4", GetExample(ann))
+}
+
+func TestGetExample_IntStep(t *testing.T) {
+	ann := &parser.Annotation{
+		Type: parser.TypeInt,
+		Constraints: []parser.Constraint{
+			{Name: "min", Value: "5"},
+			{Name: "step", Value: "4"},
+		},
+	}
+	assert.Equal(t, "8", GetExample(ann))
+}
+
+func TestGetExample_IntUpperBound(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []parser.Constraint
+		want        string
+	}{
+		{"below 42", []parser.Constraint{{Name: "max", Value: "10"}}, "10"},
+		{"exclusive max", []parser.Constraint{{Name: "exclusiveMax", Value: "10"}}, "9"},
+		{"negative max", []parser.Constraint{{Name: "max", Value: "-5"}}, "-5"},
+		{"above 42", []parser.Constraint{{Name: "max", Value: "100"}}, "42"},
+		{"step above max", []parser.Constraint{{Name: "step", Value: "50"}, {Name: "max", Value: "30"}}, "0"},
+		{"step and negative max", []parser.Constraint{{Name: "step", Value: "4"}, {Name: "max", Value: "-5"}}, "-8"},
+		{"range", []parser.Constraint{{Name: "min", Value: "1"}, {Name: "max", Value: "3"}}, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeInt, Constraints: tt.constraints}
+			assert.Equal(t, tt.want, GetExample(ann))
+			assert.NoError(t, ValidateValue(GetExample(ann), ann))
+		})
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []parser.Constraint
+		want        string
+	}{
+		{"open", []parser.Constraint{{Name: "exclusiveMin", Value: "0"}, {Name: "exclusiveMax", Value: "1"}}, "(0, 1)"},
+		{"half open", []parser.Constraint{{Name: "min", Value: "0"}, {Name: "exclusiveMax", Value: "1"}}, "[0, 1)"},
+		{"unbounded above", []parser.Constraint{{Name: "exclusiveMin", Value: "0"}}, "(0, ∞)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeNumeric, Constraints: tt.constraints}
+			assert.Equal(t, tt.want, FormatRange(ann))
+			assert.Equal(t, "Enter a number in "+tt.want, GetSuggestion(ann))
+		})
+	}
+}
+
+func TestValidateEncodingBase64Hex(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		value    string
+		wantErr  bool
+	}{
+		{"valid base64", "base64", "aGVsbG8=", false},
+		{"base64 missing padding", "base64", "aGVsbG8", true},
+		{"base64 invalid chars", "base64", "not base64!", true},
+		{"valid hex", "hex", "deadbeef", false},
+		{"hex odd length", "hex", "abc", true},
+		{"hex invalid chars", "hex", "zz", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type:        parser.TypeString,
+				Constraints: []parser.Constraint{{Name: "encoding", Value: tt.encoding}},
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid "+tt.encoding+" encoding")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		value    string
+		want     string
+	}{
+		{"base64", "base64", "aGVsbG8=", "hello"},
+		{"hex", "hex", "68656c6c6f", "hello"},
+		{"url", "url", "hello%20world", "hello world"},
+		{"no encoding", "", "hello", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeString}
+			if tt.encoding != "" {
+				ann.Constraints = []parser.Constraint{{Name: "encoding", Value: tt.encoding}}
+			}
+
+			data, err := Decode(tt.value, ann)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestValidateVariable_EncodingSuggestion(t *testing.T) {
+	v := &parser.Variable{
+		Name:  "TLS_CERT",
+		Value: "not base64!",
+		Annotation: &parser.Annotation{
+			Type:        parser.TypeString,
+			Constraints: []parser.Constraint{{Name: "encoding", Value: "base64"}},
+		},
+	}
+
+	verr := ValidateVariable(v)
+	require.NotNil(t, verr)
+	assert.Contains(t, verr.Suggestion, "Re-encode the value as standard base64")
+}
+
+func TestValidateEnum_NoCase(t *testing.T) {
+	strict, err := parser.ParseAnnotation("#prompt:Level?|enum;options:debug,info,warn,error")
+	require.NoError(t, err)
+	nocase, err := parser.ParseAnnotation("#prompt:Level?|enum;options:debug,info,warn,error;nocase")
+	require.NoError(t, err)
+	require.True(t, nocase.NoCase)
+
+	assert.Error(t, ValidateValue("INFO", strict))
+	assert.NoError(t, ValidateValue("INFO", nocase))
+	assert.NoError(t, ValidateValue("Info", nocase))
+	assert.Error(t, ValidateValue("verbose", nocase))
+
+	assert.Equal(t, "info", NormalizeValue("INFO", nocase))
+	assert.Equal(t, "INFO", NormalizeValue("INFO", strict))
+	assert.Equal(t, "verbose", NormalizeValue("verbose", nocase))
+}
+
+func TestValidateDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		min     string
+		max     string
+		wantErr bool
+	}{
+		{"seconds", "30s", "", "", false},
+		{"compound", "1h30m", "", "", false},
+		{"empty", "", "", "", true},
+		{"bare number", "30", "", "", true},
+		{"invalid unit", "5 minutes", "", "", true},
+		{"within range", "5m", "1s", "1h", false},
+		{"below min", "500ms", "1s", "", true},
+		{"above max", "2h", "", "1h", true},
+		{"at max", "1h", "", "60m", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeDuration}
+			if tt.min != "" {
+				ann.Constraints = append(ann.Constraints, parser.Constraint{Name: "min", Value: tt.min})
+			}
+			if tt.max != "" {
+				ann.Constraints = append(ann.Constraints, parser.Constraint{Name: "max", Value: tt.max})
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     parser.VariableType
+		value   string
+		version string
+		wantErr bool
+	}{
+		{"ipv4", parser.TypeIP, "192.168.1.10", "", false},
+		{"ipv6", parser.TypeIP, "::1", "", false},
+		{"hostname", parser.TypeIP, "localhost", "", true},
+		{"out of range octet", parser.TypeIP, "256.1.1.1", "", true},
+		{"empty", parser.TypeIP, "", "", true},
+		{"ipv4 only", parser.TypeIP, "10.0.0.1", "4", false},
+		{"ipv6 rejected", parser.TypeIP, "fe80::1", "4", true},
+		{"ipv4 rejected", parser.TypeIP, "10.0.0.1", "6", true},
+		{"both versions", parser.TypeIP, "fe80::1", "4,6", false},
+		{"cidr ipv4", parser.TypeCIDR, "10.0.0.0/8", "", false},
+		{"cidr ipv6", parser.TypeCIDR, "fd00::/8", "", false},
+		{"cidr without prefix", parser.TypeCIDR, "10.0.0.0", "", true},
+		{"cidr bad prefix", parser.TypeCIDR, "10.0.0.0/33", "", true},
+		{"cidr version", parser.TypeCIDR, "fd00::/8", "4", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: tt.typ}
+			if tt.version != "" {
+				ann.Constraints = []parser.Constraint{{Name: "version", Value: tt.version}}
+			}
+
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateIP_VersionMessage(t *testing.T) {
+	ann := &parser.Annotation{
+		Type:        parser.TypeIP,
+		Constraints: []parser.Constraint{{Name: "version", Value: "4"}},
+	}
+
+	err := ValidateValue("::1", ann)
+	require.Error(t, err)
+	assert.Equal(t, "IPv6 address not allowed, expected IPv4", err.Error())
+	assert.Equal(t, "Enter an IPv4 address", GetSuggestion(ann))
+	assert.NoError(t, ValidateValue(GetExample(ann), ann))
+}
+
+func TestValidateSemver(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		constraints []parser.Constraint
+		wantErr     bool
+	}{
+		{"release", "18.17.0", nil, false},
+		{"prerelease and build", "1.0.0-rc.1+build.5", nil, false},
+		{"missing patch", "18.17", nil, true},
+		{"leading zero", "01.2.3", nil, true},
+		{"v prefix rejected", "v1.2.3", nil, true},
+		{"v prefix allowed", "v1.2.3", []parser.Constraint{{Name: "vprefix", Value: "true"}}, false},
+		{"v prefix optional", "1.2.3", []parser.Constraint{{Name: "vprefix", Value: "true"}}, false},
+		{"within range", "18.17.0", []parser.Constraint{{Name: "min", Value: "18.0.0"}, {Name: "max", Value: "20.0.0"}}, false},
+		{"below min", "16.20.2", []parser.Constraint{{Name: "min", Value: "18.0.0"}}, true},
+		{"prerelease below release", "18.0.0-rc.1", []parser.Constraint{{Name: "min", Value: "18.0.0"}}, true},
+		{"above max", "20.0.1", []parser.Constraint{{Name: "max", Value: "v20.0.0"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeSemver, Constraints: tt.constraints}
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(file, []byte("cert"), 0600))
+	missing := filepath.Join(dir, "missing.pem")
+
+	exists := parser.Constraint{Name: "exists", Value: "true"}
+	tests := []struct {
+		name        string
+		value       string
+		constraints []parser.Constraint
+		wantErr     bool
+	}{
+		{"relative", "./data", nil, false},
+		{"missing not checked by default", missing, nil, false},
+		{"absolute required", "./data", []parser.Constraint{{Name: "abs", Value: "true"}}, true},
+		{"absolute", file, []parser.Constraint{{Name: "abs", Value: "true"}}, false},
+		{"exists", file, []parser.Constraint{exists}, false},
+		{"does not exist", missing, []parser.Constraint{exists}, true},
+		{"file kind", file, []parser.Constraint{exists, {Name: "kind", Value: "file"}}, false},
+		{"dir is not a file", dir, []parser.Constraint{exists, {Name: "kind", Value: "file"}}, true},
+		{"dir kind", dir, []parser.Constraint{exists, {Name: "kind", Value: "dir"}}, false},
+		{"file is not a dir", file, []parser.Constraint{exists, {Name: "kind", Value: "dir"}}, true},
+		{"kind without exists", file, []parser.Constraint{{Name: "kind", Value: "dir"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypePath, Constraints: tt.constraints}
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePath_Resolution(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "key.pem"), []byte("key"), 0600))
+	base := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(base, "certs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "certs", "cert.pem"), []byte("cert"), 0600))
+
+	exists := parser.Constraint{Name: "exists", Value: "true"}
+	tests := []struct {
+		name        string
+		value       string
+		constraints []parser.Constraint
+		wantErr     bool
+	}{
+		{"relative to the distributable", "certs/cert.pem", []parser.Constraint{exists}, false},
+		{"dot relative", "./certs", []parser.Constraint{exists, {Name: "kind", Value: "dir"}}, false},
+		{"relative missing", "certs/missing.pem", []parser.Constraint{exists}, true},
+		{"home", "~/key.pem", []parser.Constraint{exists}, false},
+		{"home is absolute", "~/key.pem", []parser.Constraint{{Name: "abs", Value: "true"}}, false},
+		{"home missing", "~/missing.pem", []parser.Constraint{exists}, true},
+		{"other user not expanded", "~nobody/key.pem", []parser.Constraint{exists}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypePath, Constraints: tt.constraints, BaseDir: base}
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	allowIP := []parser.Constraint{{Name: "allowip", Value: "true"}}
+	tests := []struct {
+		name        string
+		value       string
+		constraints []parser.Constraint
+		wantErr     bool
+	}{
+		{"single label", "localhost", nil, false},
+		{"fqdn", "smtp.example.com", nil, false},
+		{"trailing dot", "smtp.example.com.", nil, false},
+		{"leading digit", "1password.com", nil, false},
+		{"inner hyphen", "my-host.example.com", nil, false},
+		{"leading hyphen", "-host.example.com", nil, true},
+		{"trailing hyphen", "host-.example.com", nil, true},
+		{"underscore", "my_host", nil, true},
+		{"empty label", "a..b", nil, true},
+		{"url", "https://example.com", nil, true},
+		{"with port", "example.com:25", nil, true},
+		{"label of 63", strings.Repeat("a", 63) + ".com", nil, false},
+		{"label of 64", strings.Repeat("a", 64) + ".com", nil, true},
+		{"253 characters", strings.Repeat(strings.Repeat("a", 49)+".", 5) + "com", nil, false},
+		{"254 characters", strings.Repeat(strings.Repeat("a", 49)+".", 5) + "comm", nil, true},
+		{"ipv4 rejected", "10.0.0.1", nil, true},
+		{"ipv4 allowed", "10.0.0.1", allowIP, false},
+		{"ipv6 allowed", "::1", allowIP, false},
+		{"hostname with allowip", "example.com", allowIP, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeHostname, Constraints: tt.constraints}
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDate(t *testing.T) {
+	euLayout := []parser.Constraint{{Name: "layout", Value: "02/01/2006"}}
+	bounded := []parser.Constraint{
+		{Name: "after", Value: "2024-01-01"},
+		{Name: "before", Value: "2024-12-31"},
+	}
+	tests := []struct {
+		name        string
+		value       string
+		constraints []parser.Constraint
+		wantErr     bool
+	}{
+		{"iso date", "2024-01-31", nil, false},
+		{"leap day", "2024-02-29", nil, false},
+		{"no leap day", "2023-02-29", nil, true},
+		{"month out of range", "2024-13-01", nil, true},
+		{"timestamp", "2024-01-31T10:00:00Z", nil, true},
+		{"custom layout", "31/01/2024", euLayout, false},
+		{"iso with custom layout", "2024-01-31", euLayout, true},
+		{"in range", "2024-06-15", bounded, false},
+		{"on after bound", "2024-01-01", bounded, true},
+		{"before range", "2023-12-31", bounded, true},
+		{"on before bound", "2024-12-31", bounded, true},
+		{"after range", "2025-01-01", bounded, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := &parser.Annotation{Type: parser.TypeDate, Constraints: tt.constraints}
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDate_UnparsableBounds(t *testing.T) {
+	for _, bound := range []string{"after", "before"} {
+		t.Run(bound, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type: parser.TypeDate,
+				Constraints: []parser.Constraint{
+					{Name: "layout", Value: "02/01/2006"},
+					{Name: bound, Value: "2024-01-01"},
+				},
+			}
+
+			err := ValidateValue("15/06/2024", ann)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid "+bound+" bound")
+		})
+	}
+}
+
+func TestValidateDate_ErrorNamesLayout(t *testing.T) {
+	ann := &parser.Annotation{
+		Type:        parser.TypeDate,
+		Constraints: []parser.Constraint{{Name: "layout", Value: "02/01/2006"}},
+	}
+
+	err := ValidateValue("2024-01-31", ann)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "02/01/2006")
+	assert.NoError(t, ValidateValue(GetExample(ann), ann))
+}
+
+func TestValidateString_Entropy(t *testing.T) {
+	ann := &parser.Annotation{
+		Type:     parser.TypeString,
+		IsSecret: true,
+		Constraints: []parser.Constraint{
+			{Name: "minlen", Value: "32"},
+			{Name: "entropy", Value: "128"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{"random mixed", "q8V#r2Lk!9zP$w4Ne7Tb@1Xc6Hm0Js3D", ""},
+		{"lowercase", strings.Repeat("abcdefgh", 4), ""},
+		{"40 digits", strings.Repeat("0123456789", 4), ""},
+		{"32 digits", strings.Repeat("0123456789", 3) + "01", "secret too weak: ~106 bits, need 128"},
+		{"one repeated character", strings.Repeat("a", 32), "secret too weak: ~0 bits, need 128"},
+		{"two alternating characters", strings.Repeat("aB", 20), "secret too weak: ~80 bits, need 128"},
+		{"too short", "aB3$", "length 4 is below minimum 32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(tt.value, ann)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestEstimateEntropy(t *testing.T) {
+	assert.Equal(t, 0.0, estimateEntropy(""))
+	assert.InDelta(t, 40*math.Log2(10), estimateEntropy(strings.Repeat("0123456789", 4)), 0.001)
+	assert.InDelta(t, 4*math.Log2(16), estimateEntropy("aB3$"), 0.001)
+	assert.InDelta(t, 10*math.Log2(26+26+10+33), estimateEntropy("aB3$cD4%eF"), 0.001)
+	assert.Equal(t, 0.0, estimateEntropy(strings.Repeat("a", 32)))
+}
+
+func TestValidateString_MalformedEntropy(t *testing.T) {
+	for _, entropy := range []string{"lots", "12.5", "-1"} {
+		t.Run(entropy, func(t *testing.T) {
+			ann := &parser.Annotation{
+				Type:        parser.TypeString,
+				Constraints: []parser.Constraint{{Name: "entropy", Value: entropy}},
+			}
+
+			err := ValidateValue("q8V#r2Lk!9zP$w4Ne7Tb@1Xc6Hm0Js3D", ann)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid entropy constraint")
+		})
+	}
+}
+
+func TestValidateString_PatternDesc(t *testing.T) {
+	ann := &parser.Annotation{
+		Type: parser.TypeString,
+		Constraints: []parser.Constraint{
+			{Name: "pattern", Value: `^v\d+\.\d+\.\d+$`},
+			{Name: "pattern-desc", Value: "must look like v1.2.3"},
+		},
+	}
+
+	assert.NoError(t, ValidateValue("v1.2.3", ann))
+
+	err := ValidateValue("1.2", ann)
+	require.Error(t, err)
+	assert.Equal(t, `value "1.2" does not match: must look like v1.2.3`, err.Error())
+	assert.Equal(t, ErrorConstraintViolation, getErrorType(err))
+	assert.Equal(t, "Enter a value that matches: must look like v1.2.3", GetSuggestion(ann))
+}
+
+func TestCompilePattern_Cached(t *testing.T) {
+	first, err := compilePattern(`^[a-z]+$`)
+	require.NoError(t, err)
+	second, err := compilePattern(`^[a-z]+$`)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = compilePattern(`[`)
+	assert.Error(t, err)
+	cached, ok := patternCache.Load(`[`)
+	require.True(t, ok, "invalid patterns are cached")
+	assert.Equal(t, err, cached.(compiledPattern).err)
+}
+
+func TestCompilePattern_Concurrent(t *testing.T) {
+	ann := &parser.Annotation{
+		Type:        parser.TypeString,
+		Constraints: []parser.Constraint{{Name: "pattern", Value: `^concurrent-[0-9]+$`}},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- ValidateValue(fmt.Sprintf("concurrent-%d", i), ann)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func BenchmarkValidateValue_Pattern(b *testing.B) {
+	ann := &parser.Annotation{
+		Type:        parser.TypeString,
+		Constraints: []parser.Constraint{{Name: "pattern", Value: `^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`}},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ValidateValue("v1.2.3-rc.1", ann)
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// Precedence example from the Semantic Versioning 2.0.0 specification
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0",
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, ok := parseSemver(ordered[i-1])
+		require.True(t, ok)
+		b, ok := parseSemver(ordered[i])
+		require.True(t, ok)
+		assert.Equal(t, -1, a.compare(b), "%s < %s", ordered[i-1], ordered[i])
+		assert.Equal(t, 1, b.compare(a), "%s > %s", ordered[i], ordered[i-1])
+	}
+
+	a, _ := parseSemver("1.2.3+build.1")
+	b, _ := parseSemver("1.2.3+build.2")
+	assert.Equal(t, 0, a.compare(b))
+}
+
+func TestValidationResult_Warnings(t *testing.T) {
+	result := NewValidationResult()
+	result.AddWarning(NewDuplicateVariableError("DB_HOST", 5, 2))
+
+	assert.True(t, result.Valid)
+	assert.Zero(t, result.ErrorCount())
+
+	output := result.FormatErrors(".env.local")
+	assert.Contains(t, output, "✓ VALIDATION PASSED: .env.local")
+	assert.Contains(t, output, "Line 5: DB_HOST")
+	assert.Contains(t, output, "⚠ Variable already defined on line 2")
+	assert.Contains(t, output, "Found 1 warning(s)")
+}
+
+func TestValidateRelations_Requires(t *testing.T) {
+	dist, err := parser.ParseEnvFileContent(`TLS_CERT= #prompt:Cert?|string;optional;requires:TLS_KEY,TLS_CA
+TLS_KEY= #prompt:Key?|string;optional
+TLS_CA= #prompt:CA?|string;optional
+`, ".env.dist")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		target  string
+		missing []string
+	}{
+		{"all set", "TLS_CERT=c\nTLS_KEY=k\nTLS_CA=a\n", nil},
+		{"nothing set", "TLS_CERT=\n", nil},
+		{"dependency empty", "TLS_CERT=c\nTLS_KEY=\nTLS_CA=a\n", []string{"TLS_KEY"}},
+		{"dependencies absent", "TLS_CERT=c\n", []string{"TLS_KEY", "TLS_CA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := parser.ParseEnvFileContent(tt.target, ".env.local")
+			require.NoError(t, err)
+
+			errs := ValidateRelations(dist, target)
+			require.Len(t, errs, len(tt.missing))
+			for i, dep := range tt.missing {
+				assert.Equal(t, "TLS_CERT", errs[i].Variable)
+				assert.Equal(t, 1, errs[i].LineNumber)
+				assert.Equal(t, "TLS_CERT requires "+dep+" to be set", errs[i].Message)
+			}
+		})
+	}
+}
+
+func TestValidateRelations_Conflicts(t *testing.T) {
+	dist, err := parser.ParseEnvFileContent(`AUTH_TOKEN= #prompt:Token?|string;optional;conflicts:AUTH_USER,AUTH_PASS
+AUTH_USER= #prompt:User?|string;optional;conflicts:AUTH_TOKEN
+AUTH_PASS= #prompt:Password?|string;optional
+`, ".env.dist")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		target   string
+		messages []string
+	}{
+		{"token only", "AUTH_TOKEN=t\n", nil},
+		{"credentials only", "AUTH_USER=u\nAUTH_PASS=p\n", nil},
+		{"token and empty user", "AUTH_TOKEN=t\nAUTH_USER=\n", nil},
+		{"token and user", "AUTH_TOKEN=t\nAUTH_USER=u\n", []string{
+			"AUTH_TOKEN (line 1) conflicts with AUTH_USER (line 2); only one may be set",
+		}},
+		{"token and credentials", "AUTH_USER=u\nAUTH_PASS=p\nAUTH_TOKEN=t\n", []string{
+			"AUTH_TOKEN (line 3) conflicts with AUTH_USER (line 1); only one may be set",
+			"AUTH_TOKEN (line 3) conflicts with AUTH_PASS (line 2); only one may be set",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := parser.ParseEnvFileContent(tt.target, ".env.local")
+			require.NoError(t, err)
+
+			errs := ValidateRelations(dist, target)
+			require.Len(t, errs, len(tt.messages))
+			for i, msg := range tt.messages {
+				assert.Equal(t, "AUTH_TOKEN", errs[i].Variable)
+				assert.Equal(t, ErrorConstraintViolation, errs[i].Type)
+				assert.Equal(t, msg, errs[i].Message)
+			}
+		})
+	}
+}
+
+func TestValidationResult_MachineFormats(t *testing.T) {
+	result := NewValidationResult()
+	result.AddError(NewMissingRequiredError("API_KEY", 0, "API key?"))
+	result.AddError(ValidationError{
+		Variable:   "DB_PORT",
+		LineNumber: 3,
+		Message:    "expected integer, got \"abc\"",
+		Suggestion: "Enter a whole number",
+		Type:       ErrorInvalidType,
+	})
+	result.AddWarning(NewDuplicateVariableError("DB_HOST", 5, 2))
+
+	errs := result.JSONErrors(".env.local")
+	require.Len(t, errs, 2)
+	assert.Equal(t, JSONError{
+		File:       ".env.local",
+		Variable:   "DB_PORT",
+		Line:       3,
+		Type:       "invalid_type",
+		Message:    "expected integer, got \"abc\"",
+		Suggestion: "Enter a whole number",
+	}, errs[1])
+	assert.Equal(t, "missing_required", errs[0].Type)
+
+	lines := strings.Split(strings.TrimSuffix(result.FormatGitHub("config/.env,local"), "\n"), "\n")
+	assert.Equal(t, []string{
+		"::error file=config/.env%2Clocal,title=API_KEY::API_KEY: Required variable has no value%0ASet a value for API_KEY",
+		"::error file=config/.env%2Clocal,line=3,title=DB_PORT::DB_PORT: expected integer, got \"abc\"%0AEnter a whole number",
+		"::warning file=config/.env%2Clocal,line=5,title=DB_HOST::DB_HOST: Variable already defined on line 2%0ARemove duplicate definition",
+	}, lines)
+}
+
+func TestValidationResult_JSONResult(t *testing.T) {
+	result := NewValidationResult()
+	result.AddError(ValidationError{
+		Variable:   "DB_PORT",
+		LineNumber: 3,
+		Message:    "expected integer, got \"abc\"",
+		Suggestion: "Enter a whole number",
+		Example:    "5432",
+		Type:       ErrorInvalidType,
+	})
+	result.AddWarning(NewDuplicateVariableError("DB_HOST", 5, 2))
+
+	output, err := json.Marshal(result.JSONResult(".env.local"))
+	require.NoError(t, err)
+
+	var report struct {
+		File       string `json:"file"`
+		Valid      bool   `json:"valid"`
+		ErrorCount int    `json:"errorCount"`
+		Errors     []struct {
+			Variable   string `json:"variable"`
+			Line       int    `json:"line"`
+			Type       string `json:"type"`
+			Message    string `json:"message"`
+			Suggestion string `json:"suggestion"`
+			Example    string `json:"example"`
+		} `json:"errors"`
+		Warnings []struct {
+			Variable string `json:"variable"`
+			Type     string `json:"type"`
+		} `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal(output, &report))
+
+	assert.Equal(t, ".env.local", report.File)
+	assert.False(t, report.Valid)
+	assert.Equal(t, 1, report.ErrorCount)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "DB_PORT", report.Errors[0].Variable)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Equal(t, "invalid_type", report.Errors[0].Type)
+	assert.Equal(t, "expected integer, got \"abc\"", report.Errors[0].Message)
+	assert.Equal(t, "Enter a whole number", report.Errors[0].Suggestion)
+	assert.Equal(t, "5432", report.Errors[0].Example)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, "duplicate_variable", report.Warnings[0].Type)
+
+	output, err = json.Marshal(NewValidationResult().JSONResult(".env.local"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), `"valid":true`)
+	assert.Contains(t, string(output), `"errors":[]`)
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		value string
+		want  parser.VariableType
+	}{
+		{"", parser.TypeString},
+		{"localhost", parser.TypeString},
+		{"https://api.example.com", parser.TypeURL},
+		{"true", parser.TypeBoolean},
+		{"off", parser.TypeBoolean},
+		{"5432", parser.TypeInt},
+		{"0.75", parser.TypeNumeric},
+		{"NaN", parser.TypeString},
+		{"Inf", parser.TypeString},
+		{"192.168.1.10", parser.TypeIP},
+		{"10.0.0.0/8", parser.TypeCIDR},
+		{"/var/lib/app", parser.TypePath},
+		{"./data", parser.TypePath},
+		{"30s", parser.TypeDuration},
+		{"2024-01-31", parser.TypeDate},
+		{"2024-01-31T10:00:00Z", parser.TypeString},
+		{`{"a":1}`, parser.TypeObject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, InferType(tt.value))
+		})
+	}
+}
+
+func TestLooksSecret(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"DB_PASSWORD", true},
+		{"GITHUB_TOKEN", true},
+		{"stripe_api_key", true},
+		{"CLIENT_SECRET", true},
+		{"DB_HOST", false},
+		{"PORT", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LooksSecret(tt.name))
+		})
+	}
+}
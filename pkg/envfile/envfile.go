@@ -3,6 +3,9 @@
 package envfile
 
 import (
+	"io"
+
+	"github.com/theburrowhub/krakenv/internal/generator"
 	"github.com/theburrowhub/krakenv/internal/parser"
 	"github.com/theburrowhub/krakenv/internal/validator"
 )
@@ -24,12 +27,21 @@ type VariableType = parser.VariableType
 
 // Type constants.
 const (
-	TypeString  = parser.TypeString
-	TypeInt     = parser.TypeInt
-	TypeNumeric = parser.TypeNumeric
-	TypeBoolean = parser.TypeBoolean
-	TypeEnum    = parser.TypeEnum
-	TypeObject  = parser.TypeObject
+	TypeString   = parser.TypeString
+	TypeInt      = parser.TypeInt
+	TypeNumeric  = parser.TypeNumeric
+	TypeBoolean  = parser.TypeBoolean
+	TypeEnum     = parser.TypeEnum
+	TypeObject   = parser.TypeObject
+	TypeURL      = parser.TypeURL
+	TypeEmail    = parser.TypeEmail
+	TypeDuration = parser.TypeDuration
+	TypeIP       = parser.TypeIP
+	TypeCIDR     = parser.TypeCIDR
+	TypeSemver   = parser.TypeSemver
+	TypePath     = parser.TypePath
+	TypeHostname = parser.TypeHostname
+	TypeDate     = parser.TypeDate
 )
 
 // Parse parses an environment file from disk.
@@ -47,6 +59,12 @@ func Validate(value string, ann *Annotation) error {
 	return validator.ValidateValue(value, ann)
 }
 
+// Decode returns the raw bytes of a value according to the annotation's
+// encoding constraint (base64, hex or url).
+func Decode(value string, ann *Annotation) ([]byte, error) {
+	return validator.Decode(value, ann)
+}
+
 // ValidateFile validates all variables in an environment file against a distributable.
 func ValidateFile(distPath, targetPath string) (*ValidationResult, error) {
 	distFile, err := Parse(distPath)
@@ -108,3 +126,29 @@ func FormatAnnotation(ann *Annotation) string {
 func FormatVariable(v Variable, includeAnnotation bool) string {
 	return parser.FormatVariable(v, includeAnnotation)
 }
+
+// WriteOptions controls what Write and WriteFile include in their output.
+type WriteOptions struct {
+	IncludeAnnotations bool // Keep #prompt: annotations on variable lines
+	IncludeConfig      bool // Write the #krakenv: config block, if the file has one
+	AnnotationsAbove   bool // Write annotations on their own line above each variable instead of inline
+}
+
+// Write writes an environment file to w in the same format as `krakenv generate`.
+// Comments and blank lines recorded by the parser are kept in place.
+func Write(f *EnvFile, w io.Writer, opts WriteOptions) error {
+	return newWriter(f, "", opts).Write(w, f.Variables)
+}
+
+// WriteFile writes an environment file to path in the same format as `krakenv generate`.
+func WriteFile(f *EnvFile, path string, opts WriteOptions) error {
+	return newWriter(f, path, opts).WriteFile(f.Variables)
+}
+
+func newWriter(f *EnvFile, path string, opts WriteOptions) *generator.Generator {
+	gen := generator.NewGenerator(f, path)
+	gen.KeepAnnotations = opts.IncludeAnnotations
+	gen.OmitConfig = !opts.IncludeConfig
+	gen.AnnotationsAbove = opts.AnnotationsAbove
+	return gen
+}
@@ -3,6 +3,7 @@ package envfile
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -123,3 +124,55 @@ func TestFormatVariable(t *testing.T) {
 	assert.Contains(t, line, "PORT=8080")
 	assert.Contains(t, line, "#prompt:Port?|int")
 }
+
+func TestWriteRoundTrip(t *testing.T) {
+	content := `#krakenv:environments=local,production
+#krakenv:strict=true
+
+# Database
+DB_HOST=localhost #prompt:Host?|string
+DB_PORT=5432 #prompt:Port?|int;min:1;max:65535
+
+# Secrets
+API_KEY= #prompt:API key?|string;secret;optional
+TLS_CERT=<<EOF #prompt:Certificate?|string;encoding:heredoc
+line one
+line two
+EOF
+PLAIN=value
+`
+	tmpDir := t.TempDir()
+	srcPath := filepath.Join(tmpDir, "source.env")
+	require.NoError(t, os.WriteFile(srcPath, []byte(content), 0644))
+
+	original, err := Parse(srcPath)
+	require.NoError(t, err)
+
+	outPath := filepath.Join(tmpDir, "written.env")
+	require.NoError(t, WriteFile(original, outPath, WriteOptions{
+		IncludeAnnotations: true,
+		IncludeConfig:      true,
+	}))
+
+	written, err := Parse(outPath)
+	require.NoError(t, err)
+
+	require.Len(t, written.Variables, len(original.Variables))
+	for i, want := range original.Variables {
+		got := written.Variables[i]
+		assert.Equal(t, want.Name, got.Name)
+		assert.Equal(t, want.Value, got.Value, want.Name)
+		assert.Equal(t, want.Annotation, got.Annotation, want.Name)
+	}
+	assert.Equal(t, original.Config, written.Config)
+	assert.Equal(t, original.Comments, written.Comments)
+}
+
+func TestWrite_Options(t *testing.T) {
+	env, err := ParseContent("#krakenv:strict=true\n\nDB_HOST=localhost #prompt:Host?|string\n", "inline.env")
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, Write(env, &buf, WriteOptions{}))
+	assert.Equal(t, "DB_HOST=localhost\n", buf.String())
+}